@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -9,9 +11,12 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/config"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/database"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
 )
 
 func main() {
@@ -25,6 +30,9 @@ func main() {
 		logger.Error("Failed to load configuration", "error", err)
 		os.Exit(1)
 	}
+	if !cfg.AuthEnabled {
+		logger.Warn("Authentication is disabled (AUTH_ENABLED=false); all endpoints are publicly accessible")
+	}
 
 	db, err := database.NewPostgresDB(cfg.DatabaseURL())
 	if err != nil {
@@ -39,6 +47,15 @@ func main() {
 	}
 	logger.Info("Database migrations completed")
 
+	backfilled, err := repository.NewIndicatorRepository(db).BackfillHosts(context.Background())
+	if err != nil {
+		logger.Error("Failed to backfill indicator hosts", "error", err)
+		os.Exit(1)
+	}
+	if backfilled > 0 {
+		logger.Info("Backfilled indicator hosts", "count", backfilled)
+	}
+
 	appCache, err := cache.New(cache.Config{
 		MaxSizeMB: cfg.CacheMaxSizeMB,
 	})
@@ -47,7 +64,17 @@ func main() {
 		os.Exit(1)
 	}
 
-	server := NewServer(cfg, logger, db, appCache)
+	var tokens auth.Authenticator
+	if cfg.AuthEnabled && cfg.OIDCEnabled() {
+		tokens, err = newTokenAuthenticator(cfg)
+		if err != nil {
+			logger.Error("Failed to configure OIDC token validation", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("OIDC token validation enabled", "issuer", cfg.OIDCIssuer, "audience", cfg.OIDCAudience)
+	}
+
+	server := NewServer(cfg, logger, db, appCache, tokens)
 
 	go func() {
 		if err := server.Start(); err != nil && err != http.ErrServerClosed {
@@ -69,3 +96,51 @@ func main() {
 
 	logger.Info("Server stopped")
 }
+
+func newTokenAuthenticator(cfg *config.Config) (auth.Authenticator, error) {
+	var keys *auth.JWKS
+	switch {
+	case cfg.OIDCJWKSURL != "" && cfg.OIDCJWKSFile != "":
+		return nil, errors.New("set only one of OIDC_JWKS_URL and OIDC_JWKS_FILE")
+	case cfg.OIDCJWKSURL != "":
+		keys = auth.NewRemoteJWKS(cfg.OIDCJWKSURL, nil, cfg.OIDCJWKSRefresh)
+	case cfg.OIDCJWKSFile != "":
+		keys = auth.NewFileJWKS(cfg.OIDCJWKSFile, cfg.OIDCJWKSRefresh)
+	default:
+		return nil, errors.New("OIDC_JWKS_URL or OIDC_JWKS_FILE is required")
+	}
+
+	roleScopes, err := auth.ParseRoleScopes(cfg.OIDCRoleScopes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC_ROLE_SCOPES: %w", err)
+	}
+	defaultScopes, err := auth.ParseScopes(cfg.OIDCDefaultScopes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC_DEFAULT_SCOPES: %w", err)
+	}
+	roleClearance, err := auth.ParseRoleClearances(cfg.OIDCRoleTLP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC_ROLE_TLP: %w", err)
+	}
+	defaultClearance, ok := marking.Parse(cfg.OIDCDefaultTLP)
+	if !ok {
+		return nil, fmt.Errorf("invalid OIDC_DEFAULT_TLP: unknown TLP level %q", cfg.OIDCDefaultTLP)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := keys.Refresh(ctx); err != nil {
+		slog.Warn("Failed to load JWKS, will retry on first token", "error", err)
+	}
+
+	return auth.NewJWTAuthenticator(auth.JWTConfig{
+		Issuer:           cfg.OIDCIssuer,
+		Audience:         cfg.OIDCAudience,
+		RolesClaim:       cfg.OIDCRolesClaim,
+		TenantClaim:      cfg.OIDCTenantClaim,
+		RoleScopes:       roleScopes,
+		DefaultScopes:    defaultScopes,
+		RoleClearance:    roleClearance,
+		DefaultClearance: defaultClearance,
+	}, keys)
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"net/http"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/middleware"
 	"github.com/go-chi/chi/v5"
 )
@@ -15,20 +18,108 @@ func (s *Server) setupRoutes() *chi.Mux {
 
 	r.Get("/health", s.healthHandler.Check)
 
+	read := s.requireScope(auth.ScopeRead)
+	write := s.requireScope(auth.ScopeWrite)
+	export := s.requireScope(auth.ScopeExport)
+	admin := s.requireScope(auth.ScopeAdmin)
+
+	r.Route("/taxii2", func(r chi.Router) {
+		r.Use(s.authenticate())
+		r.Use(read)
+		r.Get("/", s.taxiiHandler.Discovery)
+		r.Get("/api/", s.taxiiHandler.APIRoot)
+		r.Get("/api/collections/", s.taxiiHandler.Collections)
+		r.Get("/api/collections/{id}/", s.taxiiHandler.Collection)
+		r.With(export).Get("/api/collections/{id}/objects/", s.taxiiHandler.Objects)
+	})
+
 	r.Route("/api", func(r chi.Router) {
+		r.Use(s.authenticate())
+
 		r.Route("/indicators", func(r chi.Router) {
-			r.Get("/search", s.indicatorHandler.Search)
-			r.Get("/{id}", s.indicatorHandler.GetByID)
+			r.With(write).Post("/", s.indicatorHandler.Create)
+			r.With(write).Post("/upsert", s.indicatorHandler.Upsert)
+			r.With(write).Post("/bulk", s.indicatorHandler.Bulk)
+			r.With(read).Get("/search", s.indicatorHandler.Search)
+			r.With(export).Get("/export", s.indicatorHandler.Export)
+			r.With(export).Get("/blocklist", s.indicatorHandler.Blocklist)
+			r.With(export).Get("/rules", s.indicatorHandler.Rules)
+			r.With(read).Get("/{id}", s.indicatorHandler.GetByID)
+			r.With(read).Get("/{id}/history", s.indicatorHandler.History)
+			r.With(write).Put("/{id}", s.indicatorHandler.Update)
+			r.With(write).Patch("/{id}", s.indicatorHandler.Patch)
+			r.With(write).Delete("/{id}", s.indicatorHandler.Delete)
+			r.With(write).Post("/{id}/revoke", s.indicatorHandler.Revoke)
+			r.With(write).Post("/{id}/restore", s.indicatorHandler.Restore)
+			r.With(write).Put("/{id}/actors/{actorId}", s.indicatorHandler.LinkActor)
+			r.With(write).Delete("/{id}/actors/{actorId}", s.indicatorHandler.UnlinkActor)
+			r.With(write).Put("/{id}/campaigns/{campaignId}", s.indicatorHandler.LinkCampaign)
+			r.With(write).Delete("/{id}/campaigns/{campaignId}", s.indicatorHandler.UnlinkCampaign)
+			r.With(write).Put("/{id}/sample/{siblingId}", s.indicatorHandler.LinkSample)
+			r.With(write).Delete("/{id}/sample", s.indicatorHandler.UnlinkSample)
 		})
 
 		r.Route("/campaigns", func(r chi.Router) {
-			r.Get("/{id}/indicators", s.campaignHandler.GetIndicators)
+			r.With(read).Get("/", s.campaignHandler.List)
+			r.With(write).Post("/", s.campaignHandler.Create)
+			r.With(read).Get("/{id}", s.campaignHandler.GetByID)
+			r.With(write).Put("/{id}", s.campaignHandler.Update)
+			r.With(write).Patch("/{id}", s.campaignHandler.Patch)
+			r.With(write).Delete("/{id}", s.campaignHandler.Delete)
+			r.With(read).Get("/{id}/indicators", s.campaignHandler.GetIndicators)
+			r.With(write).Put("/{id}/indicators/{indicatorId}", s.campaignHandler.LinkIndicator)
+			r.With(write).Delete("/{id}/indicators/{indicatorId}", s.campaignHandler.UnlinkIndicator)
+		})
+
+		r.Route("/actors", func(r chi.Router) {
+			r.With(read).Get("/", s.actorHandler.List)
+			r.With(read).Get("/{id}", s.actorHandler.GetByID)
+			r.With(read).Get("/{id}/indicators", s.actorHandler.GetIndicators)
+			r.With(write).Put("/{id}/aliases", s.actorHandler.SetAliases)
 		})
 
 		r.Route("/dashboard", func(r chi.Router) {
-			r.Get("/summary", s.dashboardHandler.GetSummary)
+			r.With(read).Get("/summary", s.dashboardHandler.GetSummary)
+		})
+
+		r.Route("/stix", func(r chi.Router) {
+			r.With(export).Get("/bundle", s.stixHandler.ExportBundle)
+			r.With(write).Post("/import", s.stixHandler.Import)
+			r.With(export).Get("/indicators/{id}", s.stixHandler.ExportIndicator)
+		})
+
+		r.Route("/misp", func(r chi.Router) {
+			r.With(write).Post("/import", s.mispHandler.Import)
+			r.With(export).Get("/events/{id}", s.mispHandler.ExportEvent)
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(admin)
+			r.Get("/api-keys", s.apiKeyHandler.List)
+			r.Post("/api-keys", s.apiKeyHandler.Create)
+			r.Delete("/api-keys/{id}", s.apiKeyHandler.Revoke)
 		})
+
+		r.With(admin).Get("/audit", s.auditHandler.List)
 	})
 
 	return r
 }
+
+func (s *Server) authenticate() func(http.Handler) http.Handler {
+	if !s.cfg.AuthEnabled {
+		return passthrough
+	}
+	return middleware.Authenticate(s.authenticator)
+}
+
+func (s *Server) requireScope(scope auth.Scope) func(http.Handler) http.Handler {
+	if !s.cfg.AuthEnabled {
+		return passthrough
+	}
+	return middleware.RequireScope(scope)
+}
+
+func passthrough(next http.Handler) http.Handler {
+	return next
+}
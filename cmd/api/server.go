@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/config"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/handler"
@@ -23,11 +24,19 @@ type Server struct {
 
 	indicatorHandler *handler.IndicatorHandler
 	campaignHandler  *handler.CampaignHandler
+	actorHandler     *handler.ActorHandler
 	dashboardHandler *handler.DashboardHandler
+	stixHandler      *handler.StixHandler
+	taxiiHandler     *handler.TaxiiHandler
+	mispHandler      *handler.MispHandler
 	healthHandler    *handler.HealthHandler
+	apiKeyHandler    *handler.APIKeyHandler
+	auditHandler     *handler.AuditHandler
+
+	authenticator auth.Authenticator
 }
 
-func NewServer(cfg *config.Config, logger *slog.Logger, db *sql.DB, appCache *cache.Cache) *Server {
+func NewServer(cfg *config.Config, logger *slog.Logger, db *sql.DB, appCache *cache.Cache, tokens auth.Authenticator) *Server {
 	s := &Server{
 		cfg:    cfg,
 		logger: logger,
@@ -35,25 +44,44 @@ func NewServer(cfg *config.Config, logger *slog.Logger, db *sql.DB, appCache *ca
 		cache:  appCache,
 	}
 
-	s.setupHandlers()
+	s.setupHandlers(tokens)
 	s.setupHTTPServer()
 
 	return s
 }
 
-func (s *Server) setupHandlers() {
+func (s *Server) setupHandlers(tokens auth.Authenticator) {
 	indicatorRepo := repository.NewIndicatorRepository(s.db)
 	campaignRepo := repository.NewCampaignRepository(s.db)
+	actorRepo := repository.NewActorRepository(s.db)
 	dashboardRepo := repository.NewDashboardRepository(s.db)
+	bundleRepo := repository.NewBundleRepository(s.db)
+	taxiiRepo := repository.NewTaxiiRepository(s.db)
+	apiKeyRepo := repository.NewAPIKeyRepository(s.db)
+	auditRepo := repository.NewAuditRepository(s.db)
 
-	indicatorService := service.NewIndicatorService(indicatorRepo, s.cache)
-	campaignService := service.NewCampaignService(campaignRepo, s.cache)
+	auditService := service.NewAuditService(auditRepo)
+	indicatorService := service.NewIndicatorService(indicatorRepo, s.cache, auditService)
+	campaignService := service.NewCampaignService(campaignRepo, s.cache, auditService)
+	actorService := service.NewActorService(actorRepo, s.cache, auditService)
 	dashboardService := service.NewDashboardService(dashboardRepo, s.cache)
+	stixService := service.NewStixService(bundleRepo, s.cache, auditService)
+	taxiiService := service.NewTaxiiService(taxiiRepo, auditService)
+	mispService := service.NewMispService(bundleRepo, campaignRepo, s.cache, auditService)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, s.cache, auditService)
 
 	s.indicatorHandler = handler.NewIndicatorHandler(indicatorService)
 	s.campaignHandler = handler.NewCampaignHandler(campaignService)
+	s.actorHandler = handler.NewActorHandler(actorService)
 	s.dashboardHandler = handler.NewDashboardHandler(dashboardService)
+	s.stixHandler = handler.NewStixHandler(stixService)
+	s.taxiiHandler = handler.NewTaxiiHandler(taxiiService)
+	s.mispHandler = handler.NewMispHandler(mispService)
 	s.healthHandler = handler.NewHealthHandler(s.db)
+	s.apiKeyHandler = handler.NewAPIKeyHandler(apiKeyService)
+	s.auditHandler = handler.NewAuditHandler(auditService)
+
+	s.authenticator = auth.NewChain(apiKeyService, tokens)
 }
 
 func (s *Server) setupHTTPServer() {
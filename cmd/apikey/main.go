@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/config"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/database"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/google/uuid"
+)
+
+const usage = `Usage:
+  apikey [-tenant ID] create -name NAME -scopes read,write,export,admin [-expires 720h] [-max-tlp green]
+  apikey [-tenant ID] list
+  apikey [-tenant ID] revoke ID
+`
+
+func main() {
+	global := flag.NewFlagSet("apikey", flag.ExitOnError)
+	global.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	tenantID := global.String("tenant", tenant.Default, "tenant the keys belong to")
+	global.Parse(os.Args[1:])
+
+	args := global.Args()
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if !tenant.IsValidID(*tenantID) {
+		fail("invalid tenant %q", *tenantID)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fail("failed to load configuration: %v", err)
+	}
+
+	db, err := database.NewPostgresDB(cfg.DatabaseURL())
+	if err != nil {
+		fail("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := database.RunMigrations(db); err != nil {
+		fail("failed to run migrations: %v", err)
+	}
+
+	appCache, err := cache.New(cache.Config{MaxSizeMB: 1})
+	if err != nil {
+		fail("failed to initialize cache: %v", err)
+	}
+
+	audit := service.NewAuditService(repository.NewAuditRepository(db))
+	svc := service.NewAPIKeyService(repository.NewAPIKeyRepository(db), appCache, audit)
+	ctx := tenant.WithID(context.Background(), *tenantID)
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{ID: currentUser(), Type: "cli", TenantID: *tenantID})
+
+	switch args[0] {
+	case "create":
+		err = create(ctx, svc, args[1:])
+	case "list":
+		err = list(ctx, svc)
+	case "revoke":
+		err = revoke(ctx, svc, args[1:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fail("%v", err)
+	}
+}
+
+func create(ctx context.Context, svc *service.APIKeyService, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	name := fs.String("name", "", "key name")
+	scopes := fs.String("scopes", "read", "comma-separated scopes")
+	expires := fs.Duration("expires", 0, "key lifetime, e.g. 720h (default: never expires)")
+	maxTLP := fs.String("max-tlp", "green", "highest TLP marking the key may read (clear, green, amber, red)")
+	fs.Parse(args)
+
+	input := model.APIKeyInput{Name: *name, Scopes: strings.Split(*scopes, ","), MaxTLP: *maxTLP}
+	if *expires > 0 {
+		expiresAt := time.Now().Add(*expires)
+		input.ExpiresAt = &expiresAt
+	}
+
+	key, err := svc.Create(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("id:      %s\ntenant:  %s\nscopes:  %s\nmax tlp: %s\nkey:     %s\n\n", key.ID, key.TenantID, strings.Join(key.Scopes, ","), key.MaxTLP, key.Key)
+	fmt.Println("Store the key now; it cannot be shown again.")
+	return nil
+}
+
+func list(ctx context.Context, svc *service.APIKeyService) error {
+	keys, err := svc.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tMAX TLP\tCREATED\tLAST USED\tSTATUS")
+	now := time.Now()
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			key.ID, key.Name, key.Prefix, strings.Join(key.Scopes, ","), key.MaxTLP,
+			key.CreatedAt.Format(time.RFC3339), formatTime(key.LastUsedAt), status(&key, now))
+	}
+	return w.Flush()
+}
+
+func revoke(ctx context.Context, svc *service.APIKeyService, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("revoke requires exactly one key ID")
+	}
+	if _, err := uuid.Parse(args[0]); err != nil {
+		return fmt.Errorf("invalid key ID %q", args[0])
+	}
+
+	key, err := svc.Revoke(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("revoked %s (%s)\n", key.ID, key.Name)
+	return nil
+}
+
+func status(key *model.APIKey, now time.Time) string {
+	switch {
+	case key.RevokedAt != nil:
+		return "revoked"
+	case !key.IsUsable(now):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func currentUser() string {
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "unknown"
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "apikey: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+)
+
+type Scope string
+
+const (
+	ScopeRead   Scope = "read"
+	ScopeWrite  Scope = "write"
+	ScopeExport Scope = "export"
+	ScopeAdmin  Scope = "admin"
+)
+
+var validScopes = map[Scope]bool{
+	ScopeRead:   true,
+	ScopeWrite:  true,
+	ScopeExport: true,
+	ScopeAdmin:  true,
+}
+
+func (s Scope) IsValid() bool {
+	return validScopes[s]
+}
+
+const PrincipalAPIKey = "api_key"
+
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+type Chain struct {
+	apiKeys Authenticator
+	tokens  Authenticator
+}
+
+func NewChain(apiKeys, tokens Authenticator) *Chain {
+	return &Chain{apiKeys: apiKeys, tokens: tokens}
+}
+
+func (c *Chain) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if LooksLikeAPIKey(token) {
+		return c.apiKeys.Authenticate(ctx, token)
+	}
+	if c.tokens == nil {
+		return nil, ErrInvalidCredentials
+	}
+	return c.tokens.Authenticate(ctx, token)
+}
+
+type Principal struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Type      string        `json:"type"`
+	TenantID  string        `json:"tenant_id"`
+	Scopes    []Scope       `json:"scopes"`
+	Clearance marking.Level `json:"clearance"`
+}
+
+func (p *Principal) HasScope(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Principal) String() string {
+	return p.Type + ":" + p.ID
+}
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok
+}
+
+func ParseScopes(values []string) ([]Scope, error) {
+	seen := make(map[Scope]bool, len(values))
+	scopes := make([]Scope, 0, len(values))
+	for _, value := range values {
+		scope := Scope(strings.ToLower(strings.TrimSpace(value)))
+		if !scope.IsValid() {
+			return nil, errors.New("unknown scope " + value)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func ParseRoleScopes(mapping map[string]string) (map[string][]Scope, error) {
+	roles := make(map[string][]Scope, len(mapping))
+	for role, value := range mapping {
+		scopes, err := ParseScopes(strings.Split(value, "+"))
+		if err != nil {
+			return nil, fmt.Errorf("role %s: %w", role, err)
+		}
+		roles[strings.TrimSpace(role)] = scopes
+	}
+	return roles, nil
+}
+
+func ParseRoleClearances(mapping map[string]string) (map[string]marking.Level, error) {
+	roles := make(map[string]marking.Level, len(mapping))
+	for role, value := range mapping {
+		level, ok := marking.Parse(value)
+		if !ok {
+			return nil, fmt.Errorf("role %s: unknown TLP level %q", role, value)
+		}
+		roles[strings.TrimSpace(role)] = level
+	}
+	return roles, nil
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrincipal_HasScope(t *testing.T) {
+	reader := &Principal{Scopes: []Scope{ScopeRead}}
+	assert.True(t, reader.HasScope(ScopeRead))
+	assert.False(t, reader.HasScope(ScopeWrite))
+	assert.False(t, reader.HasScope(ScopeExport))
+
+	admin := &Principal{Scopes: []Scope{ScopeAdmin}}
+	for _, scope := range []Scope{ScopeRead, ScopeWrite, ScopeExport, ScopeAdmin} {
+		assert.True(t, admin.HasScope(scope), scope)
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes([]string{" Read", "export", "read"})
+	require.NoError(t, err)
+	assert.Equal(t, []Scope{ScopeRead, ScopeExport}, scopes)
+
+	_, err = ParseScopes([]string{"read", "superuser"})
+	assert.Error(t, err)
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := map[string]struct {
+		header string
+		token  string
+		ok     bool
+	}{
+		"bearer":           {"Bearer abc123", "abc123", true},
+		"case insensitive": {"bearer abc123", "abc123", true},
+		"basic scheme":     {"Basic dXNlcjpwYXNz", "", false},
+		"empty token":      {"Bearer ", "", false},
+		"missing":          {"", "", false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			token, ok := BearerToken(tt.header)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.token, token)
+		})
+	}
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, prefix+"_"))
+	assert.True(t, LooksLikeAPIKey(key))
+	assert.Len(t, HashAPIKey(key), 64)
+
+	other, _, err := GenerateAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.NotEqual(t, HashAPIKey(key), HashAPIKey(other))
+}
+
+func TestPrincipalContext(t *testing.T) {
+	_, ok := PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+
+	principal := &Principal{ID: "key-1", Type: PrincipalAPIKey}
+	found, ok := PrincipalFromContext(WithPrincipal(context.Background(), principal))
+	require.True(t, ok)
+	assert.Equal(t, "api_key:key-1", found.String())
+}
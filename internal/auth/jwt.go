@@ -0,0 +1,301 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+)
+
+const (
+	PrincipalUser = "user"
+
+	defaultRolesClaim = "roles"
+	defaultJWTLeeway  = time.Minute
+	maxNumericDate    = 1 << 40
+)
+
+type JWTConfig struct {
+	Issuer           string
+	Audience         string
+	RolesClaim       string
+	TenantClaim      string
+	RoleScopes       map[string][]Scope
+	DefaultScopes    []Scope
+	RoleClearance    map[string]marking.Level
+	DefaultClearance marking.Level
+	Leeway           time.Duration
+}
+
+type JWTAuthenticator struct {
+	cfg  JWTConfig
+	keys KeySource
+	now  func() time.Time
+}
+
+func NewJWTAuthenticator(cfg JWTConfig, keys KeySource) (*JWTAuthenticator, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("JWT issuer is required")
+	}
+	if cfg.Audience == "" {
+		return nil, errors.New("JWT audience is required")
+	}
+	if keys == nil {
+		return nil, errors.New("JWT key source is required")
+	}
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = defaultRolesClaim
+	}
+	if cfg.Leeway == 0 {
+		cfg.Leeway = defaultJWTLeeway
+	}
+	if cfg.DefaultClearance == "" {
+		cfg.DefaultClearance = marking.Clear
+	}
+	return &JWTAuthenticator{cfg: cfg, keys: keys, now: time.Now}, nil
+}
+
+type jwtHeader struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid"`
+	Crit []string `json:"crit"`
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	claims, err := a.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, invalidToken("missing subject")
+	}
+
+	tenantID, err := a.tenantFor(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{
+		ID:        subject,
+		Name:      displayName(claims),
+		Type:      PrincipalUser,
+		TenantID:  tenantID,
+		Scopes:    a.scopesFor(claims),
+		Clearance: a.clearanceFor(claims),
+	}, nil
+}
+
+func (a *JWTAuthenticator) tenantFor(claims map[string]interface{}) (string, error) {
+	if a.cfg.TenantClaim == "" {
+		return tenant.Default, nil
+	}
+	tenantID, _ := lookupClaim(claims, a.cfg.TenantClaim).(string)
+	if !tenant.IsValidID(tenantID) {
+		return "", invalidToken("missing or invalid tenant")
+	}
+	return tenantID, nil
+}
+
+func (a *JWTAuthenticator) verify(ctx context.Context, token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalidToken("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalidToken("malformed header")
+	}
+	if len(header.Crit) > 0 {
+		return nil, invalidToken("unsupported critical header")
+	}
+	if header.Alg != "RS256" && header.Alg != "ES256" {
+		return nil, invalidToken(fmt.Sprintf("unsupported algorithm %q", header.Alg))
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidToken("malformed signature")
+	}
+
+	key, err := a.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !verifySignature(header.Alg, key, digest[:], signature) {
+		return nil, invalidToken("invalid signature")
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalidToken("malformed claims")
+	}
+	if err := a.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, digest, signature []byte) bool {
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest, signature) == nil
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || pub.Curve != elliptic.P256() || len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(pub, digest, r, s)
+	}
+	return false
+}
+
+func (a *JWTAuthenticator) validateClaims(claims map[string]interface{}) error {
+	if iss, _ := claims["iss"].(string); iss != a.cfg.Issuer {
+		return invalidToken("unexpected issuer")
+	}
+	if !hasAudience(claims["aud"], a.cfg.Audience) {
+		return invalidToken("unexpected audience")
+	}
+
+	now := a.now()
+	exp, ok := numericDate(claims["exp"])
+	if !ok {
+		return invalidToken("missing expiry")
+	}
+	if !now.Before(exp.Add(a.cfg.Leeway)) {
+		return invalidToken("token expired")
+	}
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(a.cfg.Leeway).Before(nbf) {
+		return invalidToken("token not yet valid")
+	}
+	return nil
+}
+
+func (a *JWTAuthenticator) scopesFor(claims map[string]interface{}) []Scope {
+	seen := make(map[Scope]bool)
+	scopes := make([]Scope, 0, len(a.cfg.DefaultScopes))
+	add := func(values []Scope) {
+		for _, scope := range values {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	add(a.cfg.DefaultScopes)
+	for _, role := range claimStrings(lookupClaim(claims, a.cfg.RolesClaim)) {
+		add(a.cfg.RoleScopes[role])
+	}
+	return scopes
+}
+
+func (a *JWTAuthenticator) clearanceFor(claims map[string]interface{}) marking.Level {
+	clearance := a.cfg.DefaultClearance
+	for _, role := range claimStrings(lookupClaim(claims, a.cfg.RolesClaim)) {
+		if level, ok := a.cfg.RoleClearance[role]; ok {
+			clearance = marking.Max(clearance, level)
+		}
+	}
+	return clearance
+}
+
+func lookupClaim(claims map[string]interface{}, path string) interface{} {
+	if value, ok := claims[path]; ok {
+		return value
+	}
+
+	var current interface{} = claims
+	for _, segment := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[segment]
+	}
+	return current
+}
+
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func hasAudience(value interface{}, audience string) bool {
+	if aud, ok := value.(string); ok {
+		return aud == audience
+	}
+	for _, aud := range claimStrings(value) {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
+
+func numericDate(value interface{}) (time.Time, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil || seconds < 0 || seconds > maxNumericDate {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+func displayName(claims map[string]interface{}) string {
+	for _, name := range []string{"preferred_username", "email", "name"} {
+		if value, ok := claims[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	subject, _ := claims["sub"].(string)
+	return subject
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func invalidToken(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidCredentials, reason)
+}
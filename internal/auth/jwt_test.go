@@ -0,0 +1,458 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://sso.example.com/realms/intel"
+	testAudience = "threat-intel-api"
+)
+
+type testSigner struct {
+	kid string
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newRSASigner(t *testing.T, kid string) *testSigner {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return &testSigner{kid: kid, rsa: key}
+}
+
+func newECSigner(t *testing.T, kid string) *testSigner {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &testSigner{kid: kid, ec: key}
+}
+
+func (s *testSigner) jwk() map[string]string {
+	if s.rsa != nil {
+		return map[string]string{
+			"kty": "RSA", "kid": s.kid, "use": "sig", "alg": "RS256",
+			"n": base64.RawURLEncoding.EncodeToString(s.rsa.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.rsa.E)).Bytes()),
+		}
+	}
+	return map[string]string{
+		"kty": "EC", "kid": s.kid, "use": "sig", "crv": "P-256",
+		"x": base64.RawURLEncoding.EncodeToString(s.ec.X.FillBytes(make([]byte, 32))),
+		"y": base64.RawURLEncoding.EncodeToString(s.ec.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func (s *testSigner) sign(t *testing.T, claims map[string]interface{}) string {
+	alg := "ES256"
+	if s.rsa != nil {
+		alg = "RS256"
+	}
+	return s.signWithHeader(t, map[string]interface{}{"alg": alg, "typ": "JWT", "kid": s.kid}, claims)
+}
+
+func (s *testSigner) signWithHeader(t *testing.T, header, claims map[string]interface{}) string {
+	h, err := json.Marshal(header)
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	if s.rsa != nil {
+		signature, err = rsa.SignPKCS1v15(rand.Reader, s.rsa, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	} else {
+		r, sv, err := ecdsa.Sign(rand.Reader, s.ec, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), sv.FillBytes(make([]byte, 32))...)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func jwksJSON(t *testing.T, signers ...*testSigner) []byte {
+	keys := make([]map[string]string, 0, len(signers))
+	for _, s := range signers {
+		keys = append(keys, s.jwk())
+	}
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	require.NoError(t, err)
+	return data
+}
+
+type jwksServer struct {
+	*httptest.Server
+	body     atomic.Value
+	requests atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, signers ...*testSigner) *jwksServer {
+	srv := &jwksServer{}
+	srv.body.Store(jwksJSON(t, signers...))
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(srv.body.Load().([]byte))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":                testIssuer,
+		"aud":                []string{"account", testAudience},
+		"sub":                "3f1c2a9e",
+		"preferred_username": "jdoe",
+		"exp":                time.Now().Add(5 * time.Minute).Unix(),
+		"iat":                time.Now().Unix(),
+		"roles":              []string{"analyst"},
+	}
+}
+
+func newTestJWTAuthenticator(t *testing.T, keys KeySource) *JWTAuthenticator {
+	a, err := NewJWTAuthenticator(JWTConfig{
+		Issuer:   testIssuer,
+		Audience: testAudience,
+		RoleScopes: map[string][]Scope{
+			"analyst":  {ScopeRead, ScopeWrite},
+			"exporter": {ScopeRead, ScopeExport},
+		},
+		DefaultScopes: []Scope{ScopeRead},
+	}, keys)
+	require.NoError(t, err)
+	return a
+}
+
+func TestJWTAuthenticator_ValidTokens(t *testing.T) {
+	rsaSigner := newRSASigner(t, "rsa-1")
+	ecSigner := newECSigner(t, "ec-1")
+	srv := newJWKSServer(t, rsaSigner, ecSigner)
+	a := newTestJWTAuthenticator(t, NewRemoteJWKS(srv.URL, srv.Client(), time.Hour))
+
+	for _, signer := range []*testSigner{rsaSigner, ecSigner} {
+		principal, err := a.Authenticate(context.Background(), signer.sign(t, validClaims()))
+		require.NoError(t, err, signer.kid)
+
+		assert.Equal(t, "3f1c2a9e", principal.ID)
+		assert.Equal(t, "jdoe", principal.Name)
+		assert.Equal(t, PrincipalUser, principal.Type)
+		assert.Equal(t, []Scope{ScopeRead, ScopeWrite}, principal.Scopes)
+	}
+	assert.Equal(t, int32(1), srv.requests.Load(), "JWKS should be fetched once and cached")
+}
+
+func TestJWTAuthenticator_RoleMapping(t *testing.T) {
+	signer := newRSASigner(t, "rsa-1")
+	srv := newJWKSServer(t, signer)
+
+	tests := map[string]struct {
+		rolesClaim string
+		roles      map[string]interface{}
+		want       []Scope
+	}{
+		"no roles":         {"roles", map[string]interface{}{}, []Scope{ScopeRead}},
+		"unmapped role":    {"roles", map[string]interface{}{"roles": []string{"viewer"}}, []Scope{ScopeRead}},
+		"multiple roles":   {"roles", map[string]interface{}{"roles": []string{"analyst", "exporter"}}, []Scope{ScopeRead, ScopeWrite, ScopeExport}},
+		"space separated":  {"groups", map[string]interface{}{"groups": "exporter other"}, []Scope{ScopeRead, ScopeExport}},
+		"nested claim":     {"realm_access.roles", map[string]interface{}{"realm_access": map[string]interface{}{"roles": []string{"exporter"}}}, []Scope{ScopeRead, ScopeExport}},
+		"roles claim type": {"roles", map[string]interface{}{"roles": 42}, []Scope{ScopeRead}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := newTestJWTAuthenticator(t, NewRemoteJWKS(srv.URL, srv.Client(), time.Hour))
+			a.cfg.RolesClaim = tt.rolesClaim
+
+			claims := validClaims()
+			delete(claims, "roles")
+			for k, v := range tt.roles {
+				claims[k] = v
+			}
+
+			principal, err := a.Authenticate(context.Background(), signer.sign(t, claims))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, principal.Scopes)
+		})
+	}
+}
+
+func TestJWTAuthenticator_TenantClaim(t *testing.T) {
+	signer := newRSASigner(t, "rsa-1")
+	srv := newJWKSServer(t, signer)
+
+	tests := map[string]struct {
+		tenantClaim string
+		value       interface{}
+		want        string
+		wantErr     bool
+	}{
+		"claim not configured": {"", "emea", tenant.Default, false},
+		"tenant from claim":    {"tenant", "emea", "emea", false},
+		"missing claim":        {"tenant", nil, "", true},
+		"invalid tenant":       {"tenant", "EMEA/../apac", "", true},
+		"non-string claim":     {"tenant", 7, "", true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := newTestJWTAuthenticator(t, NewRemoteJWKS(srv.URL, srv.Client(), time.Hour))
+			a.cfg.TenantClaim = tt.tenantClaim
+
+			claims := validClaims()
+			if tt.value != nil {
+				claims["tenant"] = tt.value
+			}
+
+			principal, err := a.Authenticate(context.Background(), signer.sign(t, claims))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCredentials)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, principal.TenantID)
+		})
+	}
+}
+
+func TestJWTAuthenticator_RoleClearance(t *testing.T) {
+	signer := newRSASigner(t, "rsa-1")
+	srv := newJWKSServer(t, signer)
+
+	tests := map[string]struct {
+		roles []string
+		want  marking.Level
+	}{
+		"no roles":           {nil, marking.Green},
+		"unmapped role":      {[]string{"viewer"}, marking.Green},
+		"mapped role":        {[]string{"analyst"}, marking.Amber},
+		"highest role wins":  {[]string{"admin", "analyst"}, marking.Red},
+		"lower than default": {[]string{"intern"}, marking.Green},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := newTestJWTAuthenticator(t, NewRemoteJWKS(srv.URL, srv.Client(), time.Hour))
+			a.cfg.DefaultClearance = marking.Green
+			a.cfg.RoleClearance = map[string]marking.Level{
+				"analyst": marking.Amber,
+				"admin":   marking.Red,
+				"intern":  marking.Clear,
+			}
+
+			claims := validClaims()
+			claims["roles"] = tt.roles
+
+			principal, err := a.Authenticate(context.Background(), signer.sign(t, claims))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, principal.Clearance)
+		})
+	}
+}
+
+func TestJWTAuthenticator_RejectsInvalidTokens(t *testing.T) {
+	signer := newRSASigner(t, "rsa-1")
+	impostor := newRSASigner(t, "rsa-1")
+	srv := newJWKSServer(t, signer)
+	a := newTestJWTAuthenticator(t, NewRemoteJWKS(srv.URL, srv.Client(), time.Hour))
+
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := map[string]string{
+		"expired":          signer.sign(t, with("exp", time.Now().Add(-2*time.Minute).Unix())),
+		"missing expiry":   signer.sign(t, with("exp", nil)),
+		"not yet valid":    signer.sign(t, with("nbf", time.Now().Add(10*time.Minute).Unix())),
+		"wrong issuer":     signer.sign(t, with("iss", "https://evil.example.com")),
+		"wrong audience":   signer.sign(t, with("aud", "another-api")),
+		"missing audience": signer.sign(t, with("aud", nil)),
+		"missing subject":  signer.sign(t, with("sub", nil)),
+		"forged signature": impostor.sign(t, validClaims()),
+		"alg none":         signer.signWithHeader(t, map[string]interface{}{"alg": "none", "kid": "rsa-1"}, validClaims()),
+		"alg HS256":        signer.signWithHeader(t, map[string]interface{}{"alg": "HS256", "kid": "rsa-1"}, validClaims()),
+		"alg mismatch":     signer.signWithHeader(t, map[string]interface{}{"alg": "ES256", "kid": "rsa-1"}, validClaims()),
+		"critical header":  signer.signWithHeader(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-1", "crit": []string{"exp"}}, validClaims()),
+		"tampered claims":  tamper(signer.sign(t, validClaims())),
+		"malformed":        "not.a-jwt",
+	}
+
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := a.Authenticate(context.Background(), token)
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+}
+
+func tamper(token string) string {
+	parts := strings.Split(token, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	payload = []byte(strings.Replace(string(payload), `"analyst"`, `"admin__"`, 1))
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	return strings.Join(parts, ".")
+}
+
+func TestJWKS_RefetchesOnUnknownKeyID(t *testing.T) {
+	oldSigner := newRSASigner(t, "2024")
+	newSigner := newECSigner(t, "2025")
+	srv := newJWKSServer(t, oldSigner)
+
+	keys := NewRemoteJWKS(srv.URL, srv.Client(), time.Hour)
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+	a := newTestJWTAuthenticator(t, keys)
+
+	_, err := a.Authenticate(context.Background(), oldSigner.sign(t, validClaims()))
+	require.NoError(t, err)
+
+	srv.body.Store(jwksJSON(t, newSigner))
+
+	_, err = a.Authenticate(context.Background(), newSigner.sign(t, validClaims()))
+	assert.ErrorIs(t, err, ErrInvalidCredentials, "refetch is throttled right after a fetch")
+
+	now = now.Add(minJWKSRefreshInterval)
+	_, err = a.Authenticate(context.Background(), newSigner.sign(t, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), srv.requests.Load())
+}
+
+func TestJWKS_KeepsCachedKeysWhenRefreshFails(t *testing.T) {
+	signer := newRSASigner(t, "rsa-1")
+	srv := newJWKSServer(t, signer)
+
+	keys := NewRemoteJWKS(srv.URL, srv.Client(), time.Minute)
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+	a := newTestJWTAuthenticator(t, keys)
+
+	_, err := a.Authenticate(context.Background(), signer.sign(t, validClaims()))
+	require.NoError(t, err)
+
+	srv.body.Store([]byte(`{"keys": []}`))
+	now = now.Add(2 * time.Minute)
+
+	_, err = a.Authenticate(context.Background(), signer.sign(t, validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), srv.requests.Load())
+}
+
+func TestJWKS_UnavailableIsNotACredentialError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	a := newTestJWTAuthenticator(t, NewRemoteJWKS(srv.URL, srv.Client(), time.Hour))
+	_, err := a.Authenticate(context.Background(), newRSASigner(t, "rsa-1").sign(t, validClaims()))
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestJWKS_FromFile(t *testing.T) {
+	signer := newECSigner(t, "")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwksJSON(t, signer), 0o600))
+
+	a := newTestJWTAuthenticator(t, NewFileJWKS(path, time.Hour))
+	principal, err := a.Authenticate(context.Background(), signer.sign(t, validClaims()))
+
+	require.NoError(t, err)
+	assert.Equal(t, "3f1c2a9e", principal.ID)
+}
+
+func TestParseJWKS_SkipsUnusableKeys(t *testing.T) {
+	signer := newRSASigner(t, "rsa-1")
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": signer.jwk()["n"], "e": "AQAB"},
+		{"kty": "RSA", "kid": "weak", "n": base64.RawURLEncoding.EncodeToString(weak.N.Bytes()), "e": "AQAB"},
+		{"kty": "EC", "kid": "p384", "crv": "P-384", "x": "AA", "y": "AA"},
+		signer.jwk(),
+	}})
+	require.NoError(t, err)
+
+	keys, err := parseJWKS(data)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Contains(t, keys, "rsa-1")
+
+	_, err = parseJWKS([]byte(`{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`))
+	assert.Error(t, err)
+}
+
+type stubAuthenticator struct {
+	name string
+}
+
+func (s stubAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	return &Principal{ID: s.name}, nil
+}
+
+func TestChain_DispatchesByTokenShape(t *testing.T) {
+	chain := NewChain(stubAuthenticator{"api_key"}, stubAuthenticator{"jwt"})
+
+	principal, err := chain.Authenticate(context.Background(), "tia_0123456789ab_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "api_key", principal.ID)
+
+	principal, err = chain.Authenticate(context.Background(), "eyJhbGciOiJSUzI1NiJ9.e30.sig")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt", principal.ID)
+
+	_, err = NewChain(stubAuthenticator{"api_key"}, nil).Authenticate(context.Background(), "eyJhbGciOiJSUzI1NiJ9.e30.sig")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestParseRoleClearances(t *testing.T) {
+	roles, err := ParseRoleClearances(map[string]string{"analyst": "TLP:AMBER", "guest": "white"})
+	require.NoError(t, err)
+	assert.Equal(t, marking.Amber, roles["analyst"])
+	assert.Equal(t, marking.Clear, roles["guest"])
+
+	_, err = ParseRoleClearances(map[string]string{"analyst": "purple"})
+	assert.Error(t, err)
+}
+
+func TestParseRoleScopes(t *testing.T) {
+	roles, err := ParseRoleScopes(map[string]string{"analyst": "read+write", "admin": "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, []Scope{ScopeRead, ScopeWrite}, roles["analyst"])
+	assert.Equal(t, []Scope{ScopeAdmin}, roles["admin"])
+
+	_, err = ParseRoleScopes(map[string]string{"analyst": "read+root"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,186 @@
+package blocklist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/hostname"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+const (
+	FormatEDL      = "edl"
+	FormatNftables = "nftables"
+	FormatRPZ      = "rpz"
+
+	NftablesTable = "threat_intel"
+	NftablesSetV4 = "blocklist_v4"
+	NftablesSetV6 = "blocklist_v6"
+
+	rpzTTL = 300
+)
+
+type Format struct {
+	Name        string
+	ContentType string
+	Filename    string
+	Types       []model.IndicatorType
+}
+
+var Formats = map[string]Format{
+	FormatEDL: {
+		Name:        FormatEDL,
+		ContentType: "text/plain; charset=utf-8",
+		Filename:    "blocklist.txt",
+		Types:       []model.IndicatorType{model.IndicatorTypeIP, model.IndicatorTypeDomain, model.IndicatorTypeURL},
+	},
+	FormatNftables: {
+		Name:        FormatNftables,
+		ContentType: "text/plain; charset=utf-8",
+		Filename:    "blocklist.nft",
+		Types:       []model.IndicatorType{model.IndicatorTypeIP},
+	},
+	FormatRPZ: {
+		Name:        FormatRPZ,
+		ContentType: "text/dns; charset=utf-8",
+		Filename:    "blocklist.rpz",
+		Types:       []model.IndicatorType{model.IndicatorTypeDomain},
+	},
+}
+
+func (f Format) Supports(t model.IndicatorType) bool {
+	for _, supported := range f.Types {
+		if supported == t {
+			return true
+		}
+	}
+	return false
+}
+
+type Writer struct {
+	format string
+	w      *bufio.Writer
+	now    time.Time
+}
+
+func NewWriter(format Format, w io.Writer, now time.Time) *Writer {
+	return &Writer{format: format.Name, w: bufio.NewWriter(w), now: now.UTC()}
+}
+
+func (bw *Writer) Header() error {
+	switch bw.format {
+	case FormatNftables:
+		_, err := fmt.Fprintf(bw.w, "#!/usr/sbin/nft -f\n# Generated %s\n"+
+			"table inet %[2]s {\n"+
+			"\tset %[3]s {\n\t\ttype ipv4_addr\n\t\tflags interval\n\t\tauto-merge\n\t}\n"+
+			"\tset %[4]s {\n\t\ttype ipv6_addr\n\t\tflags interval\n\t\tauto-merge\n\t}\n"+
+			"}\n"+
+			"flush set inet %[2]s %[3]s\n"+
+			"flush set inet %[2]s %[4]s\n",
+			bw.now.Format(time.RFC3339), NftablesTable, NftablesSetV4, NftablesSetV6)
+		return err
+	case FormatRPZ:
+		_, err := fmt.Fprintf(bw.w, "$TTL %d\n@ IN SOA localhost. root.localhost. %d 3600 600 86400 %d\n@ IN NS localhost.\n",
+			rpzTTL, bw.now.Unix(), rpzTTL)
+		return err
+	}
+	return nil
+}
+
+func (bw *Writer) Encode(ind *model.Indicator) error {
+	var lines []string
+	switch bw.format {
+	case FormatEDL:
+		lines = edlEntries(ind)
+	case FormatNftables:
+		lines = nftablesEntries(ind)
+	case FormatRPZ:
+		lines = rpzEntries(ind)
+	}
+
+	for _, line := range lines {
+		if _, err := bw.w.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bw *Writer) Flush() error {
+	return bw.w.Flush()
+}
+
+func edlEntries(ind *model.Indicator) []string {
+	value := strings.TrimSpace(ind.Value)
+	switch ind.Type {
+	case model.IndicatorTypeIP:
+		if prefix, ok := parseAddress(value); ok {
+			if prefix.IsSingleIP() {
+				return []string{prefix.Addr().String()}
+			}
+			return []string{prefix.String()}
+		}
+	case model.IndicatorTypeDomain:
+		if domain := hostname.Normalize(value); domain != "" {
+			return []string{domain}
+		}
+	case model.IndicatorTypeURL:
+		if _, rest, found := strings.Cut(value, "://"); found {
+			value = rest
+		}
+		if value != "" && !strings.ContainsAny(value, " \t\r\n") {
+			return []string{value}
+		}
+	}
+	return nil
+}
+
+func nftablesEntries(ind *model.Indicator) []string {
+	if ind.Type != model.IndicatorTypeIP {
+		return nil
+	}
+	prefix, ok := parseAddress(strings.TrimSpace(ind.Value))
+	if !ok {
+		return nil
+	}
+
+	set := NftablesSetV4
+	if !prefix.Addr().Is4() {
+		set = NftablesSetV6
+	}
+	element := prefix.String()
+	if prefix.IsSingleIP() {
+		element = prefix.Addr().String()
+	}
+
+	return []string{fmt.Sprintf("add element inet %s %s { %s }", NftablesTable, set, element)}
+}
+
+func rpzEntries(ind *model.Indicator) []string {
+	if ind.Type != model.IndicatorTypeDomain {
+		return nil
+	}
+	domain := hostname.Normalize(ind.Value)
+	if domain == "" {
+		return nil
+	}
+	return []string{
+		domain + " CNAME .",
+		"*." + domain + " CNAME .",
+	}
+}
+
+func parseAddress(value string) (netip.Prefix, bool) {
+	if addr, err := netip.ParseAddr(value); err == nil {
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), true
+	}
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		return prefix.Masked(), true
+	}
+	return netip.Prefix{}, false
+}
@@ -0,0 +1,79 @@
+package blocklist
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var generatedAt = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func render(t *testing.T, format string, indicators ...model.Indicator) string {
+	var buf bytes.Buffer
+	w := NewWriter(Formats[format], &buf, generatedAt)
+	require.NoError(t, w.Header())
+	for i := range indicators {
+		require.NoError(t, w.Encode(&indicators[i]))
+	}
+	require.NoError(t, w.Flush())
+	return buf.String()
+}
+
+func TestWriter_EDL(t *testing.T) {
+	out := render(t, FormatEDL,
+		model.Indicator{Type: model.IndicatorTypeIP, Value: "203.0.113.7"},
+		model.Indicator{Type: model.IndicatorTypeIP, Value: "198.51.100.0/24"},
+		model.Indicator{Type: model.IndicatorTypeDomain, Value: "Evil.Example."},
+		model.Indicator{Type: model.IndicatorTypeURL, Value: "https://evil.example/path?q=1"},
+		model.Indicator{Type: model.IndicatorTypeIP, Value: "not-an-ip"},
+		model.Indicator{Type: model.IndicatorTypeHash, Value: "d41d8cd98f00b204e9800998ecf8427e"},
+	)
+
+	assert.Equal(t, "203.0.113.7\n198.51.100.0/24\nevil.example\nevil.example/path?q=1\n", out)
+}
+
+func TestWriter_Nftables(t *testing.T) {
+	out := render(t, FormatNftables,
+		model.Indicator{Type: model.IndicatorTypeIP, Value: "203.0.113.7"},
+		model.Indicator{Type: model.IndicatorTypeIP, Value: "2001:db8::1"},
+		model.Indicator{Type: model.IndicatorTypeIP, Value: "198.51.100.9/24"},
+		model.Indicator{Type: model.IndicatorTypeDomain, Value: "evil.example"},
+	)
+
+	assert.True(t, strings.HasPrefix(out, "#!/usr/sbin/nft -f\n"))
+	assert.Contains(t, out, "table inet threat_intel {")
+	assert.Contains(t, out, "type ipv4_addr")
+	assert.Contains(t, out, "type ipv6_addr")
+	assert.Contains(t, out, "flush set inet threat_intel blocklist_v4\n")
+	assert.Contains(t, out, "add element inet threat_intel blocklist_v4 { 203.0.113.7 }\n")
+	assert.Contains(t, out, "add element inet threat_intel blocklist_v6 { 2001:db8::1 }\n")
+	assert.Contains(t, out, "add element inet threat_intel blocklist_v4 { 198.51.100.0/24 }\n")
+	assert.NotContains(t, out, "evil.example")
+}
+
+func TestWriter_RPZ(t *testing.T) {
+	out := render(t, FormatRPZ,
+		model.Indicator{Type: model.IndicatorTypeDomain, Value: "Evil.Example"},
+		model.Indicator{Type: model.IndicatorTypeDomain, Value: "bad domain"},
+	)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, "$TTL 300", lines[0])
+	assert.Equal(t, "@ IN SOA localhost. root.localhost. 1709294400 3600 600 86400 300", lines[1])
+	assert.Equal(t, "@ IN NS localhost.", lines[2])
+	assert.Equal(t, "evil.example CNAME .", lines[3])
+	assert.Equal(t, "*.evil.example CNAME .", lines[4])
+}
+
+func TestFormat_Supports(t *testing.T) {
+	assert.True(t, Formats[FormatEDL].Supports(model.IndicatorTypeURL))
+	assert.False(t, Formats[FormatEDL].Supports(model.IndicatorTypeHash))
+	assert.True(t, Formats[FormatNftables].Supports(model.IndicatorTypeIP))
+	assert.False(t, Formats[FormatRPZ].Supports(model.IndicatorTypeIP))
+}
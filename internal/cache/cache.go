@@ -1,16 +1,29 @@
 package cache
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
 	"github.com/dgraph-io/ristretto"
 )
 
+// Cache stores entries under their key plus the current generation of the
+// key's prefix. DeletePrefix bumps the generation instead of tracking every
+// key, so the old entries become unreachable and ristretto drops them on
+// eviction or expiry.
 type Cache struct {
 	cache *ristretto.Cache
+
+	mu          sync.RWMutex
+	generations map[string]uint64
 }
 
 type Config struct {
@@ -30,35 +43,67 @@ func New(cfg Config) (*Cache, error) {
 		return nil, err
 	}
 
-	return &Cache{cache: cache}, nil
+	return &Cache{
+		cache:       cache,
+		generations: make(map[string]uint64),
+	}, nil
 }
 
 func (c *Cache) Get(key string) (interface{}, bool) {
-	return c.cache.Get(key)
+	return c.cache.Get(c.versioned(key))
 }
 
 func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
 	cost := int64(1)
-	c.cache.SetWithTTL(key, value, cost, ttl)
+	c.cache.SetWithTTL(c.versioned(key), value, cost, ttl)
 }
 
 func (c *Cache) Delete(key string) {
-	c.cache.Del(key)
+	c.cache.Del(c.versioned(key))
+}
+
+func (c *Cache) DeleteKey(ctx context.Context, prefix string, params interface{}) {
+	for _, level := range marking.Levels() {
+		c.Delete(GenerateKey(marking.WithClearance(ctx, level), prefix, params))
+	}
+}
+
+func (c *Cache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[prefix]++
 }
 
 func (c *Cache) Clear() {
 	c.cache.Clear()
 }
 
-func GenerateKey(prefix string, params interface{}) string {
+func (c *Cache) versioned(key string) string {
+	c.mu.RLock()
+	generation := c.generations[keyPrefix(key)]
+	c.mu.RUnlock()
+
+	return key + "#" + strconv.FormatUint(generation, 10)
+}
+
+func keyPrefix(key string) string {
+	prefix, _, _ := strings.Cut(key, ":")
+	return prefix
+}
+
+func GenerateKey(ctx context.Context, prefix string, params interface{}) string {
 	data, _ := json.Marshal(params)
 	hash := sha256.Sum256(data)
-	return prefix + ":" + hex.EncodeToString(hash[:8])
+	return prefix + ":" + tenant.FromContext(ctx) + ":" + string(marking.ClearanceFromContext(ctx)) + ":" + hex.EncodeToString(hash[:8])
 }
 
 const (
 	TTLIndicatorDetail  = 2 * time.Minute
 	TTLIndicatorSearch  = 30 * time.Second
 	TTLCampaignTimeline = 1 * time.Minute
+	TTLCampaignDetail   = 2 * time.Minute
 	TTLDashboardSummary = 5 * time.Minute
+	TTLActorDetail      = 2 * time.Minute
+	TTLAPIKey           = 30 * time.Second
 )
@@ -1,9 +1,13 @@
 package cache
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -63,20 +67,95 @@ func TestCache_Clear(t *testing.T) {
 	assert.False(t, found2)
 }
 
+func TestCache_DeletePrefix(t *testing.T) {
+	c, err := New(Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+
+	searchKey1 := GenerateKey(context.Background(), "search", map[string]string{"type": "ip"})
+	searchKey2 := GenerateKey(tenant.WithID(context.Background(), "soc-emea"), "search", map[string]string{"type": "domain"})
+	indicatorKey := GenerateKey(context.Background(), "indicator", map[string]string{"id": "123"})
+
+	c.Set(searchKey1, "value1", time.Minute)
+	c.Set(searchKey2, "value2", time.Minute)
+	c.Set(indicatorKey, "value3", time.Minute)
+	time.Sleep(10 * time.Millisecond)
+
+	c.DeletePrefix("search")
+	time.Sleep(10 * time.Millisecond)
+
+	_, found1 := c.Get(searchKey1)
+	_, found2 := c.Get(searchKey2)
+	_, found3 := c.Get(indicatorKey)
+
+	assert.False(t, found1)
+	assert.False(t, found2)
+	assert.True(t, found3)
+
+	c.Set(searchKey1, "refreshed", time.Minute)
+	time.Sleep(10 * time.Millisecond)
+
+	value, found := c.Get(searchKey1)
+	assert.True(t, found)
+	assert.Equal(t, "refreshed", value)
+	assert.Len(t, c.generations, 1)
+}
+
 func TestGenerateKey(t *testing.T) {
 	params1 := map[string]interface{}{"id": "123", "type": "ip"}
 	params2 := map[string]interface{}{"id": "123", "type": "ip"}
 	params3 := map[string]interface{}{"id": "456", "type": "ip"}
 
-	key1 := GenerateKey("indicator", params1)
-	key2 := GenerateKey("indicator", params2)
-	key3 := GenerateKey("indicator", params3)
+	ctx := context.Background()
+	key1 := GenerateKey(ctx, "indicator", params1)
+	key2 := GenerateKey(ctx, "indicator", params2)
+	key3 := GenerateKey(ctx, "indicator", params3)
 
 	assert.Equal(t, key1, key2)
 	assert.NotEqual(t, key1, key3)
 	assert.Contains(t, key1, "indicator:")
 }
 
+func TestGenerateKey_IncludesTenant(t *testing.T) {
+	params := map[string]string{"id": "123"}
+
+	defaultKey := GenerateKey(context.Background(), "indicator", params)
+	emeaKey := GenerateKey(tenant.WithID(context.Background(), "soc-emea"), "indicator", params)
+	apacKey := GenerateKey(tenant.WithID(context.Background(), "soc-apac"), "indicator", params)
+
+	assert.NotEqual(t, defaultKey, emeaKey)
+	assert.NotEqual(t, emeaKey, apacKey)
+	assert.True(t, strings.HasPrefix(emeaKey, "indicator:soc-emea:"))
+}
+
+func TestGenerateKey_IncludesClearance(t *testing.T) {
+	params := map[string]string{"id": "123"}
+
+	greenKey := GenerateKey(marking.WithClearance(context.Background(), marking.Green), "indicator", params)
+	redKey := GenerateKey(marking.WithClearance(context.Background(), marking.Red), "indicator", params)
+
+	assert.NotEqual(t, greenKey, redKey)
+}
+
+func TestDeleteKey_AllClearances(t *testing.T) {
+	c, err := New(Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+
+	params := map[string]string{"id": "123"}
+	greenCtx := marking.WithClearance(context.Background(), marking.Green)
+	redCtx := marking.WithClearance(context.Background(), marking.Red)
+	c.Set(GenerateKey(greenCtx, "indicator", params), "green", time.Minute)
+	c.Set(GenerateKey(redCtx, "indicator", params), "red", time.Minute)
+	time.Sleep(10 * time.Millisecond)
+
+	c.DeleteKey(greenCtx, "indicator", params)
+	time.Sleep(10 * time.Millisecond)
+
+	_, found := c.Get(GenerateKey(greenCtx, "indicator", params))
+	assert.False(t, found)
+	_, found = c.Get(GenerateKey(redCtx, "indicator", params))
+	assert.False(t, found)
+}
+
 func TestTTLConstants(t *testing.T) {
 	assert.Equal(t, 2*time.Minute, TTLIndicatorDetail)
 	assert.Equal(t, 30*time.Second, TTLIndicatorSearch)
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/caarlos0/env/v10"
 )
 
@@ -15,8 +17,22 @@ type Config struct {
 	DBSSLMode      string `env:"DB_SSLMODE" envDefault:"disable"`
 	CacheMaxSizeMB int64  `env:"CACHE_MAX_SIZE_MB" envDefault:"100"`
 	RateLimitRPM   int    `env:"RATE_LIMIT_RPM" envDefault:"100"`
-	Environment    string `env:"APP_ENV" envDefault:"development"`
-	LogLevel       string `env:"LOG_LEVEL" envDefault:"info"`
+	AuthEnabled    bool   `env:"AUTH_ENABLED" envDefault:"true"`
+
+	OIDCIssuer        string            `env:"OIDC_ISSUER"`
+	OIDCAudience      string            `env:"OIDC_AUDIENCE"`
+	OIDCJWKSURL       string            `env:"OIDC_JWKS_URL"`
+	OIDCJWKSFile      string            `env:"OIDC_JWKS_FILE"`
+	OIDCJWKSRefresh   time.Duration     `env:"OIDC_JWKS_REFRESH" envDefault:"15m"`
+	OIDCRolesClaim    string            `env:"OIDC_ROLES_CLAIM" envDefault:"roles"`
+	OIDCTenantClaim   string            `env:"OIDC_TENANT_CLAIM"`
+	OIDCRoleScopes    map[string]string `env:"OIDC_ROLE_SCOPES" envDefault:"analyst:read+write,exporter:read+export,admin:admin"`
+	OIDCDefaultScopes []string          `env:"OIDC_DEFAULT_SCOPES" envDefault:"read"`
+	OIDCRoleTLP       map[string]string `env:"OIDC_ROLE_TLP" envDefault:"analyst:amber,admin:red"`
+	OIDCDefaultTLP    string            `env:"OIDC_DEFAULT_TLP" envDefault:"green"`
+
+	Environment string `env:"APP_ENV" envDefault:"development"`
+	LogLevel    string `env:"LOG_LEVEL" envDefault:"info"`
 }
 
 func Load() (*Config, error) {
@@ -31,6 +47,10 @@ func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
 }
 
+func (c *Config) OIDCEnabled() bool {
+	return c.OIDCIssuer != ""
+}
+
 func (c *Config) DatabaseURL() string {
 	return "host=" + c.DBHost +
 		" port=" + c.DBPort +
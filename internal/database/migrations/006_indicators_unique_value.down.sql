@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_indicators_type_normalized_value;
+ALTER TABLE indicators DROP COLUMN IF EXISTS normalized_value;
@@ -0,0 +1,71 @@
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS normalized_value TEXT GENERATED ALWAYS AS (
+    CASE
+        WHEN type = 'domain' THEN rtrim(lower(btrim(value)), '.')
+        WHEN type = 'url' AND btrim(value) ~ '^[^/?#]+://' THEN
+            lower(substring(btrim(value) from '^[^/?#]+://[^/?#]*')) ||
+            substring(btrim(value) from '^[^/?#]+://[^/?#]*(.*)$')
+        WHEN type = 'url' THEN btrim(value)
+        ELSE lower(btrim(value))
+    END
+) STORED;
+
+CREATE TEMP TABLE indicator_duplicates AS
+SELECT id, keep_id
+FROM (
+    SELECT id,
+           first_value(id) OVER (PARTITION BY type, normalized_value ORDER BY created_at, id) AS keep_id,
+           COUNT(*) OVER (PARTITION BY type, normalized_value) AS group_size
+    FROM indicators
+) grouped
+WHERE group_size > 1;
+
+UPDATE indicators k
+SET first_seen = merged.first_seen,
+    last_seen = merged.last_seen,
+    confidence = merged.confidence,
+    is_active = merged.is_active,
+    updated_at = CURRENT_TIMESTAMP
+FROM (
+    SELECT d.keep_id,
+           MIN(i.first_seen) AS first_seen,
+           MAX(i.last_seen) AS last_seen,
+           MAX(i.confidence) AS confidence,
+           bool_or(i.is_active) AS is_active
+    FROM indicator_duplicates d
+    JOIN indicators i ON i.id = d.id
+    GROUP BY d.keep_id
+) merged
+WHERE k.id = merged.keep_id;
+
+UPDATE indicators k
+SET tags = merged.tags
+FROM (
+    SELECT d.keep_id, jsonb_agg(DISTINCT t.tag) AS tags
+    FROM indicator_duplicates d
+    JOIN indicators i ON i.id = d.id
+    CROSS JOIN LATERAL jsonb_array_elements_text(COALESCE(i.tags, '[]'::jsonb)) AS t(tag)
+    GROUP BY d.keep_id
+) merged
+WHERE k.id = merged.keep_id;
+
+INSERT INTO indicator_campaigns (indicator_id, campaign_id, added_at, notes)
+SELECT d.keep_id, ic.campaign_id, ic.added_at, ic.notes
+FROM indicator_duplicates d
+JOIN indicator_campaigns ic ON ic.indicator_id = d.id
+WHERE d.id <> d.keep_id
+ON CONFLICT (indicator_id, campaign_id) DO NOTHING;
+
+INSERT INTO indicator_actors (indicator_id, actor_id, attribution_confidence, added_at)
+SELECT d.keep_id, ia.actor_id, ia.attribution_confidence, ia.added_at
+FROM indicator_duplicates d
+JOIN indicator_actors ia ON ia.indicator_id = d.id
+WHERE d.id <> d.keep_id
+ON CONFLICT (indicator_id, actor_id) DO UPDATE
+    SET attribution_confidence = GREATEST(indicator_actors.attribution_confidence, EXCLUDED.attribution_confidence);
+
+DELETE FROM indicators
+WHERE id IN (SELECT id FROM indicator_duplicates WHERE id <> keep_id);
+
+DROP TABLE indicator_duplicates;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_indicators_type_normalized_value ON indicators(type, normalized_value);
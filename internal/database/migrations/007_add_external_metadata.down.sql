@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_actors_stix_id;
+DROP INDEX IF EXISTS idx_campaigns_stix_id;
+DROP INDEX IF EXISTS idx_indicators_stix_id;
+ALTER TABLE threat_actors DROP COLUMN IF EXISTS metadata;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS metadata;
@@ -0,0 +1,6 @@
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS metadata JSONB DEFAULT '{}';
+ALTER TABLE threat_actors ADD COLUMN IF NOT EXISTS metadata JSONB DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_indicators_stix_id ON indicators((metadata->>'stix_id'));
+CREATE INDEX IF NOT EXISTS idx_campaigns_stix_id ON campaigns((metadata->>'stix_id'));
+CREATE INDEX IF NOT EXISTS idx_actors_stix_id ON threat_actors((metadata->>'stix_id'));
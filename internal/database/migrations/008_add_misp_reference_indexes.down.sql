@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_actors_misp_uuid;
+DROP INDEX IF EXISTS idx_campaigns_misp_uuid;
+DROP INDEX IF EXISTS idx_indicators_misp_uuid;
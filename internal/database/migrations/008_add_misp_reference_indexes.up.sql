@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_indicators_misp_uuid ON indicators((metadata->>'misp_uuid'));
+CREATE INDEX IF NOT EXISTS idx_campaigns_misp_uuid ON campaigns((metadata->>'misp_uuid'));
+CREATE INDEX IF NOT EXISTS idx_actors_misp_uuid ON threat_actors((metadata->>'misp_uuid'));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_actors_motivation;
+DROP INDEX IF EXISTS idx_actors_country;
+DROP TABLE IF EXISTS threat_actor_aliases;
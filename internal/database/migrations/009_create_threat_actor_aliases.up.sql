@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS threat_actor_aliases (
+    actor_id UUID NOT NULL REFERENCES threat_actors(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL,
+    added_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (actor_id, alias)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_actor_aliases_alias ON threat_actor_aliases(LOWER(alias));
+CREATE INDEX IF NOT EXISTS idx_actors_country ON threat_actors(country);
+CREATE INDEX IF NOT EXISTS idx_actors_motivation ON threat_actors(motivation);
@@ -0,0 +1,33 @@
+DROP INDEX IF EXISTS idx_api_keys_tenant;
+DROP INDEX IF EXISTS idx_actors_tenant;
+DROP INDEX IF EXISTS idx_campaigns_tenant_status;
+DROP INDEX IF EXISTS idx_indicators_tenant_type_active;
+DROP INDEX IF EXISTS idx_indicators_tenant_created_at;
+
+ALTER TABLE threat_actor_aliases DROP CONSTRAINT IF EXISTS threat_actor_aliases_tenant_actor_fkey;
+ALTER TABLE indicator_actors DROP CONSTRAINT IF EXISTS indicator_actors_tenant_actor_fkey;
+ALTER TABLE indicator_actors DROP CONSTRAINT IF EXISTS indicator_actors_tenant_indicator_fkey;
+ALTER TABLE indicator_campaigns DROP CONSTRAINT IF EXISTS indicator_campaigns_tenant_campaign_fkey;
+ALTER TABLE indicator_campaigns DROP CONSTRAINT IF EXISTS indicator_campaigns_tenant_indicator_fkey;
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_tenant_threat_actor_fkey;
+
+ALTER TABLE indicators DROP CONSTRAINT IF EXISTS indicators_tenant_id_id_key;
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_tenant_id_id_key;
+ALTER TABLE threat_actors DROP CONSTRAINT IF EXISTS threat_actors_tenant_id_id_key;
+
+DROP INDEX IF EXISTS idx_actor_aliases_tenant_alias;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_actor_aliases_alias ON threat_actor_aliases(LOWER(alias));
+
+DROP INDEX IF EXISTS idx_indicators_tenant_type_normalized_value;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_indicators_type_normalized_value ON indicators(type, normalized_value);
+
+DROP INDEX IF EXISTS idx_actors_tenant_name;
+ALTER TABLE threat_actors ADD CONSTRAINT threat_actors_name_key UNIQUE (name);
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE threat_actor_aliases DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE indicator_actors DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE indicator_campaigns DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE indicators DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE threat_actors DROP COLUMN IF EXISTS tenant_id;
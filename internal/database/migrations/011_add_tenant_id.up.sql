@@ -0,0 +1,39 @@
+ALTER TABLE threat_actors ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE indicator_campaigns ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE indicator_actors ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE threat_actor_aliases ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+ALTER TABLE threat_actors DROP CONSTRAINT IF EXISTS threat_actors_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_actors_tenant_name ON threat_actors(tenant_id, name);
+
+DROP INDEX IF EXISTS idx_indicators_type_normalized_value;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_indicators_tenant_type_normalized_value ON indicators(tenant_id, type, normalized_value);
+
+DROP INDEX IF EXISTS idx_actor_aliases_alias;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_actor_aliases_tenant_alias ON threat_actor_aliases(tenant_id, LOWER(alias));
+
+ALTER TABLE threat_actors ADD CONSTRAINT threat_actors_tenant_id_id_key UNIQUE (tenant_id, id);
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_tenant_id_id_key UNIQUE (tenant_id, id);
+ALTER TABLE indicators ADD CONSTRAINT indicators_tenant_id_id_key UNIQUE (tenant_id, id);
+
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_tenant_threat_actor_fkey
+    FOREIGN KEY (tenant_id, threat_actor_id) REFERENCES threat_actors(tenant_id, id) ON DELETE SET NULL (threat_actor_id);
+ALTER TABLE indicator_campaigns ADD CONSTRAINT indicator_campaigns_tenant_indicator_fkey
+    FOREIGN KEY (tenant_id, indicator_id) REFERENCES indicators(tenant_id, id) ON DELETE CASCADE;
+ALTER TABLE indicator_campaigns ADD CONSTRAINT indicator_campaigns_tenant_campaign_fkey
+    FOREIGN KEY (tenant_id, campaign_id) REFERENCES campaigns(tenant_id, id) ON DELETE CASCADE;
+ALTER TABLE indicator_actors ADD CONSTRAINT indicator_actors_tenant_indicator_fkey
+    FOREIGN KEY (tenant_id, indicator_id) REFERENCES indicators(tenant_id, id) ON DELETE CASCADE;
+ALTER TABLE indicator_actors ADD CONSTRAINT indicator_actors_tenant_actor_fkey
+    FOREIGN KEY (tenant_id, actor_id) REFERENCES threat_actors(tenant_id, id) ON DELETE CASCADE;
+ALTER TABLE threat_actor_aliases ADD CONSTRAINT threat_actor_aliases_tenant_actor_fkey
+    FOREIGN KEY (tenant_id, actor_id) REFERENCES threat_actors(tenant_id, id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_indicators_tenant_created_at ON indicators(tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_indicators_tenant_type_active ON indicators(tenant_id, type, is_active);
+CREATE INDEX IF NOT EXISTS idx_campaigns_tenant_status ON campaigns(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_actors_tenant ON threat_actors(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant ON api_keys(tenant_id);
@@ -0,0 +1,10 @@
+ALTER TABLE api_keys DROP COLUMN IF EXISTS max_tlp;
+
+DROP INDEX IF EXISTS idx_indicators_tenant_tlp;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS pap;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS tlp;
+ALTER TABLE indicators DROP COLUMN IF EXISTS pap;
+ALTER TABLE indicators DROP COLUMN IF EXISTS tlp;
+
+DROP FUNCTION IF EXISTS marking_rank(VARCHAR);
@@ -0,0 +1,33 @@
+CREATE OR REPLACE FUNCTION marking_rank(level VARCHAR) RETURNS INTEGER
+    LANGUAGE SQL IMMUTABLE
+    AS $$ SELECT array_position(ARRAY['clear', 'green', 'amber', 'red']::VARCHAR[], level) $$;
+
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS tlp VARCHAR(16) NOT NULL DEFAULT 'clear';
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS pap VARCHAR(16) NOT NULL DEFAULT 'clear';
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS tlp VARCHAR(16) NOT NULL DEFAULT 'clear';
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS pap VARCHAR(16) NOT NULL DEFAULT 'clear';
+
+UPDATE indicators
+SET tlp = CASE LOWER(REGEXP_REPLACE(metadata->>'tlp', '^tlp:', '', 'i'))
+    WHEN 'white' THEN 'clear'
+    ELSE LOWER(REGEXP_REPLACE(metadata->>'tlp', '^tlp:', '', 'i'))
+END
+WHERE LOWER(REGEXP_REPLACE(metadata->>'tlp', '^tlp:', '', 'i')) IN ('white', 'clear', 'green', 'amber', 'red');
+
+UPDATE campaigns
+SET tlp = CASE LOWER(REGEXP_REPLACE(metadata->>'tlp', '^tlp:', '', 'i'))
+    WHEN 'white' THEN 'clear'
+    ELSE LOWER(REGEXP_REPLACE(metadata->>'tlp', '^tlp:', '', 'i'))
+END
+WHERE LOWER(REGEXP_REPLACE(metadata->>'tlp', '^tlp:', '', 'i')) IN ('white', 'clear', 'green', 'amber', 'red');
+
+ALTER TABLE indicators ADD CONSTRAINT indicators_tlp_check CHECK (tlp IN ('clear', 'green', 'amber', 'red'));
+ALTER TABLE indicators ADD CONSTRAINT indicators_pap_check CHECK (pap IN ('clear', 'green', 'amber', 'red'));
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_tlp_check CHECK (tlp IN ('clear', 'green', 'amber', 'red'));
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_pap_check CHECK (pap IN ('clear', 'green', 'amber', 'red'));
+
+CREATE INDEX IF NOT EXISTS idx_indicators_tenant_tlp ON indicators(tenant_id, tlp);
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS max_tlp VARCHAR(16) NOT NULL DEFAULT 'red';
+ALTER TABLE api_keys ALTER COLUMN max_tlp SET DEFAULT 'green';
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_max_tlp_check CHECK (max_tlp IN ('clear', 'green', 'amber', 'red'));
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    principal_type VARCHAR(32) NOT NULL,
+    principal_id VARCHAR(255) NOT NULL,
+    principal_name VARCHAR(255),
+    action VARCHAR(32) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id VARCHAR(255),
+    tlp VARCHAR(16) NOT NULL DEFAULT 'red' CHECK (tlp IN ('clear', 'green', 'amber', 'red')),
+    changes JSONB,
+    params JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_tenant_occurred_at ON audit_events(tenant_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_tenant_principal ON audit_events(tenant_id, principal_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_tenant_entity ON audit_events(tenant_id, entity_type, entity_id, occurred_at DESC);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_no_update ON audit_events;
+CREATE TRIGGER audit_events_no_update
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
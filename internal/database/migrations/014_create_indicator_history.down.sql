@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS indicator_campaigns_history ON indicator_campaigns;
+DROP TRIGGER IF EXISTS indicator_actors_history ON indicator_actors;
+DROP TRIGGER IF EXISTS indicators_history ON indicators;
+DROP FUNCTION IF EXISTS record_indicator_campaign_history();
+DROP FUNCTION IF EXISTS record_indicator_actor_history();
+DROP FUNCTION IF EXISTS record_indicator_history();
+DROP TABLE IF EXISTS indicator_campaign_history;
+DROP TABLE IF EXISTS indicator_actor_history;
+DROP TABLE IF EXISTS indicator_history;
@@ -0,0 +1,156 @@
+CREATE TABLE IF NOT EXISTS indicator_history (
+    id BIGSERIAL PRIMARY KEY,
+    indicator_id UUID NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL,
+    version INTEGER NOT NULL,
+    operation VARCHAR(16) NOT NULL CHECK (operation IN ('snapshot', 'create', 'update', 'delete')),
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    type VARCHAR(50) NOT NULL,
+    value VARCHAR(2048) NOT NULL,
+    description TEXT,
+    severity VARCHAR(50),
+    confidence INTEGER,
+    first_seen TIMESTAMP WITH TIME ZONE,
+    last_seen TIMESTAMP WITH TIME ZONE,
+    is_active BOOLEAN,
+    tlp VARCHAR(16) NOT NULL,
+    pap VARCHAR(16) NOT NULL,
+    tags JSONB,
+    metadata JSONB,
+    source VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (indicator_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_indicator_history_tenant_indicator ON indicator_history(tenant_id, indicator_id, valid_from);
+
+CREATE TABLE IF NOT EXISTS indicator_actor_history (
+    id BIGSERIAL PRIMARY KEY,
+    indicator_id UUID NOT NULL,
+    actor_id UUID NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL,
+    attribution_confidence INTEGER,
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    valid_to TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_indicator_actor_history_indicator ON indicator_actor_history(tenant_id, indicator_id, valid_from);
+
+CREATE TABLE IF NOT EXISTS indicator_campaign_history (
+    id BIGSERIAL PRIMARY KEY,
+    indicator_id UUID NOT NULL,
+    campaign_id UUID NOT NULL,
+    tenant_id VARCHAR(64) NOT NULL,
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    valid_to TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_indicator_campaign_history_indicator ON indicator_campaign_history(tenant_id, indicator_id, valid_from);
+
+INSERT INTO indicator_history (
+    indicator_id, tenant_id, version, operation, valid_from,
+    type, value, description, severity, confidence, first_seen, last_seen, is_active,
+    tlp, pap, tags, metadata, source, created_at, updated_at
+)
+SELECT i.id, i.tenant_id, 1, 'snapshot', COALESCE(i.updated_at, i.created_at, CURRENT_TIMESTAMP),
+       i.type, i.value, i.description, i.severity, i.confidence, i.first_seen, i.last_seen, i.is_active,
+       i.tlp, i.pap, i.tags, i.metadata, i.source, i.created_at, i.updated_at
+FROM indicators i
+WHERE NOT EXISTS (SELECT 1 FROM indicator_history h WHERE h.indicator_id = i.id);
+
+INSERT INTO indicator_actor_history (indicator_id, actor_id, tenant_id, attribution_confidence, valid_from)
+SELECT ia.indicator_id, ia.actor_id, ia.tenant_id, ia.attribution_confidence, COALESCE(ia.added_at, CURRENT_TIMESTAMP)
+FROM indicator_actors ia
+WHERE NOT EXISTS (
+    SELECT 1 FROM indicator_actor_history h
+    WHERE h.indicator_id = ia.indicator_id AND h.actor_id = ia.actor_id AND h.valid_to IS NULL
+);
+
+INSERT INTO indicator_campaign_history (indicator_id, campaign_id, tenant_id, valid_from)
+SELECT ic.indicator_id, ic.campaign_id, ic.tenant_id, COALESCE(ic.added_at, CURRENT_TIMESTAMP)
+FROM indicator_campaigns ic
+WHERE NOT EXISTS (
+    SELECT 1 FROM indicator_campaign_history h
+    WHERE h.indicator_id = ic.indicator_id AND h.campaign_id = ic.campaign_id AND h.valid_to IS NULL
+);
+
+CREATE OR REPLACE FUNCTION record_indicator_history() RETURNS TRIGGER AS $$
+DECLARE
+    rec indicators%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    IF TG_OP = 'UPDATE' AND
+        (NEW.type, NEW.value, NEW.description, NEW.severity, NEW.confidence, NEW.first_seen, NEW.last_seen,
+         NEW.is_active, NEW.tlp, NEW.pap, NEW.tags, NEW.metadata, NEW.source)
+        IS NOT DISTINCT FROM
+        (OLD.type, OLD.value, OLD.description, OLD.severity, OLD.confidence, OLD.first_seen, OLD.last_seen,
+         OLD.is_active, OLD.tlp, OLD.pap, OLD.tags, OLD.metadata, OLD.source) THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO indicator_history (
+        indicator_id, tenant_id, version, operation,
+        type, value, description, severity, confidence, first_seen, last_seen, is_active,
+        tlp, pap, tags, metadata, source, created_at, updated_at
+    )
+    VALUES (
+        rec.id, rec.tenant_id,
+        COALESCE((SELECT MAX(version) FROM indicator_history WHERE indicator_id = rec.id), 0) + 1,
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        rec.type, rec.value, rec.description, rec.severity, rec.confidence, rec.first_seen, rec.last_seen, rec.is_active,
+        rec.tlp, rec.pap, rec.tags, rec.metadata, rec.source, rec.created_at, rec.updated_at
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS indicators_history ON indicators;
+CREATE TRIGGER indicators_history
+    AFTER INSERT OR UPDATE OR DELETE ON indicators
+    FOR EACH ROW EXECUTE FUNCTION record_indicator_history();
+
+CREATE OR REPLACE FUNCTION record_indicator_actor_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE indicator_actor_history
+        SET valid_to = CURRENT_TIMESTAMP
+        WHERE indicator_id = OLD.indicator_id AND actor_id = OLD.actor_id AND valid_to IS NULL;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO indicator_actor_history (indicator_id, actor_id, tenant_id, attribution_confidence)
+        VALUES (NEW.indicator_id, NEW.actor_id, NEW.tenant_id, NEW.attribution_confidence);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS indicator_actors_history ON indicator_actors;
+CREATE TRIGGER indicator_actors_history
+    AFTER INSERT OR UPDATE OR DELETE ON indicator_actors
+    FOR EACH ROW EXECUTE FUNCTION record_indicator_actor_history();
+
+CREATE OR REPLACE FUNCTION record_indicator_campaign_history() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE indicator_campaign_history
+        SET valid_to = CURRENT_TIMESTAMP
+        WHERE indicator_id = OLD.indicator_id AND campaign_id = OLD.campaign_id AND valid_to IS NULL;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        INSERT INTO indicator_campaign_history (indicator_id, campaign_id, tenant_id)
+        VALUES (NEW.indicator_id, NEW.campaign_id, NEW.tenant_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS indicator_campaigns_history ON indicator_campaigns;
+CREATE TRIGGER indicator_campaigns_history
+    AFTER INSERT OR UPDATE OR DELETE ON indicator_campaigns
+    FOR EACH ROW EXECUTE FUNCTION record_indicator_campaign_history();
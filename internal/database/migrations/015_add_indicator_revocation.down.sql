@@ -0,0 +1,61 @@
+CREATE OR REPLACE FUNCTION record_indicator_history() RETURNS TRIGGER AS $$
+DECLARE
+    rec indicators%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    IF TG_OP = 'UPDATE' AND
+        (NEW.type, NEW.value, NEW.description, NEW.severity, NEW.confidence, NEW.first_seen, NEW.last_seen,
+         NEW.is_active, NEW.tlp, NEW.pap, NEW.tags, NEW.metadata, NEW.source)
+        IS NOT DISTINCT FROM
+        (OLD.type, OLD.value, OLD.description, OLD.severity, OLD.confidence, OLD.first_seen, OLD.last_seen,
+         OLD.is_active, OLD.tlp, OLD.pap, OLD.tags, OLD.metadata, OLD.source) THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO indicator_history (
+        indicator_id, tenant_id, version, operation,
+        type, value, description, severity, confidence, first_seen, last_seen, is_active,
+        tlp, pap, tags, metadata, source, created_at, updated_at
+    )
+    VALUES (
+        rec.id, rec.tenant_id,
+        COALESCE((SELECT MAX(version) FROM indicator_history WHERE indicator_id = rec.id), 0) + 1,
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        rec.type, rec.value, rec.description, rec.severity, rec.confidence, rec.first_seen, rec.last_seen, rec.is_active,
+        rec.tlp, rec.pap, rec.tags, rec.metadata, rec.source, rec.created_at, rec.updated_at
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS idx_indicators_tenant_type_normalized_value;
+ALTER TABLE indicators DROP COLUMN IF EXISTS normalized_value;
+ALTER TABLE indicators ADD COLUMN normalized_value TEXT GENERATED ALWAYS AS (
+    CASE
+        WHEN type = 'domain' THEN rtrim(lower(btrim(value)), '.')
+        WHEN type = 'url' AND btrim(value) ~ '^[^/?#]+://' THEN
+            lower(substring(btrim(value) from '^[^/?#]+://[^/?#]*')) ||
+            substring(btrim(value) from '^[^/?#]+://[^/?#]*(.*)$')
+        WHEN type = 'url' THEN btrim(value)
+        ELSE lower(btrim(value))
+    END
+) STORED;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_indicators_tenant_type_normalized_value
+    ON indicators(tenant_id, type, normalized_value);
+
+DROP FUNCTION IF EXISTS indicator_normalized_value(VARCHAR, VARCHAR);
+
+ALTER TABLE indicator_history DROP COLUMN IF EXISTS revocation_reason;
+ALTER TABLE indicator_history DROP COLUMN IF EXISTS revoked_at;
+
+DROP INDEX IF EXISTS idx_indicators_tenant_unrevoked;
+
+ALTER TABLE indicators DROP CONSTRAINT IF EXISTS indicators_revocation_reason_revoked_check;
+ALTER TABLE indicators DROP CONSTRAINT IF EXISTS indicators_revocation_reason_check;
+ALTER TABLE indicators DROP COLUMN IF EXISTS revocation_reason;
+ALTER TABLE indicators DROP COLUMN IF EXISTS revoked_at;
@@ -0,0 +1,57 @@
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS revocation_reason VARCHAR(32);
+ALTER TABLE indicators ADD CONSTRAINT indicators_revocation_reason_check
+    CHECK (revocation_reason IN ('false_positive', 'expired', 'superseded'));
+ALTER TABLE indicators ADD CONSTRAINT indicators_revocation_reason_revoked_check
+    CHECK (revocation_reason IS NULL OR revoked_at IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_indicators_tenant_unrevoked ON indicators(tenant_id, created_at) WHERE revoked_at IS NULL;
+
+ALTER TABLE indicator_history ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE indicator_history ADD COLUMN IF NOT EXISTS revocation_reason VARCHAR(32);
+
+CREATE OR REPLACE FUNCTION indicator_normalized_value(indicator_type VARCHAR, value VARCHAR) RETURNS TEXT
+    LANGUAGE SQL IMMUTABLE
+    AS $$ SELECT CASE
+        WHEN indicator_type = 'domain' THEN rtrim(lower(btrim(value)), '.')
+        WHEN indicator_type = 'url' AND btrim(value) ~ '^[^/?#]+://' THEN
+            lower(substring(btrim(value) from '^[^/?#]+://[^/?#]*')) ||
+            substring(btrim(value) from '^[^/?#]+://[^/?#]*(.*)$')
+        WHEN indicator_type = 'url' THEN btrim(value)
+        ELSE lower(btrim(value))
+    END $$;
+
+CREATE OR REPLACE FUNCTION record_indicator_history() RETURNS TRIGGER AS $$
+DECLARE
+    rec indicators%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    IF TG_OP = 'UPDATE' AND
+        (NEW.type, NEW.value, NEW.description, NEW.severity, NEW.confidence, NEW.first_seen, NEW.last_seen,
+         NEW.is_active, NEW.tlp, NEW.pap, NEW.tags, NEW.metadata, NEW.source, NEW.revoked_at, NEW.revocation_reason)
+        IS NOT DISTINCT FROM
+        (OLD.type, OLD.value, OLD.description, OLD.severity, OLD.confidence, OLD.first_seen, OLD.last_seen,
+         OLD.is_active, OLD.tlp, OLD.pap, OLD.tags, OLD.metadata, OLD.source, OLD.revoked_at, OLD.revocation_reason) THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO indicator_history (
+        indicator_id, tenant_id, version, operation,
+        type, value, description, severity, confidence, first_seen, last_seen, is_active,
+        tlp, pap, tags, metadata, source, revoked_at, revocation_reason, created_at, updated_at
+    )
+    VALUES (
+        rec.id, rec.tenant_id,
+        COALESCE((SELECT MAX(version) FROM indicator_history WHERE indicator_id = rec.id), 0) + 1,
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        rec.type, rec.value, rec.description, rec.severity, rec.confidence, rec.first_seen, rec.last_seen, rec.is_active,
+        rec.tlp, rec.pap, rec.tags, rec.metadata, rec.source, rec.revoked_at, rec.revocation_reason, rec.created_at, rec.updated_at
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
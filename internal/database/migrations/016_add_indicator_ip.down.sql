@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_indicators_ip;
+
+ALTER TABLE indicators DROP COLUMN IF EXISTS ip;
+
+DROP FUNCTION IF EXISTS indicator_ip(VARCHAR, VARCHAR);
@@ -0,0 +1,14 @@
+CREATE OR REPLACE FUNCTION indicator_ip(indicator_type VARCHAR, value VARCHAR) RETURNS INET AS $$
+BEGIN
+    IF indicator_type <> 'ip' THEN
+        RETURN NULL;
+    END IF;
+    RETURN btrim(value)::inet;
+EXCEPTION WHEN invalid_text_representation THEN
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS ip INET GENERATED ALWAYS AS (indicator_ip(type, value)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_indicators_ip ON indicators USING GIST (ip inet_ops) WHERE ip IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_indicators_tld;
+DROP INDEX IF EXISTS idx_indicators_registered_domain;
+DROP INDEX IF EXISTS idx_indicators_host_reversed;
+
+ALTER TABLE indicators DROP COLUMN IF EXISTS tld;
+ALTER TABLE indicators DROP COLUMN IF EXISTS registered_domain;
+ALTER TABLE indicators DROP COLUMN IF EXISTS host;
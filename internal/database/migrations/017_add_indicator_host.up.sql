@@ -0,0 +1,7 @@
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS host VARCHAR(253);
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS registered_domain VARCHAR(253);
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS tld VARCHAR(63);
+
+CREATE INDEX IF NOT EXISTS idx_indicators_host_reversed ON indicators(tenant_id, reverse(host) text_pattern_ops) WHERE host IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_indicators_registered_domain ON indicators(tenant_id, registered_domain) WHERE registered_domain IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_indicators_tld ON indicators(tenant_id, tld) WHERE tld IS NOT NULL;
@@ -0,0 +1,45 @@
+CREATE OR REPLACE FUNCTION record_indicator_history() RETURNS TRIGGER AS $$
+DECLARE
+    rec indicators%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    IF TG_OP = 'UPDATE' AND
+        (NEW.type, NEW.value, NEW.description, NEW.severity, NEW.confidence, NEW.first_seen, NEW.last_seen,
+         NEW.is_active, NEW.tlp, NEW.pap, NEW.tags, NEW.metadata, NEW.source, NEW.revoked_at, NEW.revocation_reason)
+        IS NOT DISTINCT FROM
+        (OLD.type, OLD.value, OLD.description, OLD.severity, OLD.confidence, OLD.first_seen, OLD.last_seen,
+         OLD.is_active, OLD.tlp, OLD.pap, OLD.tags, OLD.metadata, OLD.source, OLD.revoked_at, OLD.revocation_reason) THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO indicator_history (
+        indicator_id, tenant_id, version, operation,
+        type, value, description, severity, confidence, first_seen, last_seen, is_active,
+        tlp, pap, tags, metadata, source, revoked_at, revocation_reason, created_at, updated_at
+    )
+    VALUES (
+        rec.id, rec.tenant_id,
+        COALESCE((SELECT MAX(version) FROM indicator_history WHERE indicator_id = rec.id), 0) + 1,
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        rec.type, rec.value, rec.description, rec.severity, rec.confidence, rec.first_seen, rec.last_seen, rec.is_active,
+        rec.tlp, rec.pap, rec.tags, rec.metadata, rec.source, rec.revoked_at, rec.revocation_reason, rec.created_at, rec.updated_at
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE indicator_history DROP COLUMN IF EXISTS hash_algo;
+
+DROP INDEX IF EXISTS idx_indicators_sample;
+DROP INDEX IF EXISTS idx_indicators_hash_algo;
+
+ALTER TABLE indicators DROP CONSTRAINT IF EXISTS indicators_sample_type_check;
+ALTER TABLE indicators DROP CONSTRAINT IF EXISTS indicators_hash_algo_type_check;
+ALTER TABLE indicators DROP CONSTRAINT IF EXISTS indicators_hash_algo_check;
+ALTER TABLE indicators DROP COLUMN IF EXISTS sample_id;
+ALTER TABLE indicators DROP COLUMN IF EXISTS hash_algo;
@@ -0,0 +1,71 @@
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS hash_algo VARCHAR(16);
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS sample_id UUID;
+ALTER TABLE indicators ADD CONSTRAINT indicators_hash_algo_check
+    CHECK (hash_algo IN ('md5', 'sha1', 'sha256', 'sha512', 'ssdeep', 'tlsh', 'imphash'));
+ALTER TABLE indicators ADD CONSTRAINT indicators_hash_algo_type_check
+    CHECK (hash_algo IS NULL OR type = 'hash');
+ALTER TABLE indicators ADD CONSTRAINT indicators_sample_type_check
+    CHECK (sample_id IS NULL OR type = 'hash');
+
+UPDATE indicators SET hash_algo = CASE
+        WHEN btrim(value) ~* '^[0-9a-f]{32}$' THEN 'md5'
+        WHEN btrim(value) ~* '^[0-9a-f]{40}$' THEN 'sha1'
+        WHEN btrim(value) ~* '^[0-9a-f]{64}$' THEN 'sha256'
+        WHEN btrim(value) ~* '^[0-9a-f]{128}$' THEN 'sha512'
+        WHEN btrim(value) ~* '^(t1)?[0-9a-f]{70}$' THEN 'tlsh'
+        WHEN btrim(value) ~ '^[0-9]+:[0-9A-Za-z+/]+:[0-9A-Za-z+/]+$' THEN 'ssdeep'
+    END
+WHERE type = 'hash';
+
+CREATE INDEX IF NOT EXISTS idx_indicators_hash_algo ON indicators(tenant_id, hash_algo) WHERE hash_algo IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_indicators_sample ON indicators(sample_id) WHERE sample_id IS NOT NULL;
+
+ALTER TABLE indicator_history ADD COLUMN IF NOT EXISTS hash_algo VARCHAR(16);
+
+UPDATE indicator_history SET hash_algo = CASE
+        WHEN btrim(value) ~* '^[0-9a-f]{32}$' THEN 'md5'
+        WHEN btrim(value) ~* '^[0-9a-f]{40}$' THEN 'sha1'
+        WHEN btrim(value) ~* '^[0-9a-f]{64}$' THEN 'sha256'
+        WHEN btrim(value) ~* '^[0-9a-f]{128}$' THEN 'sha512'
+        WHEN btrim(value) ~* '^(t1)?[0-9a-f]{70}$' THEN 'tlsh'
+        WHEN btrim(value) ~ '^[0-9]+:[0-9A-Za-z+/]+:[0-9A-Za-z+/]+$' THEN 'ssdeep'
+    END
+WHERE type = 'hash';
+
+CREATE OR REPLACE FUNCTION record_indicator_history() RETURNS TRIGGER AS $$
+DECLARE
+    rec indicators%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    IF TG_OP = 'UPDATE' AND
+        (NEW.type, NEW.value, NEW.description, NEW.severity, NEW.confidence, NEW.first_seen, NEW.last_seen,
+         NEW.is_active, NEW.tlp, NEW.pap, NEW.tags, NEW.metadata, NEW.source, NEW.revoked_at, NEW.revocation_reason,
+         NEW.hash_algo)
+        IS NOT DISTINCT FROM
+        (OLD.type, OLD.value, OLD.description, OLD.severity, OLD.confidence, OLD.first_seen, OLD.last_seen,
+         OLD.is_active, OLD.tlp, OLD.pap, OLD.tags, OLD.metadata, OLD.source, OLD.revoked_at, OLD.revocation_reason,
+         OLD.hash_algo) THEN
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO indicator_history (
+        indicator_id, tenant_id, version, operation,
+        type, value, description, severity, confidence, first_seen, last_seen, is_active,
+        tlp, pap, tags, metadata, source, revoked_at, revocation_reason, hash_algo, created_at, updated_at
+    )
+    VALUES (
+        rec.id, rec.tenant_id,
+        COALESCE((SELECT MAX(version) FROM indicator_history WHERE indicator_id = rec.id), 0) + 1,
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        rec.type, rec.value, rec.description, rec.severity, rec.confidence, rec.first_seen, rec.last_seen, rec.is_active,
+        rec.tlp, rec.pap, rec.tags, rec.metadata, rec.source, rec.revoked_at, rec.revocation_reason, rec.hash_algo,
+        rec.created_at, rec.updated_at
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
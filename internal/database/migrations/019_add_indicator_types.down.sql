@@ -0,0 +1,29 @@
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM indicators WHERE type NOT IN ('ip', 'domain', 'url', 'hash')) THEN
+        RAISE EXCEPTION 'indicators of types added by 019_add_indicator_types exist; delete or retype them before rolling back';
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS idx_indicators_tenant_type_normalized_value;
+ALTER TABLE indicators DROP COLUMN IF EXISTS normalized_value;
+
+CREATE OR REPLACE FUNCTION indicator_normalized_value(indicator_type VARCHAR, value VARCHAR) RETURNS TEXT
+    LANGUAGE SQL IMMUTABLE
+    AS $$ SELECT CASE
+        WHEN indicator_type = 'domain' THEN rtrim(lower(btrim(value)), '.')
+        WHEN indicator_type = 'url' AND btrim(value) ~ '^[^/?#]+://' THEN
+            lower(substring(btrim(value) from '^[^/?#]+://[^/?#]*')) ||
+            substring(btrim(value) from '^[^/?#]+://[^/?#]*(.*)$')
+        WHEN indicator_type = 'url' THEN btrim(value)
+        ELSE lower(btrim(value))
+    END $$;
+
+ALTER TABLE indicators ADD COLUMN normalized_value TEXT
+    GENERATED ALWAYS AS (indicator_normalized_value(type, value)) STORED;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_indicators_tenant_type_normalized_value
+    ON indicators(tenant_id, type, normalized_value);
+
+ALTER TABLE indicators DROP CONSTRAINT IF EXISTS indicators_type_check;
+ALTER TABLE indicators ADD CONSTRAINT indicators_type_check
+    CHECK (type IN ('ip', 'domain', 'url', 'hash'));
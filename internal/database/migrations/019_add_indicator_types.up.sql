@@ -0,0 +1,24 @@
+ALTER TABLE indicators DROP CONSTRAINT IF EXISTS indicators_type_check;
+ALTER TABLE indicators ADD CONSTRAINT indicators_type_check
+    CHECK (type IN ('ip', 'domain', 'url', 'hash', 'email', 'asn', 'cve', 'ja3', 'ja4',
+                    'file_path', 'mutex', 'registry_key', 'user_agent'));
+
+CREATE OR REPLACE FUNCTION indicator_normalized_value(indicator_type VARCHAR, value VARCHAR) RETURNS TEXT
+    LANGUAGE SQL IMMUTABLE
+    AS $$ SELECT CASE
+        WHEN indicator_type = 'domain' THEN rtrim(lower(btrim(value)), '.')
+        WHEN indicator_type = 'url' AND btrim(value) ~ '^[^/?#]+://' THEN
+            lower(substring(btrim(value) from '^[^/?#]+://[^/?#]*')) ||
+            substring(btrim(value) from '^[^/?#]+://[^/?#]*(.*)$')
+        WHEN indicator_type = 'url' THEN btrim(value)
+        WHEN indicator_type IN ('mutex', 'user_agent') THEN btrim(value)
+        WHEN indicator_type = 'file_path' AND btrim(value) ~ '^~?/' THEN btrim(value)
+        ELSE lower(btrim(value))
+    END $$;
+
+DROP INDEX IF EXISTS idx_indicators_tenant_type_normalized_value;
+ALTER TABLE indicators DROP COLUMN IF EXISTS normalized_value;
+ALTER TABLE indicators ADD COLUMN normalized_value TEXT
+    GENERATED ALWAYS AS (indicator_normalized_value(type, value)) STORED;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_indicators_tenant_type_normalized_value
+    ON indicators(tenant_id, type, normalized_value);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_threat_actors_name_search;
+DROP INDEX IF EXISTS idx_campaigns_name_search;
+DROP INDEX IF EXISTS idx_indicators_search;
+ALTER TABLE indicators DROP COLUMN IF EXISTS search_vector;
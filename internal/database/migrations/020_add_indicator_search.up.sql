@@ -0,0 +1,10 @@
+ALTER TABLE indicators ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', value), 'A') ||
+    setweight(jsonb_to_tsvector('english', COALESCE(tags, '[]'::jsonb), '["string"]'), 'B') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE(source, '')), 'D')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_indicators_search ON indicators USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_campaigns_name_search ON campaigns USING GIN (to_tsvector('english', name));
+CREATE INDEX IF NOT EXISTS idx_threat_actors_name_search ON threat_actors USING GIN (to_tsvector('english', name));
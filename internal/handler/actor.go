@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type ActorHandler struct {
+	service service.ActorServiceInterface
+}
+
+func NewActorHandler(svc service.ActorServiceInterface) *ActorHandler {
+	return &ActorHandler{service: svc}
+}
+
+func (h *ActorHandler) List(w http.ResponseWriter, r *http.Request) {
+	params := model.ActorListParams{
+		Name:       r.URL.Query().Get("name"),
+		Country:    r.URL.Query().Get("country"),
+		Motivation: r.URL.Query().Get("motivation"),
+	}
+
+	if c := r.URL.Query().Get("min_confidence"); c != "" {
+		parsed, err := strconv.Atoi(c)
+		if err != nil || parsed < 0 || parsed > 100 {
+			respondBadRequest(w, "Invalid min_confidence. Must be an integer between 0 and 100")
+			return
+		}
+		params.MinConfidence = parsed
+	}
+
+	params.Page, params.Limit = parsePagination(r)
+
+	result, err := h.service.List(r.Context(), params)
+	if err != nil {
+		slog.Error("Failed to list threat actors", "error", err)
+		respondInternalError(w)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+func (h *ActorHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := actorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	actor, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, actor)
+}
+
+func (h *ActorHandler) GetIndicators(w http.ResponseWriter, r *http.Request) {
+	id, ok := actorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	params := model.ActorIndicatorParams{Type: r.URL.Query().Get("type")}
+	if params.Type != "" && !model.IndicatorType(params.Type).IsValid() {
+		respondBadRequest(w, "Invalid indicator type. Must be one of: "+model.IndicatorTypeNames())
+		return
+	}
+	params.Page, params.Limit = parsePagination(r)
+
+	result, err := h.service.GetIndicators(r.Context(), id, params)
+	if err != nil {
+		h.handleError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+func (h *ActorHandler) SetAliases(w http.ResponseWriter, r *http.Request) {
+	id, ok := actorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.ActorAliasesInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	actor, err := h.service.SetAliases(r.Context(), id, input)
+	if err != nil {
+		h.handleError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, actor)
+}
+
+func (h *ActorHandler) handleError(w http.ResponseWriter, err error, id string) {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		respondValidationError(w, validationErr.Error())
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		respondNotFound(w, "Threat actor not found")
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		respondConflict(w, "An alias is already assigned to another threat actor")
+		return
+	}
+	slog.Error("Failed to handle threat actor request", "error", err, "id", id)
+	respondInternalError(w)
+}
+
+func actorIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondBadRequest(w, "Threat actor ID is required")
+		return "", false
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		respondBadRequest(w, "Invalid threat actor ID format")
+		return "", false
+	}
+
+	return id, true
+}
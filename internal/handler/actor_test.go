@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testActorID = "550e8400-e29b-41d4-a716-446655440000"
+
+func setupActorRouter(handler *ActorHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/actors", handler.List)
+	r.Get("/api/actors/{id}", handler.GetByID)
+	r.Get("/api/actors/{id}/indicators", handler.GetIndicators)
+	r.Put("/api/actors/{id}/aliases", handler.SetAliases)
+	return r
+}
+
+func TestActorHandler_List_ParsesFilters(t *testing.T) {
+	mockService := new(MockActorService)
+	r := setupActorRouter(NewActorHandler(mockService))
+
+	params := model.ActorListParams{Name: "fancy", Country: "RU", Motivation: "espionage", MinConfidence: 60, Page: 2, Limit: 10}
+	mockService.On("List", mock.Anything, params).Return(&model.ActorListResult{
+		Data: []model.ThreatActorWithCount{
+			{ThreatActor: model.ThreatActor{ID: testActorID, Name: "APT28", Aliases: []string{"Fancy Bear"}}, IndicatorCount: 4},
+		},
+		Total: 11, Page: 2, Limit: 10, TotalPages: 2,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/actors?name=fancy&country=RU&motivation=espionage&min_confidence=60&page=2&limit=10", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"aliases":["Fancy Bear"]`)
+	mockService.AssertExpectations(t)
+}
+
+func TestActorHandler_List_InvalidConfidence(t *testing.T) {
+	r := setupActorRouter(NewActorHandler(new(MockActorService)))
+
+	req := httptest.NewRequest("GET", "/api/actors?min_confidence=high", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestActorHandler_GetByID(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		result *model.ThreatActorDetail
+		err    error
+		status int
+	}{
+		{
+			name: "success",
+			id:   testActorID,
+			result: &model.ThreatActorDetail{
+				ThreatActor:     model.ThreatActor{ID: testActorID, Name: "APT28"},
+				Campaigns:       []model.CampaignSummary{{ID: "c1", Name: "Operation Test", Active: true}},
+				IndicatorCounts: map[string]int{"ip": 3},
+			},
+			status: http.StatusOK,
+		},
+		{name: "not found", id: testActorID, err: repository.ErrNotFound, status: http.StatusNotFound},
+		{name: "invalid id", id: "not-a-uuid", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockActorService)
+			r := setupActorRouter(NewActorHandler(mockService))
+			if tt.result != nil || tt.err != nil {
+				mockService.On("GetByID", mock.Anything, tt.id).Return(tt.result, tt.err)
+			}
+
+			req := httptest.NewRequest("GET", "/api/actors/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestActorHandler_GetIndicators(t *testing.T) {
+	mockService := new(MockActorService)
+	r := setupActorRouter(NewActorHandler(mockService))
+
+	params := model.ActorIndicatorParams{Type: "domain", Page: 1, Limit: 20}
+	mockService.On("GetIndicators", mock.Anything, testActorID, params).Return(&model.ActorIndicatorResult{
+		Data: []model.ActorIndicator{
+			{Indicator: model.Indicator{ID: "i1", Type: model.IndicatorTypeDomain, Value: "evil.example"}, AttributionConfidence: 90},
+		},
+		Total: 1, Page: 1, Limit: 20, TotalPages: 1,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/actors/"+testActorID+"/indicators?type=domain", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response APIResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.True(t, response.Success)
+	assert.Contains(t, w.Body.String(), `"attribution_confidence":90`)
+	mockService.AssertExpectations(t)
+}
+
+func TestActorHandler_GetIndicators_InvalidType(t *testing.T) {
+	r := setupActorRouter(NewActorHandler(new(MockActorService)))
+
+	req := httptest.NewRequest("GET", "/api/actors/"+testActorID+"/indicators?type=btc_wallet", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestActorHandler_SetAliases_Conflict(t *testing.T) {
+	mockService := new(MockActorService)
+	r := setupActorRouter(NewActorHandler(mockService))
+
+	input := model.ActorAliasesInput{Aliases: []string{"Fancy Bear"}}
+	mockService.On("SetAliases", mock.Anything, testActorID, input).Return(nil, repository.ErrConflict)
+
+	req := httptest.NewRequest("PUT", "/api/actors/"+testActorID+"/aliases", strings.NewReader(`{"aliases":["Fancy Bear"]}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyServiceInterface
+}
+
+func NewAPIKeyHandler(svc service.APIKeyServiceInterface) *APIKeyHandler {
+	return &APIKeyHandler{service: svc}
+}
+
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.List(r.Context())
+	if err != nil {
+		slog.Error("Failed to list api keys", "error", err)
+		respondInternalError(w)
+		return
+	}
+
+	respondSuccess(w, keys)
+}
+
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input model.APIKeyInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	key, err := h.service.Create(r.Context(), input)
+	if err != nil {
+		h.handleError(w, err, "")
+		return
+	}
+
+	respondCreated(w, key)
+}
+
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		respondBadRequest(w, "Invalid API key ID format")
+		return
+	}
+
+	key, err := h.service.Revoke(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, key)
+}
+
+func (h *APIKeyHandler) handleError(w http.ResponseWriter, err error, id string) {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		respondValidationError(w, validationErr.Error())
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		respondNotFound(w, "API key not found")
+		return
+	}
+	slog.Error("Failed to handle api key request", "error", err, "id", id)
+	respondInternalError(w)
+}
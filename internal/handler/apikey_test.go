@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testAPIKeyID = "7c9e6679-7425-40de-944b-e07fc1f90ae7"
+
+func setupAPIKeyRouter(handler *APIKeyHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/admin/api-keys", handler.List)
+	r.Post("/api/admin/api-keys", handler.Create)
+	r.Delete("/api/admin/api-keys/{id}", handler.Revoke)
+	return r
+}
+
+func TestAPIKeyHandler_Create_Success(t *testing.T) {
+	mockService := new(MockAPIKeyService)
+	r := setupAPIKeyRouter(NewAPIKeyHandler(mockService))
+
+	input := model.APIKeyInput{Name: "siem", Scopes: []string{"read", "export"}}
+	mockService.On("Create", mock.Anything, input).Return(&model.IssuedAPIKey{
+		APIKey: model.APIKey{ID: testAPIKeyID, Name: "siem", Prefix: "tia_0123456789ab", Scopes: input.Scopes},
+		Key:    "tia_0123456789ab_secret",
+	}, nil)
+
+	req := httptest.NewRequest("POST", "/api/admin/api-keys", strings.NewReader(`{"name":"siem","scopes":["read","export"]}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response struct {
+		Success bool               `json:"success"`
+		Data    model.IssuedAPIKey `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.True(t, response.Success)
+	assert.Equal(t, "tia_0123456789ab_secret", response.Data.Key)
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_Create_ValidationError(t *testing.T) {
+	mockService := new(MockAPIKeyService)
+	r := setupAPIKeyRouter(NewAPIKeyHandler(mockService))
+
+	mockService.On("Create", mock.Anything, mock.Anything).Return(nil, &service.ValidationError{Field: "scopes", Message: "unknown scope: root"})
+
+	req := httptest.NewRequest("POST", "/api/admin/api-keys", strings.NewReader(`{"name":"siem","scopes":["root"]}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestAPIKeyHandler_List_DoesNotExposeSecrets(t *testing.T) {
+	mockService := new(MockAPIKeyService)
+	r := setupAPIKeyRouter(NewAPIKeyHandler(mockService))
+
+	mockService.On("List", mock.Anything).Return([]model.APIKey{
+		{ID: testAPIKeyID, Name: "siem", Prefix: "tia_0123456789ab", Scopes: []string{"read"}, CreatedAt: time.Now()},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/admin/api-keys", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "tia_0123456789ab")
+	assert.NotContains(t, w.Body.String(), `"key"`)
+}
+
+func TestAPIKeyHandler_Revoke(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		setupMock  func(*MockAPIKeyService)
+		wantStatus int
+	}{
+		{
+			name: "revoked",
+			id:   testAPIKeyID,
+			setupMock: func(m *MockAPIKeyService) {
+				now := time.Now()
+				m.On("Revoke", mock.Anything, testAPIKeyID).Return(&model.APIKey{ID: testAPIKeyID, RevokedAt: &now}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			id:         "not-a-uuid",
+			setupMock:  func(m *MockAPIKeyService) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   testAPIKeyID,
+			setupMock: func(m *MockAPIKeyService) {
+				m.On("Revoke", mock.Anything, testAPIKeyID).Return(nil, repository.ErrNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "internal error",
+			id:   testAPIKeyID,
+			setupMock: func(m *MockAPIKeyService) {
+				m.On("Revoke", mock.Anything, testAPIKeyID).Return(nil, errors.New("db down"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAPIKeyService)
+			tt.setupMock(mockService)
+			r := setupAPIKeyRouter(NewAPIKeyHandler(mockService))
+
+			req := httptest.NewRequest("DELETE", "/api/admin/api-keys/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+)
+
+type AuditHandler struct {
+	service service.AuditServiceInterface
+}
+
+func NewAuditHandler(svc service.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{service: svc}
+}
+
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := model.AuditListParams{
+		PrincipalID: query.Get("principal"),
+		EntityType:  query.Get("entity_type"),
+		EntityID:    query.Get("entity_id"),
+		Action:      query.Get("action"),
+	}
+
+	for name, target := range map[string]**time.Time{"since": &params.Since, "until": &params.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondBadRequest(w, "Invalid "+name+". Must be an RFC 3339 timestamp")
+			return
+		}
+		*target = &t
+	}
+
+	params.Page, params.Limit = parsePagination(r)
+
+	result, err := h.service.List(r.Context(), params)
+	if err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			respondValidationError(w, validationErr.Error())
+			return
+		}
+		slog.Error("Failed to list audit events", "error", err)
+		respondInternalError(w)
+		return
+	}
+
+	respondSuccess(w, result)
+}
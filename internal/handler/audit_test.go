@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupAuditRouter(handler *AuditHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/audit", handler.List)
+	return r
+}
+
+func TestAuditHandler_List_PassesFilters(t *testing.T) {
+	mockService := new(MockAuditService)
+	r := setupAuditRouter(NewAuditHandler(mockService))
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockService.On("List", mock.Anything, mock.MatchedBy(func(p model.AuditListParams) bool {
+		return p.PrincipalID == "key-1" && p.EntityType == "indicator" && p.Action == "export" &&
+			p.Since != nil && p.Since.Equal(since) && p.Until == nil && p.Page == 2
+	})).Return(&model.AuditListResult{
+		Data:  []model.AuditEvent{{ID: 7, PrincipalID: "key-1", Action: "export", EntityType: "indicator"}},
+		Total: 1, Page: 2, Limit: 20, TotalPages: 1,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/audit?principal=key-1&entity_type=indicator&action=export&since=2026-01-01T00:00:00Z&page=2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data model.AuditListResult `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data.Data, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuditHandler_List_InvalidTimestamp(t *testing.T) {
+	mockService := new(MockAuditService)
+	r := setupAuditRouter(NewAuditHandler(mockService))
+
+	req := httptest.NewRequest("GET", "/api/audit?until=yesterday", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestAuditHandler_List_ValidationError(t *testing.T) {
+	mockService := new(MockAuditService)
+	r := setupAuditRouter(NewAuditHandler(mockService))
+
+	mockService.On("List", mock.Anything, mock.Anything).Return(nil, &service.ValidationError{Field: "action", Message: "unknown audit action"})
+
+	req := httptest.NewRequest("GET", "/api/audit?action=explode", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/blocklist"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+const defaultBlocklistMinConfidence = 70
+
+func (h *IndicatorHandler) Blocklist(w http.ResponseWriter, r *http.Request) {
+	format, ok := blocklist.Formats[r.URL.Query().Get("format")]
+	if !ok {
+		respondBadRequest(w, "Invalid blocklist format. Must be one of: edl, nftables, rpz")
+		return
+	}
+
+	params, ok := parseSearchFilters(w, r)
+	if !ok {
+		return
+	}
+
+	if params.Type != "" {
+		if !format.Supports(model.IndicatorType(params.Type)) {
+			respondBadRequest(w, "Indicator type "+params.Type+" is not supported by the "+format.Name+" format")
+			return
+		}
+	} else {
+		params.Types = format.Types
+	}
+	if r.URL.Query().Get("min_confidence") == "" {
+		params.MinConfidence = defaultBlocklistMinConfidence
+	}
+	params.ActiveOnly = true
+	params.IncludeRevoked = false
+
+	h.streamIndicators(w, r, params, format.ContentType, format.Filename, func(out io.Writer) indicatorEncoder {
+		return blocklist.NewWriter(format, out, time.Now())
+	})
+}
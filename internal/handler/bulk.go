@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+const (
+	maxBulkIndicators   = 100000
+	maxBulkBodyBytes    = 256 << 20
+	bulkRequestDeadline = 5 * time.Minute
+)
+
+var errBulkTooLarge = fmt.Errorf("bulk requests are limited to %d indicators", maxBulkIndicators)
+
+func (h *IndicatorHandler) Bulk(w http.ResponseWriter, r *http.Request) {
+	force, ok := parseBoolQuery(w, r, "force")
+	if !ok {
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Now().Add(bulkRequestDeadline))
+	rc.SetWriteDeadline(time.Now().Add(bulkRequestDeadline))
+
+	body := bufio.NewReader(http.MaxBytesReader(w, r.Body, maxBulkBodyBytes))
+
+	items, err := parseBulkIndicators(body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(w, http.StatusRequestEntityTooLarge, ErrCodeBadRequest, "Request body too large")
+			return
+		}
+		respondBadRequest(w, "Invalid bulk payload: "+err.Error())
+		return
+	}
+	if len(items) == 0 {
+		respondBadRequest(w, "Request body contains no indicators")
+		return
+	}
+
+	result, err := h.service.BulkIngest(r.Context(), items, force)
+	if err != nil {
+		slog.Error("Failed to ingest indicators", "error", err, "count", len(items))
+		respondInternalError(w)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+func parseBulkIndicators(body *bufio.Reader) ([]model.BulkIndicatorItem, error) {
+	first, err := peekFirstNonSpace(body)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if first == '[' {
+		return parseJSONArray(body)
+	}
+	return parseNDJSON(body)
+}
+
+func parseJSONArray(body io.Reader) ([]model.BulkIndicatorItem, error) {
+	decoder := json.NewDecoder(body)
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON array: %w", err)
+	}
+
+	var items []model.BulkIndicatorItem
+	for line := 1; decoder.More(); line++ {
+		if line > maxBulkIndicators {
+			return nil, errBulkTooLarge
+		}
+
+		item := model.BulkIndicatorItem{Line: line}
+		if err := decoder.Decode(&item.Input); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return nil, fmt.Errorf("invalid JSON array at element %d: %w", line, err)
+			}
+			item.ParseError = err.Error()
+		}
+		items = append(items, item)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON array: %w", err)
+	}
+
+	return items, nil
+}
+
+func parseNDJSON(body *bufio.Reader) ([]model.BulkIndicatorItem, error) {
+	var items []model.BulkIndicatorItem
+
+	for line := 1; ; line++ {
+		raw, err := body.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 {
+			if len(items) >= maxBulkIndicators {
+				return nil, errBulkTooLarge
+			}
+
+			item := model.BulkIndicatorItem{Line: line}
+			if decodeErr := json.Unmarshal(raw, &item.Input); decodeErr != nil {
+				item.ParseError = "invalid JSON: " + decodeErr.Error()
+			}
+			items = append(items, item)
+		}
+
+		if err == io.EOF {
+			return items, nil
+		}
+	}
+}
+
+func peekFirstNonSpace(body *bufio.Reader) (byte, error) {
+	for {
+		b, err := body.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, body.UnreadByte()
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBulkIndicators_NDJSON(t *testing.T) {
+	body := `{"type":"ip","value":"10.0.0.1","campaign_ids":["550e8400-e29b-41d4-a716-446655440000"]}
+
+{"type":"domain","value":
+{"type":"hash","value":"d41d8cd98f00b204e9800998ecf8427e"}
+`
+	items, err := parseBulkIndicators(bufio.NewReader(strings.NewReader(body)))
+
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, 1, items[0].Line)
+	assert.Equal(t, "10.0.0.1", *items[0].Input.Value)
+	assert.Equal(t, []string{"550e8400-e29b-41d4-a716-446655440000"}, items[0].Input.CampaignIDs)
+	assert.Equal(t, 3, items[1].Line)
+	assert.NotEmpty(t, items[1].ParseError)
+	assert.Equal(t, 4, items[2].Line)
+	assert.Empty(t, items[2].ParseError)
+}
+
+func TestParseBulkIndicators_JSONArray(t *testing.T) {
+	body := ` [{"type":"ip","value":"10.0.0.1"}, {"type":"ip","value":"10.0.0.2","confidence":"high"}]`
+
+	items, err := parseBulkIndicators(bufio.NewReader(strings.NewReader(body)))
+
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Empty(t, items[0].ParseError)
+	assert.Equal(t, 2, items[1].Line)
+	assert.Contains(t, items[1].ParseError, "confidence")
+}
+
+func TestParseBulkIndicators_MalformedArray(t *testing.T) {
+	_, err := parseBulkIndicators(bufio.NewReader(strings.NewReader(`[{"type":"ip"`)))
+
+	assert.Error(t, err)
+}
+
+func TestIndicatorHandler_Bulk_Success(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := chi.NewRouter()
+	r.Post("/api/indicators/bulk", handler.Bulk)
+
+	mockService.On("BulkIngest", mock.Anything, mock.MatchedBy(func(items []model.BulkIndicatorItem) bool {
+		return len(items) == 2
+	}), false).Return(&model.BulkResult{Received: 2, Inserted: 2, Errors: []model.BulkError{}}, nil)
+
+	body := "{\"type\":\"ip\",\"value\":\"10.0.0.1\"}\n{\"type\":\"ip\",\"value\":\"10.0.0.2\"}\n"
+	req := httptest.NewRequest("POST", "/api/indicators/bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"inserted":2`)
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Bulk_EmptyBody(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := chi.NewRouter()
+	r.Post("/api/indicators/bulk", handler.Bulk)
+
+	req := httptest.NewRequest("POST", "/api/indicators/bulk", strings.NewReader("  \n"))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "BulkIngest", mock.Anything, mock.Anything, mock.Anything)
+}
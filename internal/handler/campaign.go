@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
@@ -19,15 +20,175 @@ func NewCampaignHandler(svc service.CampaignServiceInterface) *CampaignHandler {
 	return &CampaignHandler{service: svc}
 }
 
-func (h *CampaignHandler) GetIndicators(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		respondBadRequest(w, "Campaign ID is required")
+func (h *CampaignHandler) List(w http.ResponseWriter, r *http.Request) {
+	params := model.CampaignListParams{
+		Status:        r.URL.Query().Get("status"),
+		Severity:      r.URL.Query().Get("severity"),
+		ThreatActorID: r.URL.Query().Get("threat_actor_id"),
+		Sector:        r.URL.Query().Get("sector"),
+		Region:        r.URL.Query().Get("region"),
+	}
+
+	if params.Status != "" && !model.IsValidCampaignStatus(params.Status) {
+		respondBadRequest(w, "Invalid status. Must be one of: active, inactive, historical")
+		return
+	}
+	if params.Severity != "" && !model.IsValidSeverity(params.Severity) {
+		respondBadRequest(w, "Invalid severity. Must be one of: low, medium, high, critical")
 		return
 	}
+	if params.ThreatActorID != "" {
+		if _, err := uuid.Parse(params.ThreatActorID); err != nil {
+			respondBadRequest(w, "Invalid threat_actor_id format")
+			return
+		}
+	}
 
-	if _, err := uuid.Parse(id); err != nil {
-		respondBadRequest(w, "Invalid campaign ID format")
+	params.Page, params.Limit = parsePagination(r)
+
+	result, err := h.service.List(r.Context(), params)
+	if err != nil {
+		slog.Error("Failed to list campaigns", "error", err)
+		respondInternalError(w)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+func (h *CampaignHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	campaign, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, campaign)
+}
+
+func (h *CampaignHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input model.CampaignInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	campaign, err := h.service.Create(r.Context(), input)
+	if err != nil {
+		h.handleError(w, err, "")
+		return
+	}
+
+	respondCreated(w, campaign)
+}
+
+func (h *CampaignHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.CampaignInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	campaign, err := h.service.Update(r.Context(), id, input)
+	if err != nil {
+		h.handleError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, campaign)
+}
+
+func (h *CampaignHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.CampaignInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	campaign, err := h.service.Patch(r.Context(), id, input)
+	if err != nil {
+		h.handleError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, campaign)
+}
+
+func (h *CampaignHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.handleError(w, err, id)
+		return
+	}
+
+	respondNoContent(w)
+}
+
+func (h *CampaignHandler) LinkIndicator(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
+		return
+	}
+	indicatorID, ok := pathUUIDParam(w, r, "indicatorId", "Invalid indicator ID format")
+	if !ok {
+		return
+	}
+
+	var input model.CampaignIndicatorInput
+	if r.ContentLength != 0 && !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	link, created, err := h.service.LinkIndicator(r.Context(), id, indicatorID, input)
+	if err != nil {
+		h.handleLinkError(w, err, id, indicatorID)
+		return
+	}
+
+	if created {
+		respondCreated(w, link)
+		return
+	}
+	respondSuccess(w, link)
+}
+
+func (h *CampaignHandler) UnlinkIndicator(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
+		return
+	}
+	indicatorID, ok := pathUUIDParam(w, r, "indicatorId", "Invalid indicator ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnlinkIndicator(r.Context(), id, indicatorID); err != nil {
+		h.handleLinkError(w, err, id, indicatorID)
+		return
+	}
+
+	respondNoContent(w)
+}
+
+func (h *CampaignHandler) GetIndicators(w http.ResponseWriter, r *http.Request) {
+	id, ok := campaignIDParam(w, r)
+	if !ok {
 		return
 	}
 
@@ -54,3 +215,60 @@ func (h *CampaignHandler) GetIndicators(w http.ResponseWriter, r *http.Request)
 
 	respondSuccess(w, timeline)
 }
+
+func (h *CampaignHandler) handleError(w http.ResponseWriter, err error, id string) {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		respondValidationError(w, validationErr.Error())
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		respondNotFound(w, "Campaign not found")
+		return
+	}
+	if errors.Is(err, repository.ErrInvalidReference) {
+		respondValidationError(w, "threat_actor_id: threat actor not found")
+		return
+	}
+	slog.Error("Failed to handle campaign request", "error", err, "id", id)
+	respondInternalError(w)
+}
+
+func (h *CampaignHandler) handleLinkError(w http.ResponseWriter, err error, id, indicatorID string) {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		respondValidationError(w, validationErr.Error())
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		respondNotFound(w, "Campaign, indicator or link not found")
+		return
+	}
+	slog.Error("Failed to update campaign indicator link", "error", err, "id", id, "indicator_id", indicatorID)
+	respondInternalError(w)
+}
+
+func campaignIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondBadRequest(w, "Campaign ID is required")
+		return "", false
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		respondBadRequest(w, "Invalid campaign ID format")
+		return "", false
+	}
+
+	return id, true
+}
+
+func pathUUIDParam(w http.ResponseWriter, r *http.Request, name, message string) (string, bool) {
+	id := chi.URLParam(r, name)
+	if _, err := uuid.Parse(id); err != nil {
+		respondBadRequest(w, message)
+		return "", false
+	}
+
+	return id, true
+}
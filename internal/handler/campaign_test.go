@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
@@ -15,7 +16,14 @@ import (
 
 func setupCampaignRouter(handler *CampaignHandler) *chi.Mux {
 	r := chi.NewRouter()
+	r.Get("/api/campaigns", handler.List)
+	r.Post("/api/campaigns", handler.Create)
+	r.Get("/api/campaigns/{id}", handler.GetByID)
+	r.Patch("/api/campaigns/{id}", handler.Patch)
+	r.Delete("/api/campaigns/{id}", handler.Delete)
 	r.Get("/api/campaigns/{id}/indicators", handler.GetIndicators)
+	r.Put("/api/campaigns/{id}/indicators/{indicatorId}", handler.LinkIndicator)
+	r.Delete("/api/campaigns/{id}/indicators/{indicatorId}", handler.UnlinkIndicator)
 	return r
 }
 
@@ -163,3 +171,146 @@ func TestCampaignHandler_GetIndicators_WithDateFilters(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockService.AssertExpectations(t)
 }
+
+const (
+	testCampaignID  = "550e8400-e29b-41d4-a716-446655440000"
+	testIndicatorID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+)
+
+func TestCampaignHandler_List(t *testing.T) {
+	mockService := new(MockCampaignService)
+	r := setupCampaignRouter(NewCampaignHandler(mockService))
+
+	params := model.CampaignListParams{Status: "active", Sector: "finance", Page: 1, Limit: 20}
+	mockService.On("List", mock.Anything, params).Return(&model.CampaignListResult{
+		Data: []model.CampaignWithCount{
+			{Campaign: model.Campaign{ID: testCampaignID, Name: "Operation Test", TargetSectors: []string{"finance"}}, IndicatorCount: 3},
+		},
+		Total: 1, Page: 1, Limit: 20, TotalPages: 1,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/campaigns?status=active&sector=finance", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"indicator_count":3`)
+	mockService.AssertExpectations(t)
+}
+
+func TestCampaignHandler_List_InvalidFilters(t *testing.T) {
+	r := setupCampaignRouter(NewCampaignHandler(new(MockCampaignService)))
+
+	for _, query := range []string{"status=paused", "severity=extreme", "threat_actor_id=abc"} {
+		t.Run(query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/campaigns?"+query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestCampaignHandler_GetByID_NotFound(t *testing.T) {
+	mockService := new(MockCampaignService)
+	r := setupCampaignRouter(NewCampaignHandler(mockService))
+
+	mockService.On("GetByID", mock.Anything, testCampaignID).Return(nil, repository.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/api/campaigns/"+testCampaignID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCampaignHandler_Create(t *testing.T) {
+	mockService := new(MockCampaignService)
+	r := setupCampaignRouter(NewCampaignHandler(mockService))
+
+	name := "Operation Test"
+	input := model.CampaignInput{Name: &name, TargetRegions: []string{"EU"}}
+	mockService.On("Create", mock.Anything, input).Return(&model.Campaign{
+		ID: testCampaignID, Name: name, Status: "active", TargetRegions: []string{"EU"},
+	}, nil)
+
+	req := httptest.NewRequest("POST", "/api/campaigns", strings.NewReader(`{"name":"Operation Test","target_regions":["EU"]}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCampaignHandler_Patch_UnknownThreatActor(t *testing.T) {
+	mockService := new(MockCampaignService)
+	r := setupCampaignRouter(NewCampaignHandler(mockService))
+
+	actorID := testIndicatorID
+	input := model.CampaignInput{ThreatActorID: &actorID}
+	mockService.On("Patch", mock.Anything, testCampaignID, input).Return(nil, repository.ErrInvalidReference)
+
+	req := httptest.NewRequest("PATCH", "/api/campaigns/"+testCampaignID, strings.NewReader(`{"threat_actor_id":"`+actorID+`"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCampaignHandler_Delete(t *testing.T) {
+	mockService := new(MockCampaignService)
+	r := setupCampaignRouter(NewCampaignHandler(mockService))
+
+	mockService.On("Delete", mock.Anything, testCampaignID).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/api/campaigns/"+testCampaignID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCampaignHandler_LinkIndicator(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		input   model.CampaignIndicatorInput
+		created bool
+		status  int
+	}{
+		{"new link with notes", `{"notes":"dropper C2"}`, model.CampaignIndicatorInput{Notes: "dropper C2"}, true, http.StatusCreated},
+		{"existing link without body", "", model.CampaignIndicatorInput{}, false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockCampaignService)
+			r := setupCampaignRouter(NewCampaignHandler(mockService))
+
+			mockService.On("LinkIndicator", mock.Anything, testCampaignID, testIndicatorID, tt.input).Return(&model.IndicatorCampaignLink{
+				IndicatorID: testIndicatorID, CampaignID: testCampaignID, Notes: tt.input.Notes,
+			}, tt.created, nil)
+
+			req := httptest.NewRequest("PUT", "/api/campaigns/"+testCampaignID+"/indicators/"+testIndicatorID, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCampaignHandler_UnlinkIndicator_InvalidIndicatorID(t *testing.T) {
+	r := setupCampaignRouter(NewCampaignHandler(new(MockCampaignService)))
+
+	req := httptest.NewRequest("DELETE", "/api/campaigns/"+testCampaignID+"/indicators/not-a-uuid", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+const (
+	exportFlushEvery  = 1000
+	exportWriteWindow = 10 * time.Minute
+)
+
+var csvExportHeader = []string{
+	"id", "type", "value", "description", "severity", "confidence",
+	"first_seen", "last_seen", "is_active", "tags", "source", "created_at", "updated_at",
+	"tlp", "pap",
+}
+
+type streamEncoder interface {
+	Header() error
+	Flush() error
+}
+
+type indicatorEncoder interface {
+	streamEncoder
+	Encode(ind *model.Indicator) error
+}
+
+func (h *IndicatorHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	var contentType string
+	switch format {
+	case "csv":
+		contentType = "text/csv; charset=utf-8"
+	case "ndjson":
+		contentType = "application/x-ndjson"
+	default:
+		respondBadRequest(w, "Invalid export format. Must be one of: csv, ndjson")
+		return
+	}
+
+	params, ok := parseSearchFilters(w, r)
+	if !ok {
+		return
+	}
+
+	h.streamIndicators(w, r, params, contentType, "indicators."+format, func(out io.Writer) indicatorEncoder {
+		return newIndicatorEncoder(format, out)
+	})
+}
+
+func (h *IndicatorHandler) streamIndicators(w http.ResponseWriter, r *http.Request, params model.SearchParams,
+	contentType, filename string, newEncoder func(io.Writer) indicatorEncoder) {
+	enc := newEncoder(w)
+	stream := newExportStream(w, contentType, filename, enc)
+
+	err := h.service.Export(r.Context(), params, func(ind *model.Indicator) error {
+		if err := stream.begin(); err != nil {
+			return err
+		}
+		if err := enc.Encode(ind); err != nil {
+			return err
+		}
+		return stream.rowWritten()
+	})
+
+	stream.finish(r, err)
+}
+
+type exportStream struct {
+	w           http.ResponseWriter
+	rc          *http.ResponseController
+	enc         streamEncoder
+	contentType string
+	filename    string
+	started     bool
+	rows        int
+}
+
+func newExportStream(w http.ResponseWriter, contentType, filename string, enc streamEncoder) *exportStream {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(exportWriteWindow)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.Warn("Failed to extend export write deadline", "error", err)
+	}
+
+	return &exportStream{w: w, rc: rc, enc: enc, contentType: contentType, filename: filename}
+}
+
+func (s *exportStream) begin() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	s.w.Header().Set("Content-Type", s.contentType)
+	s.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, s.filename))
+	s.w.WriteHeader(http.StatusOK)
+	return s.enc.Header()
+}
+
+func (s *exportStream) rowWritten() error {
+	s.rows++
+	if s.rows%exportFlushEvery == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+func (s *exportStream) flush() error {
+	if err := s.enc.Flush(); err != nil {
+		return err
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+func (s *exportStream) finish(r *http.Request, err error) {
+	if err != nil {
+		if r.Context().Err() != nil {
+			return
+		}
+		if !s.started {
+			slog.Error("Failed to export indicators", "error", err)
+			respondInternalError(s.w)
+			return
+		}
+		slog.Error("Indicator export aborted", "error", err, "rows", s.rows)
+		return
+	}
+
+	if err := s.begin(); err != nil {
+		return
+	}
+	if err := s.flush(); err != nil {
+		slog.Error("Failed to flush indicator export", "error", err)
+	}
+}
+
+func newIndicatorEncoder(format string, w io.Writer) indicatorEncoder {
+	if format == "ndjson" {
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}
+	}
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) Header() error {
+	return e.w.Write(csvExportHeader)
+}
+
+func (e *csvEncoder) Encode(ind *model.Indicator) error {
+	return e.w.Write([]string{
+		ind.ID,
+		string(ind.Type),
+		csvCell(ind.Value),
+		csvCell(ind.Description),
+		ind.Severity,
+		strconv.Itoa(ind.Confidence),
+		formatExportTime(ind.FirstSeen),
+		formatExportTime(ind.LastSeen),
+		strconv.FormatBool(ind.IsActive),
+		csvCell(strings.Join(ind.Tags, ";")),
+		csvCell(ind.Source),
+		ind.CreatedAt.UTC().Format(time.RFC3339),
+		ind.UpdatedAt.UTC().Format(time.RFC3339),
+		ind.TLP,
+		ind.PAP,
+	})
+}
+
+// csvCell keeps feed-supplied text from being evaluated as a formula when the
+// export is opened in a spreadsheet, by quoting cells that would start one.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Header() error {
+	return nil
+}
+
+func (e *ndjsonEncoder) Encode(ind *model.Indicator) error {
+	return e.enc.Encode(ind)
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return nil
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
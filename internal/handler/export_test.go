@@ -0,0 +1,275 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupExportRouter(handler *IndicatorHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/indicators/export", handler.Export)
+	return r
+}
+
+func exportFixtures() []model.Indicator {
+	seen := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []model.Indicator{
+		{
+			ID: "550e8400-e29b-41d4-a716-446655440000", Type: model.IndicatorTypeIP, Value: "10.0.0.1",
+			Severity: "high", Confidence: 80, FirstSeen: &seen, IsActive: true,
+			Tags: []string{"c2", "botnet"}, TLP: "amber", PAP: "green", CreatedAt: seen, UpdatedAt: seen,
+		},
+		{
+			ID: "550e8400-e29b-41d4-a716-446655440001", Type: model.IndicatorTypeDomain, Value: "evil, inc.example",
+			Severity: "low", Confidence: 20, CreatedAt: seen, UpdatedAt: seen,
+		},
+	}
+}
+
+func TestIndicatorHandler_Export_CSV(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupExportRouter(NewIndicatorHandler(mockService))
+
+	params := model.SearchParams{Type: "ip", CampaignID: "c1"}
+	mockService.On("Export", mock.Anything, params).Return(exportFixtures(), nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/export?format=csv&type=ip&campaign=c1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "indicators.csv")
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, csvExportHeader, records[0])
+	assert.Equal(t, "10.0.0.1", records[1][2])
+	assert.Equal(t, "2024-03-01T12:00:00Z", records[1][6])
+	assert.Equal(t, "c2;botnet", records[1][9])
+	assert.Equal(t, []string{"amber", "green"}, records[1][13:])
+	assert.Equal(t, "evil, inc.example", records[2][2])
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Export_CSVNeutralizesFormulas(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupExportRouter(NewIndicatorHandler(mockService))
+
+	seen := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("Export", mock.Anything, model.SearchParams{}).Return([]model.Indicator{{
+		ID: "550e8400-e29b-41d4-a716-446655440000", Type: model.IndicatorTypeFilePath,
+		Value: `=HYPERLINK("http://evil.example","x")`, Description: "+1 from partner", Source: "@feed",
+		Severity: "high", Tags: []string{"-cmd", "c2"}, CreatedAt: seen, UpdatedAt: seen,
+	}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/export?format=csv", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, `'=HYPERLINK("http://evil.example","x")`, records[1][2])
+	assert.Equal(t, "'+1 from partner", records[1][3])
+	assert.Equal(t, "'-cmd;c2", records[1][9])
+	assert.Equal(t, "'@feed", records[1][10])
+	assert.Equal(t, "high", records[1][4], "fields the API controls are left alone")
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Export_NDJSON(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupExportRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("Export", mock.Anything, model.SearchParams{}).Return(exportFixtures(), nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/export?format=ndjson", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	var lines []model.Indicator
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var ind model.Indicator
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ind))
+		lines = append(lines, ind)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440001", lines[1].ID)
+}
+
+func TestIndicatorHandler_Export_EmptyResultWritesHeader(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupExportRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("Export", mock.Anything, model.SearchParams{}).Return([]model.Indicator{}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/export", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, strings.Join(csvExportHeader, ",")+"\n", w.Body.String())
+}
+
+func TestIndicatorHandler_Export_InvalidParams(t *testing.T) {
+	tests := []string{
+		"/api/indicators/export?format=xml",
+		"/api/indicators/export?format=csv&type=invalid",
+	}
+
+	for _, url := range tests {
+		t.Run(url, func(t *testing.T) {
+			r := setupExportRouter(NewIndicatorHandler(nil))
+
+			req := httptest.NewRequest("GET", url, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestIndicatorHandler_Export_ErrorBeforeFirstRow(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupExportRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("Export", mock.Anything, model.SearchParams{}).Return(nil, errors.New("database error"))
+
+	req := httptest.NewRequest("GET", "/api/indicators/export", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestIndicatorHandler_Blocklist_AppliesDefaults(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := chi.NewRouter()
+	r.Get("/api/indicators/blocklist", NewIndicatorHandler(mockService).Blocklist)
+
+	params := model.SearchParams{
+		Types:         []model.IndicatorType{model.IndicatorTypeDomain},
+		MinConfidence: 70,
+		MinSeverity:   "high",
+		ActiveOnly:    true,
+	}
+	mockService.On("Export", mock.Anything, params).Return([]model.Indicator{
+		{Type: model.IndicatorTypeDomain, Value: "evil.example"},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/blocklist?format=rpz&min_severity=high", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "blocklist.rpz")
+	assert.Contains(t, w.Body.String(), "evil.example CNAME .\n")
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Blocklist_InvalidParams(t *testing.T) {
+	tests := []string{
+		"/api/indicators/blocklist",
+		"/api/indicators/blocklist?format=pf",
+		"/api/indicators/blocklist?format=nftables&type=domain",
+		"/api/indicators/blocklist?format=edl&min_confidence=101",
+		"/api/indicators/blocklist?format=edl&min_severity=urgent",
+	}
+
+	for _, url := range tests {
+		t.Run(url, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Get("/api/indicators/blocklist", NewIndicatorHandler(nil).Blocklist)
+
+			req := httptest.NewRequest("GET", url, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestIndicatorHandler_Rules_Suricata(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := chi.NewRouter()
+	r.Get("/api/indicators/rules", NewIndicatorHandler(mockService).Rules)
+
+	mockService.On("ExportAttributed", mock.Anything, model.SearchParams{ActiveOnly: true}).Return([]model.AttributedIndicator{
+		{
+			Indicator: model.Indicator{ID: "550e8400-e29b-41d4-a716-446655440000", Type: model.IndicatorTypeDomain, Value: "evil.example"},
+			Campaigns: []string{"Operation Dark Night"},
+		},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/rules?engine=suricata", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "threat-intel.rules")
+	assert.Contains(t, w.Body.String(), "dns.query;")
+	assert.Contains(t, w.Body.String(), "campaigns: Operation Dark Night")
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Rules_HashList(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := chi.NewRouter()
+	r.Get("/api/indicators/rules", NewIndicatorHandler(mockService).Rules)
+
+	params := model.SearchParams{Type: "hash", ActiveOnly: true}
+	mockService.On("ExportAttributed", mock.Anything, params).Return([]model.AttributedIndicator{
+		{Indicator: model.Indicator{Type: model.IndicatorTypeHash, Value: "d41d8cd98f00b204e9800998ecf8427e"}},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/rules?engine=suricata&list=md5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "threat-intel-md5.list")
+	assert.Equal(t, "d41d8cd98f00b204e9800998ecf8427e\n", w.Body.String())
+}
+
+func TestIndicatorHandler_Rules_InvalidParams(t *testing.T) {
+	tests := []string{
+		"/api/indicators/rules",
+		"/api/indicators/rules?engine=snort",
+		"/api/indicators/rules?engine=suricata&list=sha512",
+		"/api/indicators/rules?engine=suricata&list=md5&type=ip",
+	}
+
+	for _, url := range tests {
+		t.Run(url, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Get("/api/indicators/rules", NewIndicatorHandler(nil).Rules)
+
+			req := httptest.NewRequest("GET", url, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
+	"net/netip"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/hostname"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
@@ -13,6 +18,11 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	maxJSONBodyBytes     = 1 << 20
+	maxSearchQueryLength = 256
+)
+
 type IndicatorHandler struct {
 	service service.IndicatorServiceInterface
 }
@@ -33,7 +43,18 @@ func (h *IndicatorHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	indicator, err := h.service.GetByID(r.Context(), id)
+	var indicator *model.IndicatorWithRelations
+	var err error
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		t, parseErr := time.Parse(time.RFC3339, asOf)
+		if parseErr != nil {
+			respondBadRequest(w, "Invalid as_of. Must be an RFC 3339 timestamp")
+			return
+		}
+		indicator, err = h.service.GetAsOf(r.Context(), id, t)
+	} else {
+		indicator, err = h.service.GetByID(r.Context(), id)
+	}
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			respondNotFound(w, "Indicator not found")
@@ -47,8 +68,32 @@ func (h *IndicatorHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, indicator)
 }
 
-func (h *IndicatorHandler) Search(w http.ResponseWriter, r *http.Request) {
+func (h *IndicatorHandler) History(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var params model.IndicatorHistoryParams
+	params.Page, params.Limit = parsePagination(r)
+
+	result, err := h.service.History(r.Context(), id, params)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondNotFound(w, "Indicator not found")
+			return
+		}
+		slog.Error("Failed to get indicator history", "error", err, "id", id)
+		respondInternalError(w)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+func parseSearchFilters(w http.ResponseWriter, r *http.Request) (model.SearchParams, bool) {
 	params := model.SearchParams{
+		Query:          strings.TrimSpace(r.URL.Query().Get("q")),
 		Type:           r.URL.Query().Get("type"),
 		Value:          r.URL.Query().Get("value"),
 		ThreatActorID:  r.URL.Query().Get("threat_actor"),
@@ -57,21 +102,285 @@ func (h *IndicatorHandler) Search(w http.ResponseWriter, r *http.Request) {
 		LastSeenBefore: r.URL.Query().Get("last_seen_before"),
 	}
 
-	if params.Type != "" {
-		validTypes := map[string]bool{"ip": true, "domain": true, "url": true, "hash": true}
-		if !validTypes[params.Type] {
-			respondBadRequest(w, "Invalid indicator type. Must be one of: ip, domain, url, hash")
-			return
+	if len(params.Query) > maxSearchQueryLength {
+		respondBadRequest(w, "Invalid q. Must be at most 256 characters")
+		return params, false
+	}
+
+	if params.Type != "" && !model.IndicatorType(params.Type).IsValid() {
+		respondBadRequest(w, "Invalid indicator type. Must be one of: "+model.IndicatorTypeNames())
+		return params, false
+	}
+
+	if c := r.URL.Query().Get("min_confidence"); c != "" {
+		parsed, err := strconv.Atoi(c)
+		if err != nil || parsed < 0 || parsed > 100 {
+			respondBadRequest(w, "Invalid min_confidence. Must be an integer between 0 and 100")
+			return params, false
+		}
+		params.MinConfidence = parsed
+	}
+
+	if s := r.URL.Query().Get("min_severity"); s != "" {
+		if !model.IsValidSeverity(s) {
+			respondBadRequest(w, "Invalid min_severity. Must be one of: low, medium, high, critical")
+			return params, false
+		}
+		params.MinSeverity = s
+	}
+
+	includeRevoked, ok := parseBoolQuery(w, r, "include_revoked")
+	if !ok {
+		return params, false
+	}
+	params.IncludeRevoked = includeRevoked
+
+	if c := r.URL.Query().Get("cidr"); c != "" {
+		prefix, err := netip.ParsePrefix(c)
+		if err != nil || prefix.Addr().Zone() != "" {
+			respondBadRequest(w, "Invalid cidr. Must be an IPv4 or IPv6 network such as 10.0.0.0/8")
+			return params, false
 		}
+		params.CIDR = prefix.Masked().String()
+	}
+
+	if ip := r.URL.Query().Get("ip_in_range"); ip != "" {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil || addr.Zone() != "" {
+			respondBadRequest(w, "Invalid ip_in_range. Must be an IPv4 or IPv6 address")
+			return params, false
+		}
+		params.IPInRange = addr.Unmap().String()
+	}
+
+	if algo := strings.ToLower(r.URL.Query().Get("hash_algo")); algo != "" {
+		if !model.IsValidHashAlgo(algo) {
+			respondBadRequest(w, "Invalid hash_algo. Must be one of: md5, sha1, sha256, sha512, ssdeep, tlsh, imphash")
+			return params, false
+		}
+		params.HashAlgo = algo
+	}
+
+	if d := r.URL.Query().Get("domain_suffix"); d != "" {
+		params.DomainSuffix = hostname.Normalize(d)
+		if params.DomainSuffix == "" {
+			respondBadRequest(w, "Invalid domain_suffix. Must be a domain name such as example.com")
+			return params, false
+		}
+	}
+
+	if d := r.URL.Query().Get("registered_domain"); d != "" {
+		params.RegisteredDomain = hostname.Normalize(d)
+		if params.RegisteredDomain == "" || hostname.FromHost(params.RegisteredDomain).RegisteredDomain != params.RegisteredDomain {
+			respondBadRequest(w, "Invalid registered_domain. Must be a registrable domain such as example.co.uk")
+			return params, false
+		}
+	}
+
+	if tld := r.URL.Query().Get("tld"); tld != "" {
+		params.TLD = hostname.Normalize(strings.TrimPrefix(tld, "."))
+		if params.TLD == "" || strings.Contains(params.TLD, ".") {
+			respondBadRequest(w, "Invalid tld. Must be a single label such as ru")
+			return params, false
+		}
+	}
+
+	return params, true
+}
+
+func (h *IndicatorHandler) Search(w http.ResponseWriter, r *http.Request) {
+	params, ok := parseSearchFilters(w, r)
+	if !ok {
+		return
+	}
+
+	params.Page, params.Limit = parsePagination(r)
+
+	result, err := h.service.Search(r.Context(), params)
+	if err != nil {
+		respondInternalError(w)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+func (h *IndicatorHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input model.IndicatorInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	indicator, err := h.service.Create(r.Context(), input)
+	if err != nil {
+		h.handleWriteError(w, err, "")
+		return
+	}
+
+	respondCreated(w, indicator)
+}
+
+func (h *IndicatorHandler) Upsert(w http.ResponseWriter, r *http.Request) {
+	force, ok := parseBoolQuery(w, r, "force")
+	if !ok {
+		return
+	}
+
+	var input model.IndicatorInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	indicator, created, err := h.service.Upsert(r.Context(), input, force)
+	if err != nil {
+		h.handleWriteError(w, err, "")
+		return
+	}
+
+	if created {
+		respondCreated(w, indicator)
+		return
+	}
+	respondSuccess(w, indicator)
+}
+
+func (h *IndicatorHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.IndicatorInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	indicator, err := h.service.Update(r.Context(), id, input)
+	if err != nil {
+		h.handleWriteError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, indicator)
+}
+
+func (h *IndicatorHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.IndicatorInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	indicator, err := h.service.Patch(r.Context(), id, input)
+	if err != nil {
+		h.handleWriteError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, indicator)
+}
+
+func (h *IndicatorHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.handleWriteError(w, err, id)
+		return
 	}
 
+	respondNoContent(w)
+}
+
+func (h *IndicatorHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.IndicatorRevokeInput
+	if !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	indicator, err := h.service.Revoke(r.Context(), id, input)
+	if err != nil {
+		h.handleWriteError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, indicator)
+}
+
+func (h *IndicatorHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	indicator, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		h.handleWriteError(w, err, id)
+		return
+	}
+
+	respondSuccess(w, indicator)
+}
+
+func (h *IndicatorHandler) handleWriteError(w http.ResponseWriter, err error, id string) {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		respondValidationError(w, validationErr.Error())
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		respondNotFound(w, "Indicator not found")
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		respondConflict(w, "An indicator with the same type and value already exists")
+		return
+	}
+	if errors.Is(err, repository.ErrFalsePositive) {
+		respondConflict(w, "An indicator with the same type and value was revoked as a false positive; retry with force=true to re-ingest it")
+		return
+	}
+	if errors.Is(err, repository.ErrNotWritable) {
+		respondValidationError(w, "The indicator cannot be written")
+		return
+	}
+	slog.Error("Failed to write indicator", "error", err, "id", id)
+	respondInternalError(w)
+}
+
+func indicatorIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondBadRequest(w, "Indicator ID is required")
+		return "", false
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		respondBadRequest(w, "Invalid indicator ID format")
+		return "", false
+	}
+
+	return id, true
+}
+
+func parsePagination(r *http.Request) (int, int) {
 	page := 1
 	if p := r.URL.Query().Get("page"); p != "" {
 		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
 			page = parsed
 		}
 	}
-	params.Page = page
 
 	limit := 20
 	if l := r.URL.Query().Get("limit"); l != "" {
@@ -82,13 +391,33 @@ func (h *IndicatorHandler) Search(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
-	params.Limit = limit
 
-	result, err := h.service.Search(r.Context(), params)
+	return page, limit
+}
+
+func parseBoolQuery(w http.ResponseWriter, r *http.Request, name string) (bool, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, true
+	}
+
+	parsed, err := strconv.ParseBool(value)
 	if err != nil {
-		respondInternalError(w)
-		return
+		respondBadRequest(w, "Invalid "+name+". Must be true or false")
+		return false, false
 	}
+	return parsed, true
+}
 
-	respondSuccess(w, result)
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxJSONBodyBytes)
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		respondBadRequest(w, "Invalid JSON body: "+err.Error())
+		return false
+	}
+
+	return true
 }
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testHistoryIndicatorID = "550e8400-e29b-41d4-a716-446655440000"
+
+func setupIndicatorHistoryRouter(handler *IndicatorHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/indicators/{id}", handler.GetByID)
+	r.Get("/api/indicators/{id}/history", handler.History)
+	return r
+}
+
+func TestIndicatorHandler_GetByID_AsOf(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorHistoryRouter(NewIndicatorHandler(mockService))
+
+	asOf := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("GetAsOf", mock.Anything, testHistoryIndicatorID, asOf).Return(&model.IndicatorWithRelations{
+		Indicator: model.Indicator{ID: testHistoryIndicatorID, Confidence: 90},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/"+testHistoryIndicatorID+"?as_of=2026-09-01T12:00:00Z", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestIndicatorHandler_GetByID_InvalidAsOf(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorHistoryRouter(NewIndicatorHandler(mockService))
+
+	req := httptest.NewRequest("GET", "/api/indicators/"+testHistoryIndicatorID+"?as_of=last-month", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GetAsOf", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIndicatorHandler_GetByID_AsOfNotFound(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorHistoryRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("GetAsOf", mock.Anything, testHistoryIndicatorID, mock.Anything).Return(nil, repository.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/api/indicators/"+testHistoryIndicatorID+"?as_of=2020-01-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestIndicatorHandler_History(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorHistoryRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("History", mock.Anything, testHistoryIndicatorID, model.IndicatorHistoryParams{Page: 1, Limit: 20}).Return(&model.IndicatorHistoryResult{
+		Data: []model.IndicatorVersion{
+			{Indicator: model.Indicator{ID: testHistoryIndicatorID, Confidence: 40}, Version: 2, Operation: model.HistoryUpdate},
+			{Indicator: model.Indicator{ID: testHistoryIndicatorID, Confidence: 90}, Version: 1, Operation: model.HistoryCreate},
+		},
+		Total: 2, Page: 1, Limit: 20, TotalPages: 1,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/"+testHistoryIndicatorID+"/history", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data model.IndicatorHistoryResult `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response.Data.Data, 2)
+	assert.Equal(t, 2, response.Data.Data[0].Version)
+	assert.Equal(t, 90, response.Data.Data[1].Confidence)
+}
+
+func TestIndicatorHandler_History_NotFound(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorHistoryRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("History", mock.Anything, testHistoryIndicatorID, mock.Anything).Return(nil, repository.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/api/indicators/"+testHistoryIndicatorID+"/history", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
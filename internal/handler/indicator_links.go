@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+)
+
+func (h *IndicatorHandler) LinkActor(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+	actorID, ok := pathUUIDParam(w, r, "actorId", "Invalid threat actor ID format")
+	if !ok {
+		return
+	}
+
+	var input model.IndicatorActorInput
+	if r.ContentLength != 0 && !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	link, created, err := h.service.LinkActor(r.Context(), id, actorID, input)
+	if err != nil {
+		h.handleLinkError(w, err, id, "Indicator, threat actor or link not found")
+		return
+	}
+
+	if created {
+		respondCreated(w, link)
+		return
+	}
+	respondSuccess(w, link)
+}
+
+func (h *IndicatorHandler) UnlinkActor(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+	actorID, ok := pathUUIDParam(w, r, "actorId", "Invalid threat actor ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnlinkActor(r.Context(), id, actorID); err != nil {
+		h.handleLinkError(w, err, id, "Indicator, threat actor or link not found")
+		return
+	}
+
+	respondNoContent(w)
+}
+
+func (h *IndicatorHandler) LinkCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+	campaignID, ok := pathUUIDParam(w, r, "campaignId", "Invalid campaign ID format")
+	if !ok {
+		return
+	}
+
+	var input model.CampaignIndicatorInput
+	if r.ContentLength != 0 && !decodeJSONBody(w, r, &input) {
+		return
+	}
+
+	link, created, err := h.service.LinkCampaign(r.Context(), id, campaignID, input)
+	if err != nil {
+		h.handleLinkError(w, err, id, "Indicator, campaign or link not found")
+		return
+	}
+
+	if created {
+		respondCreated(w, link)
+		return
+	}
+	respondSuccess(w, link)
+}
+
+func (h *IndicatorHandler) UnlinkCampaign(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+	campaignID, ok := pathUUIDParam(w, r, "campaignId", "Invalid campaign ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnlinkCampaign(r.Context(), id, campaignID); err != nil {
+		h.handleLinkError(w, err, id, "Indicator, campaign or link not found")
+		return
+	}
+
+	respondNoContent(w)
+}
+
+func (h *IndicatorHandler) LinkSample(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+	siblingID, ok := pathUUIDParam(w, r, "siblingId", "Invalid sibling indicator ID format")
+	if !ok {
+		return
+	}
+
+	sample, err := h.service.LinkSample(r.Context(), id, siblingID)
+	if err != nil {
+		h.handleLinkError(w, err, id, "Hash indicator or sibling hash not found")
+		return
+	}
+
+	respondSuccess(w, sample)
+}
+
+func (h *IndicatorHandler) UnlinkSample(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnlinkSample(r.Context(), id); err != nil {
+		h.handleLinkError(w, err, id, "Indicator not found or not part of a sample")
+		return
+	}
+
+	respondNoContent(w)
+}
+
+func (h *IndicatorHandler) handleLinkError(w http.ResponseWriter, err error, id, notFound string) {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		respondValidationError(w, validationErr.Error())
+		return
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		respondNotFound(w, notFound)
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		respondConflict(w, "The sample already has a hash with the same algorithm")
+		return
+	}
+	slog.Error("Failed to update indicator link", "error", err, "id", id)
+	respondInternalError(w)
+}
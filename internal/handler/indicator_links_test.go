@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupIndicatorLinkRouter(handler *IndicatorHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Put("/api/indicators/{id}/actors/{actorId}", handler.LinkActor)
+	r.Delete("/api/indicators/{id}/actors/{actorId}", handler.UnlinkActor)
+	r.Put("/api/indicators/{id}/campaigns/{campaignId}", handler.LinkCampaign)
+	r.Delete("/api/indicators/{id}/campaigns/{campaignId}", handler.UnlinkCampaign)
+	return r
+}
+
+func TestIndicatorHandler_LinkActor(t *testing.T) {
+	confidence := 85
+	tests := []struct {
+		name    string
+		body    string
+		input   model.IndicatorActorInput
+		created bool
+		status  int
+	}{
+		{"new link", `{"confidence":85}`, model.IndicatorActorInput{Confidence: &confidence}, true, http.StatusCreated},
+		{"re-score existing link", `{"confidence":85}`, model.IndicatorActorInput{Confidence: &confidence}, false, http.StatusOK},
+		{"no body", "", model.IndicatorActorInput{}, true, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockIndicatorService)
+			r := setupIndicatorLinkRouter(NewIndicatorHandler(mockService))
+
+			mockService.On("LinkActor", mock.Anything, testIndicatorID, testActorID, tt.input).Return(&model.IndicatorActorLink{
+				IndicatorID: testIndicatorID, ActorID: testActorID, AttributionConfidence: 85,
+			}, tt.created, nil)
+
+			req := httptest.NewRequest("PUT", "/api/indicators/"+testIndicatorID+"/actors/"+testActorID, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIndicatorHandler_LinkActor_InvalidConfidence(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorLinkRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("LinkActor", mock.Anything, testIndicatorID, testActorID, mock.Anything).
+		Return(nil, false, &service.ValidationError{Field: "confidence", Message: "must be between 0 and 100"})
+
+	req := httptest.NewRequest("PUT", "/api/indicators/"+testIndicatorID+"/actors/"+testActorID, strings.NewReader(`{"confidence":150}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestIndicatorHandler_LinkActor_InvalidActorID(t *testing.T) {
+	r := setupIndicatorLinkRouter(NewIndicatorHandler(new(MockIndicatorService)))
+
+	req := httptest.NewRequest("PUT", "/api/indicators/"+testIndicatorID+"/actors/not-a-uuid", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid threat actor ID format")
+}
+
+func TestIndicatorHandler_UnlinkActor_NotFound(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorLinkRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("UnlinkActor", mock.Anything, testIndicatorID, testActorID).Return(repository.ErrNotFound)
+
+	req := httptest.NewRequest("DELETE", "/api/indicators/"+testIndicatorID+"/actors/"+testActorID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_LinkCampaign(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorLinkRouter(NewIndicatorHandler(mockService))
+
+	input := model.CampaignIndicatorInput{Notes: "staging server"}
+	mockService.On("LinkCampaign", mock.Anything, testIndicatorID, testCampaignID, input).Return(&model.IndicatorCampaignLink{
+		IndicatorID: testIndicatorID, CampaignID: testCampaignID, Notes: "staging server",
+	}, true, nil)
+
+	req := httptest.NewRequest("PUT", "/api/indicators/"+testIndicatorID+"/campaigns/"+testCampaignID, strings.NewReader(`{"notes":"staging server"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"notes":"staging server"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_UnlinkCampaign(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorLinkRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("UnlinkCampaign", mock.Anything, testIndicatorID, testCampaignID).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/api/indicators/"+testIndicatorID+"/campaigns/"+testCampaignID, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_LinkSample(t *testing.T) {
+	const siblingID = "7a1c2f3e-1111-4222-8333-444455556666"
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"linked", nil, http.StatusOK},
+		{"not a visible hash", repository.ErrNotFound, http.StatusNotFound},
+		{"algorithm already in sample", repository.ErrConflict, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockIndicatorService)
+			r := chi.NewRouter()
+			r.Put("/api/indicators/{id}/sample/{siblingId}", NewIndicatorHandler(mockService).LinkSample)
+
+			var sample *model.IndicatorSample
+			if tt.err == nil {
+				sample = &model.IndicatorSample{ID: "sample-1"}
+			}
+			mockService.On("LinkSample", mock.Anything, testIndicatorID, siblingID).Return(sample, tt.err)
+
+			req := httptest.NewRequest("PUT", "/api/indicators/"+testIndicatorID+"/sample/"+siblingID, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestIndicatorHandler_UnlinkSample(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := chi.NewRouter()
+	r.Delete("/api/indicators/{id}/sample", NewIndicatorHandler(mockService).UnlinkSample)
+
+	mockService.On("UnlinkSample", mock.Anything, testIndicatorID).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/api/indicators/"+testIndicatorID+"/sample", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testRevokedIndicatorID = "550e8400-e29b-41d4-a716-446655440000"
+
+func setupIndicatorRevocationRouter(handler *IndicatorHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/indicators/search", handler.Search)
+	r.Post("/api/indicators/upsert", handler.Upsert)
+	r.Post("/api/indicators/{id}/revoke", handler.Revoke)
+	r.Post("/api/indicators/{id}/restore", handler.Restore)
+	return r
+}
+
+func TestIndicatorHandler_Revoke(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorRevocationRouter(NewIndicatorHandler(mockService))
+
+	revokedAt := time.Now()
+	mockService.On("Revoke", mock.Anything, testRevokedIndicatorID, model.IndicatorRevokeInput{Reason: "false_positive"}).
+		Return(&model.Indicator{ID: testRevokedIndicatorID, RevokedAt: &revokedAt, RevocationReason: "false_positive"}, nil)
+
+	req := httptest.NewRequest("POST", "/api/indicators/"+testRevokedIndicatorID+"/revoke", strings.NewReader(`{"reason":"false_positive"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"revocation_reason":"false_positive"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Revoke_InvalidReason(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorRevocationRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("Revoke", mock.Anything, testRevokedIndicatorID, mock.Anything).
+		Return(nil, &service.ValidationError{Field: "reason", Message: "must be one of: false_positive, expired, superseded"})
+
+	req := httptest.NewRequest("POST", "/api/indicators/"+testRevokedIndicatorID+"/revoke", strings.NewReader(`{"reason":"oops"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestIndicatorHandler_Restore_NotFound(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorRevocationRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("Restore", mock.Anything, testRevokedIndicatorID).Return(nil, repository.ErrNotFound)
+
+	req := httptest.NewRequest("POST", "/api/indicators/"+testRevokedIndicatorID+"/restore", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestIndicatorHandler_Upsert_FalsePositiveNeedsForce(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorRevocationRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("Upsert", mock.Anything, mock.Anything, false).Return(nil, false, repository.ErrFalsePositive)
+	mockService.On("Upsert", mock.Anything, mock.Anything, true).Return(&model.Indicator{ID: testRevokedIndicatorID}, false, nil)
+
+	body := `{"type":"ip","value":"10.0.0.1"}`
+	req := httptest.NewRequest("POST", "/api/indicators/upsert", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "force=true")
+
+	req = httptest.NewRequest("POST", "/api/indicators/upsert?force=true", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Search_IncludeRevoked(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := setupIndicatorRevocationRouter(NewIndicatorHandler(mockService))
+
+	mockService.On("Search", mock.Anything, mock.MatchedBy(func(p model.SearchParams) bool {
+		return p.IncludeRevoked
+	})).Return(&model.SearchResult{Data: []model.IndicatorSearchResult{}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/search?include_revoked=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/indicators/search?include_revoked=maybe", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNumberOfCalls(t, "Search", 1)
+}
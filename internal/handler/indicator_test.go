@@ -4,12 +4,27 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+func setupIndicatorWriteRouter(handler *IndicatorHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Post("/api/indicators", handler.Create)
+	r.Post("/api/indicators/upsert", handler.Upsert)
+	r.Put("/api/indicators/{id}", handler.Update)
+	r.Patch("/api/indicators/{id}", handler.Patch)
+	r.Delete("/api/indicators/{id}", handler.Delete)
+	return r
+}
+
 func TestIndicatorHandler_GetByID_InvalidID(t *testing.T) {
 	r := chi.NewRouter()
 	handler := &IndicatorHandler{service: nil}
@@ -46,24 +61,17 @@ func TestIndicatorHandler_Search_InvalidType(t *testing.T) {
 }
 
 func TestIndicatorHandler_Search_ValidTypes(t *testing.T) {
-	validTypes := []string{"ip", "domain", "url", "hash"}
-
-	for _, validType := range validTypes {
-		t.Run("type_"+validType, func(t *testing.T) {
+	for _, validType := range model.IndicatorTypes() {
+		t.Run("type_"+string(validType), func(t *testing.T) {
+			mockService := new(MockIndicatorService)
 			r := chi.NewRouter()
-			_ = &IndicatorHandler{service: nil}
-
-			r.Get("/api/indicators/search", func(w http.ResponseWriter, r *http.Request) {
-				params := r.URL.Query().Get("type")
-				validTypesMap := map[string]bool{"ip": true, "domain": true, "url": true, "hash": true}
-				if params != "" && !validTypesMap[params] {
-					respondBadRequest(w, "Invalid type")
-					return
-				}
-				respondSuccess(w, map[string]string{"validated": "true"})
-			})
-
-			req := httptest.NewRequest("GET", "/api/indicators/search?type="+validType, nil)
+			r.Get("/api/indicators/search", NewIndicatorHandler(mockService).Search)
+
+			mockService.On("Search", mock.Anything, mock.MatchedBy(func(p model.SearchParams) bool {
+				return p.Type == string(validType)
+			})).Return(&model.SearchResult{Data: []model.IndicatorSearchResult{}}, nil)
+
+			req := httptest.NewRequest("GET", "/api/indicators/search?type="+string(validType), nil)
 			w := httptest.NewRecorder()
 
 			r.ServeHTTP(w, req)
@@ -102,3 +110,268 @@ func TestRespondError(t *testing.T) {
 	assert.Equal(t, ErrCodeBadRequest, response.Error.Code)
 	assert.Equal(t, "Test error", response.Error.Message)
 }
+
+func TestIndicatorHandler_Create_Success(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := setupIndicatorWriteRouter(handler)
+
+	created := &model.Indicator{ID: "550e8400-e29b-41d4-a716-446655440000", Type: "ip", Value: "10.0.0.1"}
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("model.IndicatorInput")).Return(created, nil)
+
+	body := `{"type":"ip","value":"10.0.0.1","confidence":80}`
+	req := httptest.NewRequest("POST", "/api/indicators", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response APIResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.True(t, response.Success)
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Create_InvalidJSON(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := setupIndicatorWriteRouter(handler)
+
+	req := httptest.NewRequest("POST", "/api/indicators", strings.NewReader(`{"type":`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestIndicatorHandler_Create_ValidationError(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := setupIndicatorWriteRouter(handler)
+
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("model.IndicatorInput")).
+		Return(nil, &service.ValidationError{Field: "confidence", Message: "must be between 0 and 100"})
+
+	req := httptest.NewRequest("POST", "/api/indicators", strings.NewReader(`{"type":"ip","value":"10.0.0.1","confidence":150}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response APIResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.False(t, response.Success)
+	assert.Equal(t, ErrCodeValidation, response.Error.Code)
+	assert.Contains(t, response.Error.Message, "confidence")
+}
+
+func TestIndicatorHandler_Update_NotFound(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := setupIndicatorWriteRouter(handler)
+
+	mockService.On("Update", mock.Anything, "550e8400-e29b-41d4-a716-446655440000", mock.AnythingOfType("model.IndicatorInput")).
+		Return(nil, repository.ErrNotFound)
+
+	req := httptest.NewRequest("PUT", "/api/indicators/550e8400-e29b-41d4-a716-446655440000", strings.NewReader(`{"type":"ip","value":"10.0.0.1"}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Patch_InvalidID(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := setupIndicatorWriteRouter(handler)
+
+	req := httptest.NewRequest("PATCH", "/api/indicators/not-a-uuid", strings.NewReader(`{"confidence":10}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestIndicatorHandler_Delete_Success(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := setupIndicatorWriteRouter(handler)
+
+	mockService.On("Delete", mock.Anything, "550e8400-e29b-41d4-a716-446655440000").Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/api/indicators/550e8400-e29b-41d4-a716-446655440000", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Create_Conflict(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := setupIndicatorWriteRouter(handler)
+
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("model.IndicatorInput")).Return(nil, repository.ErrConflict)
+
+	req := httptest.NewRequest("POST", "/api/indicators", strings.NewReader(`{"type":"ip","value":"10.0.0.1"}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response APIResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, ErrCodeConflict, response.Error.Code)
+}
+
+func TestIndicatorHandler_WriteOverHiddenIndicator_DoesNotRevealIt(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	handler := NewIndicatorHandler(mockService)
+	r := setupIndicatorWriteRouter(handler)
+
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("model.IndicatorInput")).Return(nil, repository.ErrNotWritable)
+	mockService.On("Upsert", mock.Anything, mock.AnythingOfType("model.IndicatorInput"), false).Return(nil, false, repository.ErrNotWritable)
+
+	for _, path := range []string{"/api/indicators", "/api/indicators/upsert"} {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"type":"ip","value":"10.0.0.1"}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, path)
+		var response APIResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, ErrCodeValidation, response.Error.Code, path)
+		assert.NotContains(t, strings.ToLower(response.Error.Message), "exists", path)
+		assert.NotContains(t, strings.ToLower(response.Error.Message), "tlp", path)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestIndicatorHandler_Upsert_StatusReflectsOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		created  bool
+		expected int
+	}{
+		{"inserted", true, http.StatusCreated},
+		{"merged", false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockIndicatorService)
+			handler := NewIndicatorHandler(mockService)
+			r := setupIndicatorWriteRouter(handler)
+
+			indicator := &model.Indicator{ID: "550e8400-e29b-41d4-a716-446655440000", Type: "ip", Value: "10.0.0.1"}
+			mockService.On("Upsert", mock.Anything, mock.AnythingOfType("model.IndicatorInput"), false).Return(indicator, tt.created, nil)
+
+			req := httptest.NewRequest("POST", "/api/indicators/upsert", strings.NewReader(`{"type":"ip","value":"10.0.0.1"}`))
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIndicatorHandler_Search_IPRangeFilters(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := chi.NewRouter()
+	r.Get("/api/indicators/search", NewIndicatorHandler(mockService).Search)
+
+	mockService.On("Search", mock.Anything, mock.MatchedBy(func(p model.SearchParams) bool {
+		return p.CIDR == "10.1.0.0/16" && p.IPInRange == "2001:db8::1"
+	})).Return(&model.SearchResult{Data: []model.IndicatorSearchResult{}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/search?cidr=10.1.2.3/16&ip_in_range=2001:DB8::1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, query := range []string{"cidr=10.1.0.0", "cidr=10.0.0.0/40", "ip_in_range=10.0.0.0/8", "ip_in_range=fe80::1%25eth0"} {
+		req = httptest.NewRequest("GET", "/api/indicators/search?"+query, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNumberOfCalls(t, "Search", 1)
+}
+
+func TestIndicatorHandler_Search_HashAlgoFilter(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := chi.NewRouter()
+	r.Get("/api/indicators/search", NewIndicatorHandler(mockService).Search)
+
+	mockService.On("Search", mock.Anything, mock.MatchedBy(func(p model.SearchParams) bool {
+		return p.HashAlgo == model.HashAlgoSHA256
+	})).Return(&model.SearchResult{Data: []model.IndicatorSearchResult{}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/search?hash_algo=SHA256", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/indicators/search?hash_algo=crc32", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNumberOfCalls(t, "Search", 1)
+}
+
+func TestIndicatorHandler_Search_TextQuery(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := chi.NewRouter()
+	r.Get("/api/indicators/search", NewIndicatorHandler(mockService).Search)
+
+	mockService.On("Search", mock.Anything, mock.MatchedBy(func(p model.SearchParams) bool {
+		return p.Query == `"cobalt strike" -test`
+	})).Return(&model.SearchResult{Data: []model.IndicatorSearchResult{}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/search?q=+%22cobalt+strike%22+-test+", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/indicators/search?q="+strings.Repeat("a", 257), nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNumberOfCalls(t, "Search", 1)
+}
+
+func TestIndicatorHandler_Search_DomainFilters(t *testing.T) {
+	mockService := new(MockIndicatorService)
+	r := chi.NewRouter()
+	r.Get("/api/indicators/search", NewIndicatorHandler(mockService).Search)
+
+	mockService.On("Search", mock.Anything, mock.MatchedBy(func(p model.SearchParams) bool {
+		return p.DomainSuffix == "evil.example.com" && p.RegisteredDomain == "example.co.uk" && p.TLD == "ru"
+	})).Return(&model.SearchResult{Data: []model.IndicatorSearchResult{}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/indicators/search?domain_suffix=Evil.Example.com.&registered_domain=EXAMPLE.co.uk&tld=.ru", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, query := range []string{"domain_suffix=not%20a%20domain", "registered_domain=www.example.com", "registered_domain=co.uk", "tld=co.uk"} {
+		req = httptest.NewRequest("GET", "/api/indicators/search?"+query, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNumberOfCalls(t, "Search", 1)
+}
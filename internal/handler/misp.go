@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type MispHandler struct {
+	service service.MispServiceInterface
+}
+
+func NewMispHandler(svc service.MispServiceInterface) *MispHandler {
+	return &MispHandler{service: svc}
+}
+
+func (h *MispHandler) Import(w http.ResponseWriter, r *http.Request) {
+	force, ok := parseBoolQuery(w, r, "force")
+	if !ok {
+		return
+	}
+
+	payload, ok := readImportPayload(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Import(r.Context(), payload, force)
+	if err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			respondBadRequest(w, "Invalid MISP event: "+validationErr.Message)
+			return
+		}
+		slog.Error("Failed to import MISP events", "error", err)
+		respondInternalError(w)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+func (h *MispHandler) ExportEvent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		respondBadRequest(w, "Invalid campaign ID format")
+		return
+	}
+
+	event, err := h.service.ExportEvent(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondNotFound(w, "Campaign not found")
+			return
+		}
+		slog.Error("Failed to export MISP event", "error", err, "campaign", id)
+		respondInternalError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(event)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/misp"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupMispRouter(handler *MispHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Post("/api/misp/import", handler.Import)
+	r.Get("/api/misp/events/{id}", handler.ExportEvent)
+	return r
+}
+
+func TestMispHandler_Import_Success(t *testing.T) {
+	mockService := new(MockMispService)
+	r := setupMispRouter(NewMispHandler(mockService))
+
+	body := `{"Event": {"uuid": "5e5d0b55-9d3c-4c5a-8d2f-4a1c2b3d4e5f", "info": "test"}}`
+	mockService.On("Import", mock.Anything, []byte(body), false).Return(&model.ImportResult{
+		Campaigns: model.ImportCounts{Created: 1},
+		Errors:    []model.ImportError{},
+	}, nil)
+
+	req := httptest.NewRequest("POST", "/api/misp/import", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"campaigns":{"created":1,"updated":0}`)
+	mockService.AssertExpectations(t)
+}
+
+func TestMispHandler_Import_InvalidEvent(t *testing.T) {
+	mockService := new(MockMispService)
+	r := setupMispRouter(NewMispHandler(mockService))
+
+	mockService.On("Import", mock.Anything, mock.Anything, false).Return(nil, &service.ValidationError{Field: "event", Message: "payload contains no events"})
+
+	req := httptest.NewRequest("POST", "/api/misp/import", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid MISP event")
+}
+
+func TestMispHandler_ExportEvent_Success(t *testing.T) {
+	mockService := new(MockMispService)
+	r := setupMispRouter(NewMispHandler(mockService))
+
+	id := "550e8400-e29b-41d4-a716-446655440000"
+	mockService.On("ExportEvent", mock.Anything, id).Return(&misp.EventWrapper{Event: misp.Event{UUID: id, Info: "Operation Test", Attribute: []misp.Attribute{}}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/misp/events/"+id, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"Event":{"uuid":"550e8400-e29b-41d4-a716-446655440000","info":"Operation Test"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestMispHandler_ExportEvent_NotFound(t *testing.T) {
+	mockService := new(MockMispService)
+	r := setupMispRouter(NewMispHandler(mockService))
+
+	id := "550e8400-e29b-41d4-a716-446655440000"
+	mockService.On("ExportEvent", mock.Anything, id).Return(nil, repository.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/api/misp/events/"+id, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
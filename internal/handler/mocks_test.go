@@ -2,8 +2,12 @@ package handler
 
 import (
 	"context"
+	"time"
 
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/misp"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/stix"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/taxii"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -27,10 +31,208 @@ func (m *MockIndicatorService) Search(ctx context.Context, params model.SearchPa
 	return args.Get(0).(*model.SearchResult), args.Error(1)
 }
 
+func (m *MockIndicatorService) Export(ctx context.Context, params model.SearchParams, fn func(*model.Indicator) error) error {
+	args := m.Called(ctx, params)
+	if indicators, ok := args.Get(0).([]model.Indicator); ok {
+		for i := range indicators {
+			if err := fn(&indicators[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockIndicatorService) ExportAttributed(ctx context.Context, params model.SearchParams, fn func(*model.AttributedIndicator) error) error {
+	args := m.Called(ctx, params)
+	if indicators, ok := args.Get(0).([]model.AttributedIndicator); ok {
+		for i := range indicators {
+			if err := fn(&indicators[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockIndicatorService) Create(ctx context.Context, input model.IndicatorInput) (*model.Indicator, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Indicator), args.Error(1)
+}
+
+func (m *MockIndicatorService) Update(ctx context.Context, id string, input model.IndicatorInput) (*model.Indicator, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Indicator), args.Error(1)
+}
+
+func (m *MockIndicatorService) Patch(ctx context.Context, id string, input model.IndicatorInput) (*model.Indicator, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Indicator), args.Error(1)
+}
+
+func (m *MockIndicatorService) Upsert(ctx context.Context, input model.IndicatorInput, force bool) (*model.Indicator, bool, error) {
+	args := m.Called(ctx, input, force)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*model.Indicator), args.Bool(1), args.Error(2)
+}
+
+func (m *MockIndicatorService) BulkIngest(ctx context.Context, items []model.BulkIndicatorItem, force bool) (*model.BulkResult, error) {
+	args := m.Called(ctx, items, force)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BulkResult), args.Error(1)
+}
+
+func (m *MockIndicatorService) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorService) Revoke(ctx context.Context, id string, input model.IndicatorRevokeInput) (*model.Indicator, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Indicator), args.Error(1)
+}
+
+func (m *MockIndicatorService) Restore(ctx context.Context, id string) (*model.Indicator, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Indicator), args.Error(1)
+}
+
+func (m *MockIndicatorService) LinkActor(ctx context.Context, indicatorID, actorID string, input model.IndicatorActorInput) (*model.IndicatorActorLink, bool, error) {
+	args := m.Called(ctx, indicatorID, actorID, input)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*model.IndicatorActorLink), args.Bool(1), args.Error(2)
+}
+
+func (m *MockIndicatorService) UnlinkActor(ctx context.Context, indicatorID, actorID string) error {
+	args := m.Called(ctx, indicatorID, actorID)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorService) LinkCampaign(ctx context.Context, indicatorID, campaignID string, input model.CampaignIndicatorInput) (*model.IndicatorCampaignLink, bool, error) {
+	args := m.Called(ctx, indicatorID, campaignID, input)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*model.IndicatorCampaignLink), args.Bool(1), args.Error(2)
+}
+
+func (m *MockIndicatorService) UnlinkCampaign(ctx context.Context, indicatorID, campaignID string) error {
+	args := m.Called(ctx, indicatorID, campaignID)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorService) LinkSample(ctx context.Context, indicatorID, siblingID string) (*model.IndicatorSample, error) {
+	args := m.Called(ctx, indicatorID, siblingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IndicatorSample), args.Error(1)
+}
+
+func (m *MockIndicatorService) UnlinkSample(ctx context.Context, indicatorID string) error {
+	args := m.Called(ctx, indicatorID)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorService) GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.IndicatorWithRelations, error) {
+	args := m.Called(ctx, id, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IndicatorWithRelations), args.Error(1)
+}
+
+func (m *MockIndicatorService) History(ctx context.Context, id string, params model.IndicatorHistoryParams) (*model.IndicatorHistoryResult, error) {
+	args := m.Called(ctx, id, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IndicatorHistoryResult), args.Error(1)
+}
+
 type MockCampaignService struct {
 	mock.Mock
 }
 
+func (m *MockCampaignService) List(ctx context.Context, params model.CampaignListParams) (*model.CampaignListResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CampaignListResult), args.Error(1)
+}
+
+func (m *MockCampaignService) GetByID(ctx context.Context, id string) (*model.Campaign, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignService) Create(ctx context.Context, input model.CampaignInput) (*model.Campaign, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignService) Update(ctx context.Context, id string, input model.CampaignInput) (*model.Campaign, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignService) Patch(ctx context.Context, id string, input model.CampaignInput) (*model.Campaign, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignService) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCampaignService) LinkIndicator(ctx context.Context, campaignID, indicatorID string, input model.CampaignIndicatorInput) (*model.IndicatorCampaignLink, bool, error) {
+	args := m.Called(ctx, campaignID, indicatorID, input)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*model.IndicatorCampaignLink), args.Bool(1), args.Error(2)
+}
+
+func (m *MockCampaignService) UnlinkIndicator(ctx context.Context, campaignID, indicatorID string) error {
+	args := m.Called(ctx, campaignID, indicatorID)
+	return args.Error(0)
+}
+
 func (m *MockCampaignService) GetIndicatorsTimeline(ctx context.Context, campaignID string, params model.TimelineParams) (*model.CampaignWithTimeline, error) {
 	args := m.Called(ctx, campaignID, params)
 	if args.Get(0) == nil {
@@ -39,6 +241,42 @@ func (m *MockCampaignService) GetIndicatorsTimeline(ctx context.Context, campaig
 	return args.Get(0).(*model.CampaignWithTimeline), args.Error(1)
 }
 
+type MockActorService struct {
+	mock.Mock
+}
+
+func (m *MockActorService) List(ctx context.Context, params model.ActorListParams) (*model.ActorListResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ActorListResult), args.Error(1)
+}
+
+func (m *MockActorService) GetByID(ctx context.Context, id string) (*model.ThreatActorDetail, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ThreatActorDetail), args.Error(1)
+}
+
+func (m *MockActorService) GetIndicators(ctx context.Context, actorID string, params model.ActorIndicatorParams) (*model.ActorIndicatorResult, error) {
+	args := m.Called(ctx, actorID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ActorIndicatorResult), args.Error(1)
+}
+
+func (m *MockActorService) SetAliases(ctx context.Context, actorID string, input model.ActorAliasesInput) (*model.ThreatActorDetail, error) {
+	args := m.Called(ctx, actorID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ThreatActorDetail), args.Error(1)
+}
+
 type MockDashboardService struct {
 	mock.Mock
 }
@@ -50,3 +288,119 @@ func (m *MockDashboardService) GetSummary(ctx context.Context, timeRange string)
 	}
 	return args.Get(0).(*model.DashboardSummary), args.Error(1)
 }
+
+type MockStixService struct {
+	mock.Mock
+}
+
+func (m *MockStixService) ExportIndicator(ctx context.Context, indicatorID string) (*stix.Bundle, error) {
+	args := m.Called(ctx, indicatorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*stix.Bundle), args.Error(1)
+}
+
+func (m *MockStixService) ExportCampaign(ctx context.Context, campaignID string) (*stix.Bundle, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*stix.Bundle), args.Error(1)
+}
+
+func (m *MockStixService) Import(ctx context.Context, payload []byte, force bool) (*model.ImportResult, error) {
+	args := m.Called(ctx, payload, force)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportResult), args.Error(1)
+}
+
+type MockTaxiiService struct {
+	mock.Mock
+}
+
+func (m *MockTaxiiService) Collections(ctx context.Context) ([]taxii.Collection, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]taxii.Collection), args.Error(1)
+}
+
+func (m *MockTaxiiService) Collection(ctx context.Context, id string) (*taxii.Collection, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*taxii.Collection), args.Error(1)
+}
+
+func (m *MockTaxiiService) Objects(ctx context.Context, collectionID string, params model.TaxiiObjectParams) (*taxii.Envelope, error) {
+	args := m.Called(ctx, collectionID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*taxii.Envelope), args.Error(1)
+}
+
+type MockMispService struct {
+	mock.Mock
+}
+
+func (m *MockMispService) Import(ctx context.Context, payload []byte, force bool) (*model.ImportResult, error) {
+	args := m.Called(ctx, payload, force)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportResult), args.Error(1)
+}
+
+func (m *MockMispService) ExportEvent(ctx context.Context, campaignID string) (*misp.EventWrapper, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*misp.EventWrapper), args.Error(1)
+}
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) Create(ctx context.Context, input model.APIKeyInput) (*model.IssuedAPIKey, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IssuedAPIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) List(ctx context.Context) ([]model.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyService) Revoke(ctx context.Context, id string) (*model.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) List(ctx context.Context, params model.AuditListParams) (*model.AuditListResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AuditListResult), args.Error(1)
+}
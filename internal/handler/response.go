@@ -20,8 +20,11 @@ const (
 	ErrCodeNotFound       = "NOT_FOUND"
 	ErrCodeBadRequest     = "BAD_REQUEST"
 	ErrCodeValidation     = "VALIDATION_ERROR"
+	ErrCodeConflict       = "CONFLICT"
 	ErrCodeInternalServer = "INTERNAL_ERROR"
 	ErrCodeRateLimited    = "RATE_LIMITED"
+	ErrCodeUnauthorized   = "UNAUTHORIZED"
+	ErrCodeForbidden      = "FORBIDDEN"
 )
 
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -37,6 +40,17 @@ func respondSuccess(w http.ResponseWriter, data interface{}) {
 	})
 }
 
+func respondCreated(w http.ResponseWriter, data interface{}) {
+	respondJSON(w, http.StatusCreated, APIResponse{
+		Success: true,
+		Data:    data,
+	})
+}
+
+func respondNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func respondError(w http.ResponseWriter, status int, code, message string) {
 	respondJSON(w, status, APIResponse{
 		Success: false,
@@ -58,3 +72,11 @@ func respondBadRequest(w http.ResponseWriter, message string) {
 func respondInternalError(w http.ResponseWriter) {
 	respondError(w, http.StatusInternalServerError, ErrCodeInternalServer, "Internal server error")
 }
+
+func respondValidationError(w http.ResponseWriter, message string) {
+	respondError(w, http.StatusUnprocessableEntity, ErrCodeValidation, message)
+}
+
+func respondConflict(w http.ResponseWriter, message string) {
+	respondError(w, http.StatusConflict, ErrCodeConflict, message)
+}
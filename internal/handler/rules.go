@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/rules"
+)
+
+type attributedEncoder interface {
+	streamEncoder
+	Encode(ind *model.AttributedIndicator) error
+}
+
+func (h *IndicatorHandler) Rules(w http.ResponseWriter, r *http.Request) {
+	if engine := r.URL.Query().Get("engine"); engine != rules.EngineSuricata {
+		respondBadRequest(w, "Invalid rule engine. Must be one of: suricata")
+		return
+	}
+
+	params, ok := parseSearchFilters(w, r)
+	if !ok {
+		return
+	}
+	params.ActiveOnly = true
+	params.IncludeRevoked = false
+
+	filename := "threat-intel.rules"
+	var enc attributedEncoder
+	if name := r.URL.Query().Get("list"); name != "" {
+		list, ok := rules.HashLists[name]
+		if !ok {
+			respondBadRequest(w, "Invalid hash list. Must be one of: md5, sha1, sha256")
+			return
+		}
+		if params.Type != "" && params.Type != string(model.IndicatorTypeHash) {
+			respondBadRequest(w, "Hash lists only contain hash indicators")
+			return
+		}
+		params.Type = string(model.IndicatorTypeHash)
+		filename = list.Filename
+		enc = rules.NewHashListWriter(list, w)
+	} else {
+		enc = rules.NewSuricataWriter(w)
+	}
+
+	stream := newExportStream(w, "text/plain; charset=utf-8", filename, enc)
+	err := h.service.ExportAttributed(r.Context(), params, func(ind *model.AttributedIndicator) error {
+		if err := stream.begin(); err != nil {
+			return err
+		}
+		if err := enc.Encode(ind); err != nil {
+			return err
+		}
+		return stream.rowWritten()
+	})
+
+	stream.finish(r, err)
+}
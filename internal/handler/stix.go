@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/stix"
+	"github.com/google/uuid"
+)
+
+const maxImportBodyBytes = 64 << 20
+
+type StixHandler struct {
+	service service.StixServiceInterface
+}
+
+func NewStixHandler(svc service.StixServiceInterface) *StixHandler {
+	return &StixHandler{service: svc}
+}
+
+func (h *StixHandler) ExportBundle(w http.ResponseWriter, r *http.Request) {
+	campaignID := r.URL.Query().Get("campaign")
+	if campaignID == "" {
+		respondBadRequest(w, "The campaign query parameter is required")
+		return
+	}
+
+	if _, err := uuid.Parse(campaignID); err != nil {
+		respondBadRequest(w, "Invalid campaign ID format")
+		return
+	}
+
+	bundle, err := h.service.ExportCampaign(r.Context(), campaignID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondNotFound(w, "Campaign not found")
+			return
+		}
+		slog.Error("Failed to export STIX bundle", "error", err, "campaign", campaignID)
+		respondInternalError(w)
+		return
+	}
+
+	respondSTIX(w, bundle)
+}
+
+func (h *StixHandler) ExportIndicator(w http.ResponseWriter, r *http.Request) {
+	id, ok := indicatorIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	bundle, err := h.service.ExportIndicator(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondNotFound(w, "Indicator not found")
+			return
+		}
+		slog.Error("Failed to export STIX indicator", "error", err, "id", id)
+		respondInternalError(w)
+		return
+	}
+
+	respondSTIX(w, bundle)
+}
+
+func (h *StixHandler) Import(w http.ResponseWriter, r *http.Request) {
+	force, ok := parseBoolQuery(w, r, "force")
+	if !ok {
+		return
+	}
+
+	payload, ok := readImportPayload(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Import(r.Context(), payload, force)
+	if err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			respondBadRequest(w, "Invalid STIX bundle: "+validationErr.Message)
+			return
+		}
+		slog.Error("Failed to import STIX bundle", "error", err)
+		respondInternalError(w)
+		return
+	}
+
+	respondSuccess(w, result)
+}
+
+func readImportPayload(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(w, http.StatusRequestEntityTooLarge, ErrCodeBadRequest, "Request body too large")
+			return nil, false
+		}
+		respondBadRequest(w, "Failed to read request body")
+		return nil, false
+	}
+	return payload, true
+}
+
+func respondSTIX(w http.ResponseWriter, bundle *stix.Bundle) {
+	w.Header().Set("Content-Type", stix.MediaType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(bundle)
+}
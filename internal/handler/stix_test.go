@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/stix"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupStixRouter(handler *StixHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/api/stix/bundle", handler.ExportBundle)
+	r.Get("/api/stix/indicators/{id}", handler.ExportIndicator)
+	r.Post("/api/stix/import", handler.Import)
+	return r
+}
+
+func TestStixHandler_ExportBundle_Success(t *testing.T) {
+	mockService := new(MockStixService)
+	r := setupStixRouter(NewStixHandler(mockService))
+
+	mockService.On("ExportCampaign", mock.Anything, "550e8400-e29b-41d4-a716-446655440000").Return(stix.NewBundle(nil), nil)
+
+	req := httptest.NewRequest("GET", "/api/stix/bundle?campaign=550e8400-e29b-41d4-a716-446655440000", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, stix.MediaType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"type":"bundle"`)
+	assert.Contains(t, w.Body.String(), `"objects":[]`)
+	mockService.AssertExpectations(t)
+}
+
+func TestStixHandler_ExportBundle_MissingCampaign(t *testing.T) {
+	mockService := new(MockStixService)
+	r := setupStixRouter(NewStixHandler(mockService))
+
+	req := httptest.NewRequest("GET", "/api/stix/bundle", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestStixHandler_ExportIndicator_NotFound(t *testing.T) {
+	mockService := new(MockStixService)
+	r := setupStixRouter(NewStixHandler(mockService))
+
+	mockService.On("ExportIndicator", mock.Anything, "550e8400-e29b-41d4-a716-446655440000").Return(nil, repository.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/api/stix/indicators/550e8400-e29b-41d4-a716-446655440000", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestStixHandler_Import_Success(t *testing.T) {
+	mockService := new(MockStixService)
+	r := setupStixRouter(NewStixHandler(mockService))
+
+	body := `{"type": "bundle", "id": "bundle--5d0092c5-5f74-4287-9642-33f4c354e56d", "objects": []}`
+	mockService.On("Import", mock.Anything, []byte(body), false).Return(&model.ImportResult{
+		Indicators: model.ImportCounts{Created: 2, Updated: 1},
+		Errors:     []model.ImportError{},
+	}, nil)
+
+	req := httptest.NewRequest("POST", "/api/stix/import", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"indicators":{"created":2,"updated":1}`)
+	mockService.AssertExpectations(t)
+}
+
+func TestStixHandler_Import_InvalidBundle(t *testing.T) {
+	mockService := new(MockStixService)
+	r := setupStixRouter(NewStixHandler(mockService))
+
+	mockService.On("Import", mock.Anything, mock.Anything, false).Return(nil, &service.ValidationError{Field: "bundle", Message: "invalid bundle"})
+
+	req := httptest.NewRequest("POST", "/api/stix/import", strings.NewReader(`[]`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid STIX bundle")
+}
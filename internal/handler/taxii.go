@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/service"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/taxii"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	taxiiAPIRootPath     = "/taxii2/api/"
+	taxiiDateAddedLayout = "2006-01-02T15:04:05.000000Z"
+)
+
+type TaxiiHandler struct {
+	service service.TaxiiServiceInterface
+}
+
+func NewTaxiiHandler(svc service.TaxiiServiceInterface) *TaxiiHandler {
+	return &TaxiiHandler{service: svc}
+}
+
+func (h *TaxiiHandler) Discovery(w http.ResponseWriter, r *http.Request) {
+	if !acceptsTAXII(w, r) {
+		return
+	}
+
+	apiRoot := requestBaseURL(r) + taxiiAPIRootPath
+	respondTAXII(w, http.StatusOK, taxii.Discovery{
+		Title:       "Threat Intel API",
+		Description: "TAXII 2.1 feed of indicators and campaigns",
+		Default:     apiRoot,
+		APIRoots:    []string{apiRoot},
+	})
+}
+
+func (h *TaxiiHandler) APIRoot(w http.ResponseWriter, r *http.Request) {
+	if !acceptsTAXII(w, r) {
+		return
+	}
+
+	respondTAXII(w, http.StatusOK, taxii.APIRoot{
+		Title:            "Threat Intel API",
+		Versions:         []string{taxii.MediaType},
+		MaxContentLength: maxJSONBodyBytes,
+	})
+}
+
+func (h *TaxiiHandler) Collections(w http.ResponseWriter, r *http.Request) {
+	if !acceptsTAXII(w, r) {
+		return
+	}
+
+	collections, err := h.service.Collections(r.Context())
+	if err != nil {
+		slog.Error("Failed to list TAXII collections", "error", err)
+		respondTAXIIError(w, http.StatusInternalServerError, "Internal server error", "")
+		return
+	}
+
+	respondTAXII(w, http.StatusOK, taxii.Collections{Collections: collections})
+}
+
+func (h *TaxiiHandler) Collection(w http.ResponseWriter, r *http.Request) {
+	if !acceptsTAXII(w, r) {
+		return
+	}
+
+	id, ok := taxiiCollectionID(w, r)
+	if !ok {
+		return
+	}
+
+	collection, err := h.service.Collection(r.Context(), id)
+	if err != nil {
+		h.handleCollectionError(w, err, id)
+		return
+	}
+
+	respondTAXII(w, http.StatusOK, collection)
+}
+
+func (h *TaxiiHandler) Objects(w http.ResponseWriter, r *http.Request) {
+	if !acceptsTAXII(w, r) {
+		return
+	}
+
+	id, ok := taxiiCollectionID(w, r)
+	if !ok {
+		return
+	}
+
+	params, err := parseTaxiiObjectParams(r)
+	if err != nil {
+		respondTAXIIError(w, http.StatusBadRequest, "Invalid query parameter", err.Error())
+		return
+	}
+
+	envelope, err := h.service.Objects(r.Context(), id, params)
+	if err != nil {
+		h.handleCollectionError(w, err, id)
+		return
+	}
+
+	if !envelope.DateAddedFirst.IsZero() {
+		w.Header().Set("X-TAXII-Date-Added-First", envelope.DateAddedFirst.UTC().Format(taxiiDateAddedLayout))
+		w.Header().Set("X-TAXII-Date-Added-Last", envelope.DateAddedLast.UTC().Format(taxiiDateAddedLayout))
+	}
+	respondTAXII(w, http.StatusOK, envelope)
+}
+
+func (h *TaxiiHandler) handleCollectionError(w http.ResponseWriter, err error, id string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		respondTAXIIError(w, http.StatusNotFound, "Collection not found", "")
+		return
+	}
+	slog.Error("Failed to read TAXII collection", "error", err, "collection", id)
+	respondTAXIIError(w, http.StatusInternalServerError, "Internal server error", "")
+}
+
+func taxiiCollectionID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		respondTAXIIError(w, http.StatusNotFound, "Collection not found", "")
+		return "", false
+	}
+	return id, true
+}
+
+func parseTaxiiObjectParams(r *http.Request) (model.TaxiiObjectParams, error) {
+	query := r.URL.Query()
+	params := model.TaxiiObjectParams{Limit: taxii.DefaultPageSize}
+
+	if addedAfter := query.Get("added_after"); addedAfter != "" {
+		t, err := time.Parse(time.RFC3339Nano, addedAfter)
+		if err != nil {
+			return params, errors.New("added_after must be an RFC 3339 timestamp")
+		}
+		params.AddedAfter = &t
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return params, errors.New("limit must be a positive integer")
+		}
+		params.Limit = min(n, taxii.MaxPageSize)
+	}
+
+	if next := query.Get("next"); next != "" {
+		dateAdded, id, err := taxii.DecodeCursor(next)
+		if err != nil {
+			return params, err
+		}
+		params.AfterDate = &dateAdded
+		params.AfterID = id
+	}
+
+	return params, nil
+}
+
+func acceptsTAXII(w http.ResponseWriter, r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return true
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "*/*", "application/*":
+			return true
+		case "application/taxii+json":
+			if version, ok := params["version"]; !ok || version == "2.1" {
+				return true
+			}
+		}
+	}
+
+	respondTAXIIError(w, http.StatusNotAcceptable, "Not acceptable", "Supported media type: "+taxii.MediaType)
+	return false
+}
+
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
+
+func respondTAXII(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", taxii.MediaType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func respondTAXIIError(w http.ResponseWriter, status int, title, description string) {
+	respondTAXII(w, status, taxii.Error{
+		Title:       title,
+		Description: description,
+		HTTPStatus:  strconv.Itoa(status),
+	})
+}
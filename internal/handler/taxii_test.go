@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/taxii"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupTaxiiRouter(handler *TaxiiHandler) *chi.Mux {
+	r := chi.NewRouter()
+	r.Get("/taxii2/", handler.Discovery)
+	r.Get("/taxii2/api/", handler.APIRoot)
+	r.Get("/taxii2/api/collections/", handler.Collections)
+	r.Get("/taxii2/api/collections/{id}/", handler.Collection)
+	r.Get("/taxii2/api/collections/{id}/objects/", handler.Objects)
+	return r
+}
+
+func TestTaxiiHandler_Discovery(t *testing.T) {
+	r := setupTaxiiRouter(NewTaxiiHandler(new(MockTaxiiService)))
+
+	req := httptest.NewRequest("GET", "http://intel.example.com/taxii2/", nil)
+	req.Header.Set("Accept", taxii.MediaType)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, taxii.MediaType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"api_roots":["http://intel.example.com/taxii2/api/"]`)
+}
+
+func TestTaxiiHandler_NotAcceptable(t *testing.T) {
+	r := setupTaxiiRouter(NewTaxiiHandler(new(MockTaxiiService)))
+
+	req := httptest.NewRequest("GET", "/taxii2/api/", nil)
+	req.Header.Set("Accept", "application/taxii+json;version=2.0")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.Contains(t, w.Body.String(), `"http_status":"406"`)
+}
+
+func TestTaxiiHandler_Objects_Success(t *testing.T) {
+	mockService := new(MockTaxiiService)
+	r := setupTaxiiRouter(NewTaxiiHandler(mockService))
+
+	addedAfter := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockService.On("Objects", mock.Anything, taxii.IndicatorsCollectionID, model.TaxiiObjectParams{
+		AddedAfter: &addedAfter,
+		Limit:      50,
+	}).Return(&taxii.Envelope{More: true, Next: "abc", DateAddedFirst: first, DateAddedLast: first}, nil)
+
+	req := httptest.NewRequest("GET", "/taxii2/api/collections/"+taxii.IndicatorsCollectionID+"/objects/?added_after=2024-01-01T00:00:00Z&limit=50", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, taxii.MediaType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "2024-01-02T00:00:00.000000Z", w.Header().Get("X-TAXII-Date-Added-First"))
+	assert.Contains(t, w.Body.String(), `"more":true`)
+	assert.Contains(t, w.Body.String(), `"next":"abc"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestTaxiiHandler_Objects_InvalidNext(t *testing.T) {
+	r := setupTaxiiRouter(NewTaxiiHandler(new(MockTaxiiService)))
+
+	req := httptest.NewRequest("GET", "/taxii2/api/collections/"+taxii.IndicatorsCollectionID+"/objects/?next=bogus", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTaxiiHandler_Collection_NotFound(t *testing.T) {
+	mockService := new(MockTaxiiService)
+	r := setupTaxiiRouter(NewTaxiiHandler(mockService))
+
+	mockService.On("Collection", mock.Anything, "550e8400-e29b-41d4-a716-446655440000").Return(nil, repository.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/taxii2/api/collections/550e8400-e29b-41d4-a716-446655440000/", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, taxii.MediaType, w.Header().Get("Content-Type"))
+}
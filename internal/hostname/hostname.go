@@ -0,0 +1,73 @@
+package hostname
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"golang.org/x/net/publicsuffix"
+)
+
+type Parts struct {
+	Host             string
+	RegisteredDomain string
+	TLD              string
+}
+
+func Parse(indicatorType model.IndicatorType, value string) Parts {
+	switch indicatorType {
+	case model.IndicatorTypeDomain:
+		return FromHost(Normalize(value))
+	case model.IndicatorTypeURL:
+		return FromHost(urlHost(value))
+	}
+	return Parts{}
+}
+
+// Normalize lowercases a domain name and strips a trailing dot and a leading
+// wildcard label, returning "" for anything that is not a valid host name.
+func Normalize(value string) string {
+	domain := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), ".")
+	domain = strings.TrimPrefix(domain, "*.")
+	if domain == "" || len(domain) > 253 || net.ParseIP(domain) != nil {
+		return ""
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 {
+			return ""
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return ""
+			}
+		}
+	}
+	return domain
+}
+
+// FromHost splits an already normalized host name. The registered domain is
+// left empty when the host is itself a public suffix, such as "co.uk".
+func FromHost(host string) Parts {
+	if host == "" {
+		return Parts{}
+	}
+
+	parts := Parts{Host: host, TLD: host[strings.LastIndex(host, ".")+1:]}
+	if registered, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		parts.RegisteredDomain = registered
+	}
+	return parts
+}
+
+func urlHost(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return Normalize(u.Hostname())
+}
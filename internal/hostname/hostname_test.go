@@ -0,0 +1,34 @@
+package hostname
+
+import (
+	"testing"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		indicatorType model.IndicatorType
+		value         string
+		want          Parts
+	}{
+		{"domain", model.IndicatorTypeDomain, "Login.Evil.Example.COM.", Parts{"login.evil.example.com", "example.com", "com"}},
+		{"multi-label suffix", model.IndicatorTypeDomain, "mail.example.co.uk", Parts{"mail.example.co.uk", "example.co.uk", "uk"}},
+		{"private suffix", model.IndicatorTypeDomain, "phish.github.io", Parts{"phish.github.io", "phish.github.io", "io"}},
+		{"wildcard domain", model.IndicatorTypeDomain, "*.bad.ru", Parts{"bad.ru", "bad.ru", "ru"}},
+		{"public suffix only", model.IndicatorTypeDomain, "co.uk", Parts{"co.uk", "", "uk"}},
+		{"url", model.IndicatorTypeURL, "https://User@CDN.Example.org:8443/path?q=1", Parts{"cdn.example.org", "example.org", "org"}},
+		{"url without scheme", model.IndicatorTypeURL, "evil.example.net/payload.exe", Parts{"evil.example.net", "example.net", "net"}},
+		{"url with ip host", model.IndicatorTypeURL, "http://10.0.0.1/x", Parts{}},
+		{"invalid domain", model.IndicatorTypeDomain, "not a domain", Parts{}},
+		{"ip indicator", model.IndicatorTypeIP, "10.0.0.1", Parts{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.indicatorType, tt.value))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package marking
+
+import (
+	"context"
+	"strings"
+)
+
+type Level string
+
+const (
+	Clear Level = "clear"
+	Green Level = "green"
+	Amber Level = "amber"
+	Red   Level = "red"
+)
+
+var levels = []Level{Clear, Green, Amber, Red}
+
+type contextKey struct{}
+
+func Levels() []Level {
+	return append([]Level(nil), levels...)
+}
+
+func Parse(value string) (Level, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "tlp:"), "pap:")
+	if value == "white" {
+		return Clear, true
+	}
+
+	level := Level(value)
+	return level, level.IsValid()
+}
+
+func (l Level) IsValid() bool {
+	return l.rank() >= 0
+}
+
+func (l Level) rank() int {
+	for i, level := range levels {
+		if level == l {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l Level) Allows(other Level) bool {
+	return other.rank() >= 0 && other.rank() <= l.rank()
+}
+
+func (l Level) TLP() string {
+	return "TLP:" + strings.ToUpper(string(l))
+}
+
+func (l Level) PAP() string {
+	return "PAP:" + strings.ToUpper(string(l))
+}
+
+func Max(a, b Level) Level {
+	if b.rank() > a.rank() {
+		return b
+	}
+	return a
+}
+
+func Within(clearance Level) []string {
+	allowed := make([]string, 0, len(levels))
+	for _, level := range levels {
+		if clearance.Allows(level) {
+			allowed = append(allowed, string(level))
+		}
+	}
+	return allowed
+}
+
+func WithClearance(ctx context.Context, clearance Level) context.Context {
+	return context.WithValue(ctx, contextKey{}, clearance)
+}
+
+func ClearanceFromContext(ctx context.Context) Level {
+	clearance, ok := ctx.Value(contextKey{}).(Level)
+	if !ok {
+		return Red
+	}
+	if !clearance.IsValid() {
+		return Clear
+	}
+	return clearance
+}
@@ -0,0 +1,41 @@
+package marking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Level{
+		"amber":      Amber,
+		"TLP:RED":    Red,
+		" tlp:green": Green,
+		"TLP:WHITE":  Clear,
+		"PAP:CLEAR":  Clear,
+	}
+	for input, want := range tests {
+		level, ok := Parse(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, want, level, input)
+	}
+
+	for _, input := range []string{"", "TLP:AMBER+STRICT", "purple", "tlp"} {
+		_, ok := Parse(input)
+		assert.False(t, ok, input)
+	}
+}
+
+func TestWithin(t *testing.T) {
+	assert.Equal(t, []string{"clear"}, Within(Clear))
+	assert.Equal(t, []string{"clear", "green", "amber"}, Within(Amber))
+	assert.Equal(t, []string{"clear", "green", "amber", "red"}, Within(Red))
+	assert.Empty(t, Within(Level("bogus")))
+}
+
+func TestClearanceFromContext(t *testing.T) {
+	assert.Equal(t, Red, ClearanceFromContext(context.Background()))
+	assert.Equal(t, Green, ClearanceFromContext(WithClearance(context.Background(), Green)))
+	assert.Equal(t, Clear, ClearanceFromContext(WithClearance(context.Background(), "")))
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+)
+
+func Authenticate(authenticator auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := auth.BearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				unauthorized(w, "Missing bearer token")
+				return
+			}
+
+			principal, err := authenticator.Authenticate(r.Context(), token)
+			if err != nil {
+				if !errors.Is(err, auth.ErrInvalidCredentials) {
+					slog.Error("Failed to authenticate request", "error", err, "path", r.URL.Path)
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte(`{"success":false,"error":{"code":"INTERNAL_ERROR","message":"Internal server error"}}`))
+					return
+				}
+				slog.Debug("Rejected credentials", "error", err, "path", r.URL.Path)
+				unauthorized(w, "Invalid or expired credentials")
+				return
+			}
+
+			setLoggedPrincipal(r.Context(), principal)
+			ctx := tenant.WithID(r.Context(), principal.TenantID)
+			ctx = marking.WithClearance(ctx, principal.Clearance)
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(ctx, principal)))
+		})
+	}
+}
+
+func RequireScope(scope auth.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.PrincipalFromContext(r.Context())
+			if !ok {
+				unauthorized(w, "Missing bearer token")
+				return
+			}
+			if !principal.HasScope(scope) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"success":false,"error":{"code":"FORBIDDEN","message":"Missing required scope: ` + string(scope) + `"}}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", `Bearer realm="threat-intel-api"`)
+	w.WriteHeader(http.StatusUnauthorized)
+	w.Write([]byte(`{"success":false,"error":{"code":"UNAUTHORIZED","message":"` + message + `"}}`))
+}
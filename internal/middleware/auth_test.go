@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAuthenticator map[string]*auth.Principal
+
+func (s stubAuthenticator) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	if token == "broken" {
+		return nil, errors.New("database unavailable")
+	}
+	if p, ok := s[token]; ok {
+		return p, nil
+	}
+	return nil, auth.ErrInvalidCredentials
+}
+
+var testAuthenticator = stubAuthenticator{
+	"reader-key": {ID: "key-1", Type: auth.PrincipalAPIKey, TenantID: "emea", Scopes: []auth.Scope{auth.ScopeRead}, Clearance: marking.Amber},
+	"admin-key":  {ID: "key-2", Type: auth.PrincipalAPIKey, Scopes: []auth.Scope{auth.ScopeAdmin}},
+}
+
+func TestAuthenticate(t *testing.T) {
+	var seen *auth.Principal
+	var seenTenant string
+	var seenClearance marking.Level
+	handler := Authenticate(testAuthenticator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = auth.PrincipalFromContext(r.Context())
+		seenTenant = tenant.FromContext(r.Context())
+		seenClearance = marking.ClearanceFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := map[string]struct {
+		header string
+		status int
+	}{
+		"valid key":      {"Bearer reader-key", http.StatusOK},
+		"missing header": {"", http.StatusUnauthorized},
+		"unknown key":    {"Bearer nope", http.StatusUnauthorized},
+		"wrong scheme":   {"Basic reader-key", http.StatusUnauthorized},
+		"backend error":  {"Bearer broken", http.StatusInternalServerError},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest("GET", "/api/test", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				require.NotNil(t, seen)
+				assert.Equal(t, "key-1", seen.ID)
+				assert.Equal(t, "emea", seenTenant)
+				assert.Equal(t, marking.Amber, seenClearance)
+			} else {
+				assert.Nil(t, seen)
+			}
+			if tt.status == http.StatusUnauthorized {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+				assert.Contains(t, w.Body.String(), "UNAUTHORIZED")
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	handler := Authenticate(testAuthenticator)(RequireScope(auth.ScopeWrite)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	tests := map[string]int{
+		"reader-key": http.StatusForbidden,
+		"admin-key":  http.StatusNoContent,
+	}
+
+	for token, status := range tests {
+		t.Run(token, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/test", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code)
+		})
+	}
+}
+
+func TestRequireScope_WithoutPrincipal(t *testing.T) {
+	handler := RequireScope(auth.ScopeRead)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/test", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLogger_IncludesPrincipal(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := Logger(logger)(Authenticate(testAuthenticator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	req := httptest.NewRequest("GET", "/api/test", nil)
+	req.Header.Set("Authorization", "Bearer reader-key")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, buf.String(), "principal=api_key:key-1")
+}
@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
 )
 
 type responseWriter struct {
@@ -16,6 +19,22 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+type logEntryKey struct{}
+
+type logEntry struct {
+	principal *auth.Principal
+}
+
+func setLoggedPrincipal(ctx context.Context, principal *auth.Principal) {
+	if entry, ok := ctx.Value(logEntryKey{}).(*logEntry); ok {
+		entry.principal = principal
+	}
+}
+
 func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -26,15 +45,20 @@ func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
 				status:         http.StatusOK,
 			}
 
-			next.ServeHTTP(wrapped, r)
+			entry := &logEntry{}
+			next.ServeHTTP(wrapped, r.WithContext(context.WithValue(r.Context(), logEntryKey{}, entry)))
 
-			logger.Info("HTTP request",
+			attrs := []interface{}{
 				"method", r.Method,
 				"path", r.URL.Path,
 				"status", wrapped.status,
 				"duration", time.Since(start).String(),
 				"ip", r.RemoteAddr,
-			)
+			}
+			if entry.principal != nil {
+				attrs = append(attrs, "principal", entry.principal.String(), "tenant", entry.principal.TenantID)
+			}
+			logger.Info("HTTP request", attrs...)
 		})
 	}
 }
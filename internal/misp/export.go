@@ -0,0 +1,126 @@
+package misp
+
+import (
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+var analysisByStatus = map[string]string{
+	"active":     AnalysisOngoing,
+	"inactive":   AnalysisCompleted,
+	"historical": AnalysisCompleted,
+}
+
+func BuildEvent(timeline *model.CampaignWithTimeline) *EventWrapper {
+	campaign := timeline.Campaign
+
+	event := Event{
+		UUID:          campaign.ID,
+		Info:          campaign.Name,
+		ThreatLevelID: ThreatLevelUndefined,
+		Analysis:      FlexString(analysisByStatus[campaign.Status]),
+		Distribution:  DistributionOrganisation,
+		Attribute:     []Attribute{},
+		Tag:           tlpTags(campaign.TLP),
+	}
+	if event.Analysis == "" {
+		event.Analysis = AnalysisInitial
+	}
+
+	date := time.Now().UTC()
+	if campaign.FirstSeen != nil {
+		date = *campaign.FirstSeen
+	} else if len(timeline.Timeline) > 0 {
+		if first, err := time.Parse("2006-01-02", timeline.Timeline[len(timeline.Timeline)-1].Period); err == nil {
+			date = first
+		}
+	}
+	event.Date = date.Format("2006-01-02")
+
+	seen := make(map[string]bool)
+	for i := len(timeline.Timeline) - 1; i >= 0; i-- {
+		for _, ind := range timeline.Timeline[i].Indicators {
+			if seen[ind.ID] {
+				continue
+			}
+			seen[ind.ID] = true
+
+			attr, ok := FromIndicator(ind)
+			if !ok {
+				continue
+			}
+			event.Attribute = append(event.Attribute, attr)
+		}
+	}
+
+	return &EventWrapper{Event: event}
+}
+
+func FromIndicator(ind model.TimelineIndicator) (Attribute, bool) {
+	attr := Attribute{
+		UUID:  ind.ID,
+		Value: ind.Value,
+		ToIDs: true,
+		Tag:   tlpTags(ind.TLP),
+	}
+
+	switch ind.Type {
+	case model.IndicatorTypeIP:
+		attr.Type = "ip-dst"
+		attr.Category = "Network activity"
+	case model.IndicatorTypeDomain:
+		attr.Type = "domain"
+		attr.Category = "Network activity"
+	case model.IndicatorTypeURL:
+		attr.Type = "url"
+		attr.Category = "Network activity"
+	case model.IndicatorTypeHash:
+		hashAlgo := ind.HashAlgo
+		if hashAlgo == "" {
+			hashAlgo = model.DetectHashAlgo(ind.Value)
+		}
+		if hashAlgo == "" {
+			return attr, false
+		}
+		attr.Type = hashAlgo
+		attr.Category = "Payload delivery"
+	case model.IndicatorTypeEmail:
+		attr.Type = "email-src"
+		attr.Category = "Payload delivery"
+	case model.IndicatorTypeASN:
+		attr.Type = "AS"
+		attr.Category = "Network activity"
+	case model.IndicatorTypeCVE:
+		attr.Type = "vulnerability"
+		attr.Category = "External analysis"
+	case model.IndicatorTypeJA3:
+		attr.Type = "ja3-fingerprint-md5"
+		attr.Category = "Network activity"
+	case model.IndicatorTypeFilePath:
+		attr.Type = "filename"
+		attr.Category = "Artifacts dropped"
+	case model.IndicatorTypeMutex:
+		attr.Type = "mutex"
+		attr.Category = "Artifacts dropped"
+	case model.IndicatorTypeRegistryKey:
+		attr.Type = "regkey"
+		attr.Category = "Persistence mechanism"
+	case model.IndicatorTypeUserAgent:
+		attr.Type = "user-agent"
+		attr.Category = "Network activity"
+	default:
+		return attr, false
+	}
+
+	return attr, true
+}
+
+func tlpTags(tlp string) []Tag {
+	level, ok := marking.Parse(tlp)
+	if !ok {
+		return nil
+	}
+	return []Tag{{Name: "tlp:" + string(level)}}
+}
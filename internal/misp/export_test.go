@@ -0,0 +1,70 @@
+package misp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEvent(t *testing.T) {
+	firstSeen := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	timeline := &model.CampaignWithTimeline{
+		Campaign: model.CampaignDetail{
+			ID:        "550e8400-e29b-41d4-a716-446655440000",
+			Name:      "Operation Test",
+			Status:    "active",
+			FirstSeen: &firstSeen,
+		},
+		Timeline: []model.TimelinePeriod{
+			{Period: "2024-01-16", Indicators: []model.TimelineIndicator{
+				{ID: "ind-2", Type: model.IndicatorTypeHash, Value: "d41d8cd98f00b204e9800998ecf8427e"},
+				{ID: "ind-3", Type: model.IndicatorTypeHash, Value: "abc"},
+				{ID: "ind-4", Type: model.IndicatorTypeHash, Value: "f34d5f2d4577ed6d9ceec516c1f5a744", HashAlgo: model.HashAlgoImphash},
+			}},
+			{Period: "2024-01-15", Indicators: []model.TimelineIndicator{
+				{ID: "ind-1", Type: model.IndicatorTypeIP, Value: "10.0.0.1"},
+			}},
+		},
+	}
+
+	event := BuildEvent(timeline).Event
+
+	assert.Equal(t, timeline.Campaign.ID, event.UUID)
+	assert.Equal(t, "Operation Test", event.Info)
+	assert.Equal(t, "2024-01-15", event.Date)
+	assert.Equal(t, FlexString(AnalysisOngoing), event.Analysis)
+	require.Len(t, event.Attribute, 3)
+	assert.Equal(t, "ip-dst", event.Attribute[0].Type)
+	assert.Equal(t, "10.0.0.1", event.Attribute[0].Value)
+	assert.Equal(t, "md5", event.Attribute[1].Type)
+	assert.Equal(t, "Payload delivery", event.Attribute[1].Category)
+	assert.Equal(t, "imphash", event.Attribute[2].Type)
+}
+
+func TestBuildEvent_RoundTrip(t *testing.T) {
+	timeline := &model.CampaignWithTimeline{
+		Campaign: model.CampaignDetail{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Operation Test", TLP: "amber"},
+		Timeline: []model.TimelinePeriod{
+			{Period: "2024-01-15", Indicators: []model.TimelineIndicator{
+				{ID: "a1a1a1a1-0000-4000-8000-000000000001", Type: model.IndicatorTypeDomain, Value: "evil.example.com", TLP: "green"},
+			}},
+		},
+	}
+
+	payload, err := json.Marshal(BuildEvent(timeline))
+	require.NoError(t, err)
+
+	data, rejected, err := ParseEvents(payload)
+
+	require.NoError(t, err)
+	assert.Empty(t, rejected)
+	require.Len(t, data.Indicators, 1)
+	assert.Equal(t, model.IndicatorTypeDomain, data.Indicators[0].Indicator.Type)
+	assert.Equal(t, "evil.example.com", data.Indicators[0].Indicator.Value)
+	assert.Equal(t, "amber", data.Campaigns[0].Campaign.TLP)
+	assert.Equal(t, "amber", data.Indicators[0].Indicator.TLP)
+}
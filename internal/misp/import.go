@@ -0,0 +1,310 @@
+package misp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+var attributeTypes = map[string]model.IndicatorType{
+	"ip-src":              model.IndicatorTypeIP,
+	"ip-dst":              model.IndicatorTypeIP,
+	"ip-src|port":         model.IndicatorTypeIP,
+	"ip-dst|port":         model.IndicatorTypeIP,
+	"domain":              model.IndicatorTypeDomain,
+	"hostname":            model.IndicatorTypeDomain,
+	"url":                 model.IndicatorTypeURL,
+	"md5":                 model.IndicatorTypeHash,
+	"sha1":                model.IndicatorTypeHash,
+	"sha256":              model.IndicatorTypeHash,
+	"sha512":              model.IndicatorTypeHash,
+	"ssdeep":              model.IndicatorTypeHash,
+	"tlsh":                model.IndicatorTypeHash,
+	"imphash":             model.IndicatorTypeHash,
+	"filename|md5":        model.IndicatorTypeHash,
+	"filename|sha1":       model.IndicatorTypeHash,
+	"filename|sha256":     model.IndicatorTypeHash,
+	"filename|sha512":     model.IndicatorTypeHash,
+	"filename|ssdeep":     model.IndicatorTypeHash,
+	"filename|tlsh":       model.IndicatorTypeHash,
+	"filename|imphash":    model.IndicatorTypeHash,
+	"email":               model.IndicatorTypeEmail,
+	"email-src":           model.IndicatorTypeEmail,
+	"email-dst":           model.IndicatorTypeEmail,
+	"AS":                  model.IndicatorTypeASN,
+	"vulnerability":       model.IndicatorTypeCVE,
+	"ja3-fingerprint-md5": model.IndicatorTypeJA3,
+	"filename":            model.IndicatorTypeFilePath,
+	"mutex":               model.IndicatorTypeMutex,
+	"regkey":              model.IndicatorTypeRegistryKey,
+	"regkey|value":        model.IndicatorTypeRegistryKey,
+	"user-agent":          model.IndicatorTypeUserAgent,
+}
+
+var threatActorGalaxies = map[string]bool{
+	"threat-actor":        true,
+	"mitre-intrusion-set": true,
+}
+
+var severities = map[string]string{
+	ThreatLevelHigh:      "high",
+	ThreatLevelMedium:    "medium",
+	ThreatLevelLow:       "low",
+	ThreatLevelUndefined: "medium",
+}
+
+type eventsPayload struct {
+	Event    *Event         `json:"Event"`
+	Response []EventWrapper `json:"response"`
+}
+
+func ParseEvents(data []byte) (*model.IntelImport, []model.ImportError, error) {
+	events, err := decodeEvents(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil, errors.New("payload contains no events")
+	}
+
+	result := &model.IntelImport{RefKey: MetadataKey}
+	var rejected []model.ImportError
+	for _, event := range events {
+		rejected = append(rejected, parseEvent(result, event)...)
+	}
+
+	return result, rejected, nil
+}
+
+func decodeEvents(data []byte) ([]Event, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var wrappers []EventWrapper
+		if err := json.Unmarshal(data, &wrappers); err != nil {
+			return nil, fmt.Errorf("invalid event list: %w", err)
+		}
+		return unwrapEvents(wrappers), nil
+	}
+
+	var payload eventsPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	if payload.Event != nil {
+		return []Event{*payload.Event}, nil
+	}
+	return unwrapEvents(payload.Response), nil
+}
+
+func unwrapEvents(wrappers []EventWrapper) []Event {
+	events := make([]Event, 0, len(wrappers))
+	for _, wrapper := range wrappers {
+		events = append(events, wrapper.Event)
+	}
+	return events
+}
+
+func parseEvent(result *model.IntelImport, event Event) []model.ImportError {
+	if event.UUID == "" {
+		return []model.ImportError{{Ref: event.Info, Type: "event", Reason: "event uuid is required"}}
+	}
+
+	severity := severities[string(event.ThreatLevelID)]
+	campaign := model.Campaign{
+		Name:     event.Info,
+		Severity: severity,
+		Metadata: referenceMetadata(map[string]string{MetadataKey: event.UUID}),
+		TLP:      tlpFromTags(event.Tag, ""),
+	}
+	if date, err := time.Parse("2006-01-02", event.Date); err == nil {
+		campaign.StartDate = &date
+	}
+	result.Campaigns = append(result.Campaigns, model.ImportCampaign{Ref: event.UUID, Campaign: campaign})
+
+	var actorRefs []string
+	for _, galaxy := range event.Galaxy {
+		if !threatActorGalaxies[galaxy.Type] {
+			continue
+		}
+		for _, cluster := range galaxy.GalaxyCluster {
+			ref := cluster.UUID
+			if ref == "" {
+				ref = cluster.Value
+			}
+			result.ThreatActors = append(result.ThreatActors, model.ImportThreatActor{
+				Ref:         ref,
+				ThreatActor: clusterToThreatActor(cluster),
+			})
+			actorRefs = append(actorRefs, ref)
+		}
+	}
+	if len(actorRefs) > 0 {
+		result.Relationships = append(result.Relationships, model.ImportRelationship{
+			Ref:        event.UUID + "/attributed-to/" + actorRefs[0],
+			SourceType: model.EntityCampaign,
+			SourceRef:  event.UUID,
+			TargetType: model.EntityThreatActor,
+			TargetRef:  actorRefs[0],
+		})
+	}
+
+	attributes := event.Attribute
+	for _, object := range event.Object {
+		attributes = append(attributes, object.Attribute...)
+	}
+
+	var rejected []model.ImportError
+	for i, attr := range attributes {
+		ref := attr.UUID
+		if ref == "" {
+			ref = fmt.Sprintf("%s/attribute/%d", event.UUID, i)
+		}
+
+		ind, err := attributeToIndicator(attr, event.UUID, severity, campaign.TLP)
+		if err != nil {
+			rejected = append(rejected, model.ImportError{Ref: ref, Type: "attribute", Reason: err.Error()})
+			continue
+		}
+		result.Indicators = append(result.Indicators, model.ImportIndicator{Ref: ref, Indicator: *ind})
+
+		result.Relationships = append(result.Relationships, model.ImportRelationship{
+			Ref:        ref + "/indicates/" + event.UUID,
+			SourceType: model.EntityIndicator,
+			SourceRef:  ref,
+			TargetType: model.EntityCampaign,
+			TargetRef:  event.UUID,
+		})
+		for _, actorRef := range actorRefs {
+			result.Relationships = append(result.Relationships, model.ImportRelationship{
+				Ref:        ref + "/indicates/" + actorRef,
+				SourceType: model.EntityIndicator,
+				SourceRef:  ref,
+				TargetType: model.EntityThreatActor,
+				TargetRef:  actorRef,
+			})
+		}
+	}
+
+	return rejected
+}
+
+func attributeToIndicator(attr Attribute, eventUUID, severity, eventTLP string) (*model.Indicator, error) {
+	indicatorType, ok := attributeTypes[attr.Type]
+	if !ok {
+		return nil, fmt.Errorf("unsupported attribute type %q", attr.Type)
+	}
+
+	value, hashAlgo := attr.Value, ""
+	if strings.HasPrefix(attr.Type, "filename|") {
+		_, value, _ = strings.Cut(value, "|")
+	} else if strings.Contains(attr.Type, "|") {
+		value, _, _ = strings.Cut(value, "|")
+	}
+	if indicatorType == model.IndicatorTypeHash {
+		hashAlgo = strings.TrimPrefix(attr.Type, "filename|")
+		if hashAlgo != model.HashAlgoSSDEEP {
+			value = strings.ToLower(value)
+		}
+	}
+
+	metadata := map[string]string{MetadataKey: attr.UUID, "misp_event_uuid": eventUUID}
+	if attr.UUID == "" {
+		delete(metadata, MetadataKey)
+	}
+	if attr.Category != "" {
+		metadata["misp_category"] = attr.Category
+	}
+
+	ind := &model.Indicator{
+		Type:        indicatorType,
+		Value:       strings.TrimSpace(value),
+		HashAlgo:    hashAlgo,
+		Description: attr.Comment,
+		Severity:    severity,
+		Confidence:  50,
+		IsActive:    attr.ToIDs,
+		Metadata:    referenceMetadata(metadata),
+		Source:      ImportSource,
+		TLP:         tlpFromTags(attr.Tag, eventTLP),
+	}
+	if ind.Severity == "" {
+		ind.Severity = "medium"
+	}
+	for _, tag := range attr.Tag {
+		ind.Tags = append(ind.Tags, tag.Name)
+	}
+
+	if seen, ok := parseUnixTimestamp(string(attr.Timestamp)); ok {
+		ind.FirstSeen = &seen
+		ind.LastSeen = &seen
+	}
+	if firstSeen, err := time.Parse(time.RFC3339Nano, attr.FirstSeen); err == nil {
+		ind.FirstSeen = &firstSeen
+	}
+	if lastSeen, err := time.Parse(time.RFC3339Nano, attr.LastSeen); err == nil {
+		ind.LastSeen = &lastSeen
+	}
+	if ind.FirstSeen != nil && ind.LastSeen != nil && ind.LastSeen.Before(*ind.FirstSeen) {
+		ind.LastSeen = ind.FirstSeen
+	}
+
+	return ind, nil
+}
+
+func tlpFromTags(tags []Tag, inherited string) string {
+	tlp := marking.Level(inherited)
+	for _, tag := range tags {
+		name := strings.ToLower(tag.Name)
+		if !strings.HasPrefix(name, "tlp:") {
+			continue
+		}
+		if level, ok := marking.Parse(strings.TrimSuffix(name, "+strict")); ok {
+			tlp = marking.Max(tlp, level)
+		}
+	}
+	return string(tlp)
+}
+
+func clusterToThreatActor(cluster GalaxyCluster) model.ThreatActor {
+	metadata := map[string]string{}
+	if cluster.UUID != "" {
+		metadata[MetadataKey] = cluster.UUID
+	}
+
+	actor := model.ThreatActor{
+		Name:            cluster.Value,
+		Description:     cluster.Description,
+		ConfidenceLevel: 50,
+		Metadata:        referenceMetadata(metadata),
+	}
+	if countries := cluster.MetaValues("country"); len(countries) > 0 {
+		actor.Country = countries[0]
+	}
+	for _, synonym := range cluster.MetaValues("synonyms") {
+		if synonym != "" && synonym != cluster.Value {
+			actor.Aliases = append(actor.Aliases, synonym)
+		}
+	}
+
+	return actor
+}
+
+func parseUnixTimestamp(value string) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0).UTC(), true
+}
+
+func referenceMetadata(values map[string]string) json.RawMessage {
+	metadata, _ := json.Marshal(values)
+	return metadata
+}
@@ -0,0 +1,139 @@
+package misp
+
+import (
+	"testing"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleEvent = `{
+	"Event": {
+		"uuid": "5e5d0b55-9d3c-4c5a-8d2f-4a1c2b3d4e5f",
+		"info": "Phishing wave targeting banks",
+		"date": "2024-02-10",
+		"threat_level_id": "1",
+		"Tag": [{"name": "tlp:clear"}],
+		"Attribute": [
+			{"uuid": "a1a1a1a1-0000-4000-8000-000000000001", "type": "ip-dst", "category": "Network activity", "value": "203.0.113.7", "to_ids": true, "timestamp": "1707523200", "comment": "C2"},
+			{"uuid": "a1a1a1a1-0000-4000-8000-000000000002", "type": "hostname", "value": "login.evil.example", "to_ids": true, "Tag": [{"name": "tlp:green"}]},
+			{"uuid": "a1a1a1a1-0000-4000-8000-000000000003", "type": "btc", "value": "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", "to_ids": false},
+			{"uuid": "a1a1a1a1-0000-4000-8000-000000000004", "type": "ip-dst|port", "value": "198.51.100.9|443", "to_ids": false}
+		],
+		"Object": [
+			{"name": "file", "Attribute": [
+				{"uuid": "a1a1a1a1-0000-4000-8000-000000000005", "type": "sha256", "value": "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855", "to_ids": true}
+			]}
+		],
+		"Galaxy": [
+			{"type": "threat-actor", "GalaxyCluster": [
+				{"uuid": "c1c1c1c1-0000-4000-8000-000000000001", "value": "APT28", "description": "Russian actor", "meta": {"country": ["RU"], "synonyms": ["Fancy Bear"]}}
+			]},
+			{"type": "mitre-attack-pattern", "GalaxyCluster": [{"value": "Phishing - T1566"}]}
+		]
+	}
+}`
+
+func TestParseEvents(t *testing.T) {
+	data, rejected, err := ParseEvents([]byte(sampleEvent))
+
+	require.NoError(t, err)
+	assert.Equal(t, MetadataKey, data.RefKey)
+
+	require.Len(t, data.Campaigns, 1)
+	campaign := data.Campaigns[0]
+	assert.Equal(t, "5e5d0b55-9d3c-4c5a-8d2f-4a1c2b3d4e5f", campaign.Ref)
+	assert.Equal(t, "Phishing wave targeting banks", campaign.Campaign.Name)
+	assert.Equal(t, "high", campaign.Campaign.Severity)
+	require.NotNil(t, campaign.Campaign.StartDate)
+	assert.Equal(t, "2024-02-10", campaign.Campaign.StartDate.Format("2006-01-02"))
+	assert.Equal(t, "clear", campaign.Campaign.TLP)
+
+	require.Len(t, data.ThreatActors, 1)
+	assert.Equal(t, "APT28", data.ThreatActors[0].ThreatActor.Name)
+	assert.Equal(t, "RU", data.ThreatActors[0].ThreatActor.Country)
+	assert.Equal(t, []string{"Fancy Bear"}, data.ThreatActors[0].ThreatActor.Aliases)
+
+	require.Len(t, data.Indicators, 4)
+	assert.Equal(t, model.IndicatorTypeIP, data.Indicators[0].Indicator.Type)
+	assert.Equal(t, "C2", data.Indicators[0].Indicator.Description)
+	assert.Equal(t, "high", data.Indicators[0].Indicator.Severity)
+	require.NotNil(t, data.Indicators[0].Indicator.LastSeen)
+	assert.Equal(t, int64(1707523200), data.Indicators[0].Indicator.LastSeen.Unix())
+	assert.Equal(t, model.IndicatorTypeDomain, data.Indicators[1].Indicator.Type)
+	assert.Equal(t, []string{"tlp:green"}, data.Indicators[1].Indicator.Tags)
+	assert.Equal(t, "clear", data.Indicators[0].Indicator.TLP)
+	assert.Equal(t, "green", data.Indicators[1].Indicator.TLP)
+	assert.Equal(t, "198.51.100.9", data.Indicators[2].Indicator.Value)
+	assert.False(t, data.Indicators[2].Indicator.IsActive)
+	assert.Equal(t, model.IndicatorTypeHash, data.Indicators[3].Indicator.Type)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", data.Indicators[3].Indicator.Value)
+	assert.JSONEq(t, `{"misp_uuid": "a1a1a1a1-0000-4000-8000-000000000001", "misp_event_uuid": "5e5d0b55-9d3c-4c5a-8d2f-4a1c2b3d4e5f", "misp_category": "Network activity"}`,
+		string(data.Indicators[0].Indicator.Metadata))
+
+	assert.Len(t, data.Relationships, 1+4*2)
+	assert.Equal(t, model.EntityCampaign, data.Relationships[0].SourceType)
+	assert.Equal(t, "c1c1c1c1-0000-4000-8000-000000000001", data.Relationships[0].TargetRef)
+
+	require.Len(t, rejected, 1)
+	assert.Equal(t, "a1a1a1a1-0000-4000-8000-000000000003", rejected[0].Ref)
+}
+
+func TestParseEvents_Formats(t *testing.T) {
+	payloads := []string{
+		`[{"Event": {"uuid": "5e5d0b55-9d3c-4c5a-8d2f-4a1c2b3d4e5f", "info": "a", "threat_level_id": 2}}]`,
+		`{"response": [{"Event": {"uuid": "5e5d0b55-9d3c-4c5a-8d2f-4a1c2b3d4e5f", "info": "a", "threat_level_id": 2}}]}`,
+	}
+
+	for _, payload := range payloads {
+		data, _, err := ParseEvents([]byte(payload))
+		require.NoError(t, err)
+		require.Len(t, data.Campaigns, 1)
+		assert.Equal(t, "medium", data.Campaigns[0].Campaign.Severity)
+	}
+}
+
+func TestParseEvents_Invalid(t *testing.T) {
+	_, _, err := ParseEvents([]byte(`{"foo": "bar"}`))
+	assert.Error(t, err)
+
+	_, rejected, err := ParseEvents([]byte(`{"Event": {"info": "no uuid"}}`))
+	require.NoError(t, err)
+	assert.Len(t, rejected, 1)
+}
+
+func TestParseEvents_ObservableTypes(t *testing.T) {
+	payload := `{"Event": {"uuid": "5e5d0b55-9d3c-4c5a-8d2f-4a1c2b3d4e5f", "info": "a", "Attribute": [
+		{"type": "regkey|value", "value": "HKLM\\Software\\Microsoft\\Windows\\CurrentVersion\\Run|evil.exe"},
+		{"type": "AS", "value": "AS13335"},
+		{"type": "user-agent", "value": "EvilBot/1.0"}
+	]}}`
+
+	data, rejected, err := ParseEvents([]byte(payload))
+
+	require.NoError(t, err)
+	assert.Empty(t, rejected)
+	require.Len(t, data.Indicators, 3)
+	assert.Equal(t, model.IndicatorTypeRegistryKey, data.Indicators[0].Indicator.Type)
+	assert.Equal(t, `HKLM\Software\Microsoft\Windows\CurrentVersion\Run`, data.Indicators[0].Indicator.Value)
+	assert.Equal(t, model.IndicatorTypeASN, data.Indicators[1].Indicator.Type)
+	assert.Equal(t, model.IndicatorTypeUserAgent, data.Indicators[2].Indicator.Type)
+}
+
+func TestParseEvents_HashAlgorithms(t *testing.T) {
+	payload := `{"Event": {"uuid": "5e5d0b55-9d3c-4c5a-8d2f-4a1c2b3d4e5f", "info": "a", "Attribute": [
+		{"type": "filename|imphash", "value": "evil.exe|F34D5F2D4577ED6D9CEEC516C1F5A744"},
+		{"type": "ssdeep", "value": "3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C"}
+	]}}`
+
+	data, rejected, err := ParseEvents([]byte(payload))
+
+	require.NoError(t, err)
+	assert.Empty(t, rejected)
+	require.Len(t, data.Indicators, 2)
+	assert.Equal(t, model.HashAlgoImphash, data.Indicators[0].Indicator.HashAlgo)
+	assert.Equal(t, "f34d5f2d4577ed6d9ceec516c1f5a744", data.Indicators[0].Indicator.Value)
+	assert.Equal(t, model.HashAlgoSSDEEP, data.Indicators[1].Indicator.HashAlgo)
+	assert.Equal(t, "3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C", data.Indicators[1].Indicator.Value)
+}
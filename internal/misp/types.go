@@ -0,0 +1,115 @@
+package misp
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	MetadataKey  = "misp_uuid"
+	ImportSource = "misp"
+
+	ThreatLevelHigh      = "1"
+	ThreatLevelMedium    = "2"
+	ThreatLevelLow       = "3"
+	ThreatLevelUndefined = "4"
+
+	AnalysisInitial   = "0"
+	AnalysisOngoing   = "1"
+	AnalysisCompleted = "2"
+
+	DistributionOrganisation = "0"
+)
+
+type FlexString string
+
+func (s *FlexString) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*s = FlexString(str)
+		return nil
+	}
+	if string(data) == "null" {
+		*s = ""
+		return nil
+	}
+	*s = FlexString(strings.TrimSpace(string(data)))
+	return nil
+}
+
+type EventWrapper struct {
+	Event Event `json:"Event"`
+}
+
+type Event struct {
+	UUID          string      `json:"uuid"`
+	Info          string      `json:"info"`
+	Date          string      `json:"date,omitempty"`
+	ThreatLevelID FlexString  `json:"threat_level_id,omitempty"`
+	Analysis      FlexString  `json:"analysis,omitempty"`
+	Distribution  FlexString  `json:"distribution,omitempty"`
+	Published     bool        `json:"published"`
+	Timestamp     FlexString  `json:"timestamp,omitempty"`
+	Attribute     []Attribute `json:"Attribute"`
+	Object        []Object    `json:"Object,omitempty"`
+	Tag           []Tag       `json:"Tag,omitempty"`
+	Galaxy        []Galaxy    `json:"Galaxy,omitempty"`
+}
+
+type Attribute struct {
+	UUID      string     `json:"uuid,omitempty"`
+	Type      string     `json:"type"`
+	Category  string     `json:"category,omitempty"`
+	Value     string     `json:"value"`
+	ToIDs     bool       `json:"to_ids"`
+	Comment   string     `json:"comment,omitempty"`
+	Timestamp FlexString `json:"timestamp,omitempty"`
+	FirstSeen string     `json:"first_seen,omitempty"`
+	LastSeen  string     `json:"last_seen,omitempty"`
+	Tag       []Tag      `json:"Tag,omitempty"`
+}
+
+type Object struct {
+	UUID      string      `json:"uuid,omitempty"`
+	Name      string      `json:"name"`
+	Attribute []Attribute `json:"Attribute"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+type Galaxy struct {
+	UUID          string          `json:"uuid,omitempty"`
+	Name          string          `json:"name,omitempty"`
+	Type          string          `json:"type"`
+	GalaxyCluster []GalaxyCluster `json:"GalaxyCluster"`
+}
+
+type GalaxyCluster struct {
+	UUID        string                     `json:"uuid,omitempty"`
+	Type        string                     `json:"type,omitempty"`
+	Value       string                     `json:"value"`
+	Description string                     `json:"description,omitempty"`
+	Meta        map[string]json.RawMessage `json:"meta,omitempty"`
+}
+
+func (c GalaxyCluster) MetaValues(key string) []string {
+	raw, ok := c.Meta[key]
+	if !ok {
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(raw, &values); err == nil {
+		return values
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil && value != "" {
+		return []string{value}
+	}
+	return nil
+}
@@ -1,21 +1,76 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type ThreatActor struct {
-	ID              string     `json:"id"`
-	Name            string     `json:"name"`
-	Description     string     `json:"description,omitempty"`
-	Country         string     `json:"country,omitempty"`
-	Motivation      string     `json:"motivation,omitempty"`
-	FirstSeen       *time.Time `json:"first_seen,omitempty"`
-	LastSeen        *time.Time `json:"last_seen,omitempty"`
-	ConfidenceLevel int        `json:"confidence_level"`
-	CreatedAt       time.Time  `json:"created_at"`
-	UpdatedAt       time.Time  `json:"updated_at"`
+	ID              string          `json:"id"`
+	Name            string          `json:"name"`
+	Description     string          `json:"description,omitempty"`
+	Country         string          `json:"country,omitempty"`
+	Motivation      string          `json:"motivation,omitempty"`
+	FirstSeen       *time.Time      `json:"first_seen,omitempty"`
+	LastSeen        *time.Time      `json:"last_seen,omitempty"`
+	ConfidenceLevel int             `json:"confidence_level"`
+	Aliases         []string        `json:"aliases,omitempty"`
+	Metadata        json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
 }
 
 type ThreatActorWithCount struct {
 	ThreatActor
 	IndicatorCount int `json:"indicator_count"`
 }
+
+type ThreatActorDetail struct {
+	ThreatActor
+	Campaigns       []CampaignSummary `json:"campaigns"`
+	IndicatorCounts map[string]int    `json:"indicator_counts"`
+	// MaxTLP is the most restrictive marking among the campaigns and
+	// indicators summarized, so reads of the detail can be audited.
+	MaxTLP string `json:"-"`
+}
+
+type ActorListParams struct {
+	Name          string `json:"name,omitempty"`
+	Country       string `json:"country,omitempty"`
+	Motivation    string `json:"motivation,omitempty"`
+	MinConfidence int    `json:"min_confidence,omitempty"`
+	Page          int    `json:"page"`
+	Limit         int    `json:"limit"`
+}
+
+type ActorListResult struct {
+	Data       []ThreatActorWithCount `json:"data"`
+	Total      int                    `json:"total"`
+	Page       int                    `json:"page"`
+	Limit      int                    `json:"limit"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+type ActorIndicatorParams struct {
+	Type  string `json:"type,omitempty"`
+	Page  int    `json:"page"`
+	Limit int    `json:"limit"`
+}
+
+type ActorIndicator struct {
+	Indicator
+	AttributionConfidence int       `json:"attribution_confidence"`
+	AddedAt               time.Time `json:"added_at"`
+}
+
+type ActorIndicatorResult struct {
+	Data       []ActorIndicator `json:"data"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	Limit      int              `json:"limit"`
+	TotalPages int              `json:"total_pages"`
+}
+
+type ActorAliasesInput struct {
+	Aliases []string `json:"aliases"`
+}
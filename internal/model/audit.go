@@ -0,0 +1,81 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionLink    = "link"
+	AuditActionUnlink  = "unlink"
+	AuditActionImport  = "import"
+	AuditActionExport  = "export"
+	AuditActionRead    = "read"
+	AuditActionRevoke  = "revoke"
+	AuditActionRestore = "restore"
+)
+
+const (
+	EntityAPIKey     = "api_key"
+	EntityBundle     = "bundle"
+	EntityCollection = "collection"
+)
+
+var auditActions = map[string]bool{
+	AuditActionCreate:  true,
+	AuditActionUpdate:  true,
+	AuditActionDelete:  true,
+	AuditActionLink:    true,
+	AuditActionUnlink:  true,
+	AuditActionImport:  true,
+	AuditActionExport:  true,
+	AuditActionRead:    true,
+	AuditActionRevoke:  true,
+	AuditActionRestore: true,
+}
+
+func IsValidAuditAction(action string) bool {
+	return auditActions[action]
+}
+
+type AuditEvent struct {
+	ID            int64           `json:"id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	PrincipalType string          `json:"principal_type"`
+	PrincipalID   string          `json:"principal_id"`
+	PrincipalName string          `json:"principal_name,omitempty"`
+	Action        string          `json:"action"`
+	EntityType    string          `json:"entity_type"`
+	EntityID      string          `json:"entity_id,omitempty"`
+	TLP           string          `json:"tlp"`
+	Redacted      bool            `json:"redacted,omitempty"`
+	Changes       json.RawMessage `json:"changes,omitempty"`
+	Params        json.RawMessage `json:"params,omitempty"`
+}
+
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditListParams struct {
+	PrincipalID string     `json:"principal,omitempty"`
+	EntityType  string     `json:"entity_type,omitempty"`
+	EntityID    string     `json:"entity_id,omitempty"`
+	Action      string     `json:"action,omitempty"`
+	Since       *time.Time `json:"since,omitempty"`
+	Until       *time.Time `json:"until,omitempty"`
+	Page        int        `json:"page"`
+	Limit       int        `json:"limit"`
+}
+
+type AuditListResult struct {
+	Data       []AuditEvent `json:"data"`
+	Total      int          `json:"total"`
+	Page       int          `json:"page"`
+	Limit      int          `json:"limit"`
+	TotalPages int          `json:"total_pages"`
+}
@@ -0,0 +1,42 @@
+package model
+
+type BulkIndicatorInput struct {
+	IndicatorInput
+	CampaignIDs    []string `json:"campaign_ids,omitempty"`
+	ThreatActorIDs []string `json:"threat_actor_ids,omitempty"`
+}
+
+type BulkIndicatorItem struct {
+	Line       int
+	Input      BulkIndicatorInput
+	ParseError string
+}
+
+type BulkIndicator struct {
+	Line           int
+	Indicator      Indicator
+	CampaignIDs    []string
+	ThreatActorIDs []string
+}
+
+type BulkError struct {
+	Line   int    `json:"line"`
+	Value  string `json:"value,omitempty"`
+	Reason string `json:"reason"`
+}
+
+type BulkResult struct {
+	Received int         `json:"received"`
+	Inserted int         `json:"inserted"`
+	Updated  int         `json:"updated"`
+	Rejected int         `json:"rejected"`
+	Errors   []BulkError `json:"errors"`
+	Affected AffectedIDs `json:"-"`
+}
+
+// AffectedIDs names the rows a batch write created or updated, so the audit
+// trail can point at each of them.
+type AffectedIDs struct {
+	Created []string `json:"created,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+type IntelBundle struct {
+	Indicators         []Indicator
+	Campaigns          []Campaign
+	ThreatActors       []ThreatActor
+	IndicatorCampaigns []IndicatorCampaignLink
+	IndicatorActors    []IndicatorActorLink
+}
+
+type IndicatorCampaignLink struct {
+	IndicatorID string    `json:"indicator_id"`
+	CampaignID  string    `json:"campaign_id"`
+	AddedAt     time.Time `json:"added_at"`
+	Notes       string    `json:"notes,omitempty"`
+}
+
+type IndicatorActorLink struct {
+	IndicatorID           string    `json:"indicator_id"`
+	ActorID               string    `json:"actor_id"`
+	AttributionConfidence int       `json:"attribution_confidence"`
+	AddedAt               time.Time `json:"added_at"`
+}
@@ -1,20 +1,74 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Campaign struct {
-	ID            string     `json:"id"`
-	Name          string     `json:"name"`
-	Description   string     `json:"description,omitempty"`
-	Status        string     `json:"status"`
-	StartDate     *time.Time `json:"start_date,omitempty"`
-	EndDate       *time.Time `json:"end_date,omitempty"`
-	TargetSectors []string   `json:"target_sectors,omitempty"`
-	TargetRegions []string   `json:"target_regions,omitempty"`
-	ThreatActorID string     `json:"threat_actor_id,omitempty"`
-	Severity      string     `json:"severity,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description,omitempty"`
+	Status        string          `json:"status"`
+	StartDate     *time.Time      `json:"start_date,omitempty"`
+	EndDate       *time.Time      `json:"end_date,omitempty"`
+	TargetSectors []string        `json:"target_sectors,omitempty"`
+	TargetRegions []string        `json:"target_regions,omitempty"`
+	ThreatActorID string          `json:"threat_actor_id,omitempty"`
+	Severity      string          `json:"severity,omitempty"`
+	TLP           string          `json:"tlp"`
+	PAP           string          `json:"pap"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+var validCampaignStatuses = map[string]bool{"active": true, "inactive": true, "historical": true}
+
+func IsValidCampaignStatus(status string) bool {
+	return validCampaignStatuses[status]
+}
+
+type CampaignWithCount struct {
+	Campaign
+	IndicatorCount int `json:"indicator_count"`
+}
+
+type CampaignListParams struct {
+	Status        string `json:"status,omitempty"`
+	Severity      string `json:"severity,omitempty"`
+	ThreatActorID string `json:"threat_actor_id,omitempty"`
+	Sector        string `json:"sector,omitempty"`
+	Region        string `json:"region,omitempty"`
+	Page          int    `json:"page"`
+	Limit         int    `json:"limit"`
+}
+
+type CampaignListResult struct {
+	Data       []CampaignWithCount `json:"data"`
+	Total      int                 `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+type CampaignInput struct {
+	Name          *string         `json:"name,omitempty"`
+	Description   *string         `json:"description,omitempty"`
+	Status        *string         `json:"status,omitempty"`
+	StartDate     *time.Time      `json:"start_date,omitempty"`
+	EndDate       *time.Time      `json:"end_date,omitempty"`
+	TargetSectors []string        `json:"target_sectors,omitempty"`
+	TargetRegions []string        `json:"target_regions,omitempty"`
+	ThreatActorID *string         `json:"threat_actor_id,omitempty"`
+	Severity      *string         `json:"severity,omitempty"`
+	TLP           *string         `json:"tlp,omitempty"`
+	PAP           *string         `json:"pap,omitempty"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+}
+
+type CampaignIndicatorInput struct {
+	Notes string `json:"notes"`
 }
 
 type CampaignWithTimeline struct {
@@ -30,6 +84,7 @@ type CampaignDetail struct {
 	FirstSeen   *time.Time `json:"first_seen,omitempty"`
 	LastSeen    *time.Time `json:"last_seen,omitempty"`
 	Status      string     `json:"status"`
+	TLP         string     `json:"tlp"`
 }
 
 type TimelinePeriod struct {
@@ -39,14 +94,18 @@ type TimelinePeriod struct {
 }
 
 type TimelineIndicator struct {
-	ID    string        `json:"id"`
-	Type  IndicatorType `json:"type"`
-	Value string        `json:"value"`
+	ID       string        `json:"id"`
+	Type     IndicatorType `json:"type"`
+	Value    string        `json:"value"`
+	HashAlgo string        `json:"hash_algo,omitempty"`
+	TLP      string        `json:"tlp"`
+	Notes    string        `json:"notes,omitempty"`
 }
 
 type TimelineSummary struct {
-	TotalIndicators int `json:"total_indicators"`
-	UniqueIPs       int `json:"unique_ips"`
-	UniqueDomains   int `json:"unique_domains"`
-	DurationDays    int `json:"duration_days"`
+	TotalIndicators int            `json:"total_indicators"`
+	UniqueIPs       int            `json:"unique_ips"`
+	UniqueDomains   int            `json:"unique_domains"`
+	ByType          map[string]int `json:"by_type"`
+	DurationDays    int            `json:"duration_days"`
 }
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	HashAlgoMD5     = "md5"
+	HashAlgoSHA1    = "sha1"
+	HashAlgoSHA256  = "sha256"
+	HashAlgoSHA512  = "sha512"
+	HashAlgoSSDEEP  = "ssdeep"
+	HashAlgoTLSH    = "tlsh"
+	HashAlgoImphash = "imphash"
+)
+
+var hashFormats = map[string]*regexp.Regexp{
+	HashAlgoMD5:     regexp.MustCompile(`^(?i)[0-9a-f]{32}$`),
+	HashAlgoSHA1:    regexp.MustCompile(`^(?i)[0-9a-f]{40}$`),
+	HashAlgoSHA256:  regexp.MustCompile(`^(?i)[0-9a-f]{64}$`),
+	HashAlgoSHA512:  regexp.MustCompile(`^(?i)[0-9a-f]{128}$`),
+	HashAlgoSSDEEP:  regexp.MustCompile(`^[0-9]+:[0-9A-Za-z+/]+:[0-9A-Za-z+/]+$`),
+	HashAlgoTLSH:    regexp.MustCompile(`^(?i)(t1)?[0-9a-f]{70}$`),
+	HashAlgoImphash: regexp.MustCompile(`^(?i)[0-9a-f]{32}$`),
+}
+
+// An imphash has the same format as an MD5, so it is never detected and has to
+// be named by the caller.
+var detectableHashAlgos = []string{
+	HashAlgoMD5, HashAlgoSHA1, HashAlgoSHA256, HashAlgoSHA512, HashAlgoTLSH, HashAlgoSSDEEP,
+}
+
+func IsValidHashAlgo(algo string) bool {
+	_, ok := hashFormats[algo]
+	return ok
+}
+
+func IsValidHash(algo, value string) bool {
+	format, ok := hashFormats[algo]
+	return ok && format.MatchString(strings.TrimSpace(value))
+}
+
+func DetectHashAlgo(value string) string {
+	for _, algo := range detectableHashAlgos {
+		if IsValidHash(algo, value) {
+			return algo
+		}
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+const (
+	HistorySnapshot = "snapshot"
+	HistoryCreate   = "create"
+	HistoryUpdate   = "update"
+	HistoryDelete   = "delete"
+)
+
+type IndicatorVersion struct {
+	Indicator
+	Version   int       `json:"version"`
+	Operation string    `json:"operation"`
+	ValidFrom time.Time `json:"valid_from"`
+}
+
+type IndicatorHistoryParams struct {
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
+
+type IndicatorHistoryResult struct {
+	Data       []IndicatorVersion `json:"data"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
+}
@@ -0,0 +1,61 @@
+package model
+
+const (
+	EntityIndicator   = "indicator"
+	EntityCampaign    = "campaign"
+	EntityThreatActor = "threat_actor"
+)
+
+type IntelImport struct {
+	RefKey        string
+	Force         bool
+	Indicators    []ImportIndicator
+	Campaigns     []ImportCampaign
+	ThreatActors  []ImportThreatActor
+	Relationships []ImportRelationship
+}
+
+type ImportIndicator struct {
+	Ref       string
+	Indicator Indicator
+}
+
+type ImportCampaign struct {
+	Ref      string
+	Campaign Campaign
+}
+
+type ImportThreatActor struct {
+	Ref         string
+	ThreatActor ThreatActor
+}
+
+type ImportRelationship struct {
+	Ref        string
+	SourceType string
+	SourceRef  string
+	TargetType string
+	TargetRef  string
+	Confidence *int
+}
+
+type ImportCounts struct {
+	Created int         `json:"created"`
+	Updated int         `json:"updated"`
+	IDs     AffectedIDs `json:"-"`
+}
+
+type ImportError struct {
+	Ref    string `json:"id"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+type ImportResult struct {
+	Indicators    ImportCounts  `json:"indicators"`
+	Campaigns     ImportCounts  `json:"campaigns"`
+	ThreatActors  ImportCounts  `json:"threat_actors"`
+	Relationships int           `json:"relationships"`
+	Rejected      int           `json:"rejected"`
+	Errors        []ImportError `json:"errors"`
+}
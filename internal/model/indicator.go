@@ -1,31 +1,63 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+var severityOrder = []string{"low", "medium", "high", "critical"}
+
+var validSeverities = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
 
-type IndicatorType string
+func IsValidSeverity(severity string) bool {
+	return validSeverities[severity]
+}
 
 const (
-	IndicatorTypeIP     IndicatorType = "ip"
-	IndicatorTypeDomain IndicatorType = "domain"
-	IndicatorTypeURL    IndicatorType = "url"
-	IndicatorTypeHash   IndicatorType = "hash"
+	RevocationFalsePositive = "false_positive"
+	RevocationExpired       = "expired"
+	RevocationSuperseded    = "superseded"
 )
 
+var validRevocationReasons = map[string]bool{
+	RevocationFalsePositive: true,
+	RevocationExpired:       true,
+	RevocationSuperseded:    true,
+}
+
+func IsValidRevocationReason(reason string) bool {
+	return validRevocationReasons[reason]
+}
+
+func SeveritiesAtLeast(severity string) []string {
+	for i, s := range severityOrder {
+		if s == severity {
+			return severityOrder[i:]
+		}
+	}
+	return nil
+}
+
 type Indicator struct {
-	ID          string        `json:"id"`
-	Type        IndicatorType `json:"type"`
-	Value       string        `json:"value"`
-	Description string        `json:"description,omitempty"`
-	Severity    string        `json:"severity,omitempty"`
-	Confidence  int           `json:"confidence"`
-	FirstSeen   *time.Time    `json:"first_seen,omitempty"`
-	LastSeen    *time.Time    `json:"last_seen,omitempty"`
-	IsActive    bool          `json:"is_active"`
-	Tags        []string      `json:"tags,omitempty"`
-	Metadata    string        `json:"metadata,omitempty"`
-	Source      string        `json:"source,omitempty"`
-	CreatedAt   time.Time     `json:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at"`
+	ID               string          `json:"id"`
+	Type             IndicatorType   `json:"type"`
+	Value            string          `json:"value"`
+	Description      string          `json:"description,omitempty"`
+	Severity         string          `json:"severity,omitempty"`
+	Confidence       int             `json:"confidence"`
+	FirstSeen        *time.Time      `json:"first_seen,omitempty"`
+	LastSeen         *time.Time      `json:"last_seen,omitempty"`
+	IsActive         bool            `json:"is_active"`
+	TLP              string          `json:"tlp"`
+	PAP              string          `json:"pap"`
+	Tags             []string        `json:"tags,omitempty"`
+	Metadata         json.RawMessage `json:"metadata,omitempty"`
+	Source           string          `json:"source,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	RevokedAt        *time.Time      `json:"revoked_at,omitempty"`
+	RevocationReason string          `json:"revocation_reason,omitempty"`
+	HashAlgo         string          `json:"hash_algo,omitempty"`
 }
 
 type IndicatorWithRelations struct {
@@ -33,6 +65,19 @@ type IndicatorWithRelations struct {
 	ThreatActors      []ThreatActorSummary `json:"threat_actors"`
 	Campaigns         []CampaignSummary    `json:"campaigns"`
 	RelatedIndicators []RelatedIndicator   `json:"related_indicators"`
+	SampleID          string               `json:"sample_id,omitempty"`
+	SiblingHashes     []SiblingHash        `json:"sibling_hashes,omitempty"`
+}
+
+type SiblingHash struct {
+	ID       string `json:"id"`
+	Value    string `json:"value"`
+	HashAlgo string `json:"hash_algo"`
+}
+
+type IndicatorSample struct {
+	ID     string        `json:"id"`
+	Hashes []SiblingHash `json:"hashes"`
 }
 
 type RelatedIndicator struct {
@@ -53,3 +98,34 @@ type CampaignSummary struct {
 	Name   string `json:"name"`
 	Active bool   `json:"active"`
 }
+
+type IndicatorInput struct {
+	Type        *IndicatorType  `json:"type,omitempty"`
+	Value       *string         `json:"value,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Severity    *string         `json:"severity,omitempty"`
+	Confidence  *int            `json:"confidence,omitempty"`
+	FirstSeen   *time.Time      `json:"first_seen,omitempty"`
+	LastSeen    *time.Time      `json:"last_seen,omitempty"`
+	IsActive    *bool           `json:"is_active,omitempty"`
+	TLP         *string         `json:"tlp,omitempty"`
+	PAP         *string         `json:"pap,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"`
+	Source      *string         `json:"source,omitempty"`
+	HashAlgo    *string         `json:"hash_algo,omitempty"`
+}
+
+type IndicatorRevokeInput struct {
+	Reason string `json:"reason"`
+}
+
+type IndicatorActorInput struct {
+	Confidence *int `json:"confidence,omitempty"`
+}
+
+type AttributedIndicator struct {
+	Indicator
+	Campaigns    []string `json:"campaigns"`
+	ThreatActors []string `json:"threat_actors"`
+}
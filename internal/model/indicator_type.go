@@ -0,0 +1,181 @@
+package model
+
+import (
+	"net/mail"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type IndicatorType string
+
+const (
+	IndicatorTypeIP          IndicatorType = "ip"
+	IndicatorTypeDomain      IndicatorType = "domain"
+	IndicatorTypeURL         IndicatorType = "url"
+	IndicatorTypeHash        IndicatorType = "hash"
+	IndicatorTypeEmail       IndicatorType = "email"
+	IndicatorTypeASN         IndicatorType = "asn"
+	IndicatorTypeCVE         IndicatorType = "cve"
+	IndicatorTypeJA3         IndicatorType = "ja3"
+	IndicatorTypeJA4         IndicatorType = "ja4"
+	IndicatorTypeFilePath    IndicatorType = "file_path"
+	IndicatorTypeMutex       IndicatorType = "mutex"
+	IndicatorTypeRegistryKey IndicatorType = "registry_key"
+	IndicatorTypeUserAgent   IndicatorType = "user_agent"
+)
+
+// IndicatorTypeSpec describes how values of one indicator type are cleaned up
+// and checked before they are stored. Values are trimmed before Normalize
+// runs; a nil Normalize or Valid leaves the value as it is.
+type IndicatorTypeSpec struct {
+	Type      IndicatorType
+	Normalize func(value string) string
+	Valid     func(value string) bool
+	Invalid   string
+}
+
+// indicatorTypes is the single list of supported types, in the order they are
+// listed in errors and summaries. Hashes are checked against their algorithm
+// instead, see IsValidHash. Uniqueness is decided by the database function
+// indicator_normalized_value, which has to agree with Normalize.
+var indicatorTypes = []IndicatorTypeSpec{
+	{Type: IndicatorTypeIP, Valid: isIPValue, Invalid: "must be an IPv4 or IPv6 address or CIDR block"},
+	{Type: IndicatorTypeDomain},
+	{Type: IndicatorTypeURL},
+	{Type: IndicatorTypeHash},
+	{Type: IndicatorTypeEmail, Normalize: strings.ToLower, Valid: isEmailValue, Invalid: "must be an email address such as user@example.com"},
+	{Type: IndicatorTypeASN, Normalize: normalizeASN, Valid: isASNValue, Invalid: "must be an autonomous system number such as AS13335"},
+	{Type: IndicatorTypeCVE, Normalize: strings.ToUpper, Valid: cvePattern.MatchString, Invalid: "must be a CVE identifier such as CVE-2021-44228"},
+	{Type: IndicatorTypeJA3, Normalize: strings.ToLower, Valid: ja3Pattern.MatchString, Invalid: "must be a JA3 fingerprint (32 hex characters)"},
+	{Type: IndicatorTypeJA4, Normalize: strings.ToLower, Valid: ja4Pattern.MatchString, Invalid: "must be a JA4 fingerprint such as t13d1516h2_8daaf6152771_b186095e22b6"},
+	{Type: IndicatorTypeFilePath, Valid: isFilePathValue, Invalid: "must be an absolute Windows or POSIX path"},
+	{Type: IndicatorTypeMutex, Valid: isMutexValue, Invalid: "must be at most 260 characters without control characters"},
+	{Type: IndicatorTypeRegistryKey, Normalize: normalizeRegistryKey, Valid: isRegistryKeyValue, Invalid: "must start with a registry hive such as HKEY_LOCAL_MACHINE or HKLM"},
+	{Type: IndicatorTypeUserAgent, Valid: hasNoControlCharacters, Invalid: "must not contain control characters"},
+}
+
+var (
+	cvePattern      = regexp.MustCompile(`^CVE-[0-9]{4}-[0-9]{4,}$`)
+	ja3Pattern      = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	ja4Pattern      = regexp.MustCompile(`^[tqd][0-9a-z]{2}[di][0-9]{4}[0-9a-z]{2}_[0-9a-f]{12}_[0-9a-f]{12}$`)
+	filePathPattern = regexp.MustCompile(`^([A-Za-z]:[\\/]|\\\\[^\\]+\\|%[A-Za-z0-9_()]+%[\\/]|~?/)`)
+)
+
+var registryHives = map[string]string{
+	"HKEY_LOCAL_MACHINE":  "HKEY_LOCAL_MACHINE",
+	"HKLM":                "HKEY_LOCAL_MACHINE",
+	"HKEY_CURRENT_USER":   "HKEY_CURRENT_USER",
+	"HKCU":                "HKEY_CURRENT_USER",
+	"HKEY_CLASSES_ROOT":   "HKEY_CLASSES_ROOT",
+	"HKCR":                "HKEY_CLASSES_ROOT",
+	"HKEY_USERS":          "HKEY_USERS",
+	"HKU":                 "HKEY_USERS",
+	"HKEY_CURRENT_CONFIG": "HKEY_CURRENT_CONFIG",
+	"HKCC":                "HKEY_CURRENT_CONFIG",
+}
+
+func LookupIndicatorType(t IndicatorType) (IndicatorTypeSpec, bool) {
+	for _, spec := range indicatorTypes {
+		if spec.Type == t {
+			return spec, true
+		}
+	}
+	return IndicatorTypeSpec{}, false
+}
+
+func IndicatorTypes() []IndicatorType {
+	types := make([]IndicatorType, 0, len(indicatorTypes))
+	for _, spec := range indicatorTypes {
+		types = append(types, spec.Type)
+	}
+	return types
+}
+
+// IndicatorTypeNames lists the supported types for error messages.
+func IndicatorTypeNames() string {
+	names := make([]string, 0, len(indicatorTypes))
+	for _, spec := range indicatorTypes {
+		names = append(names, string(spec.Type))
+	}
+	return strings.Join(names, ", ")
+}
+
+func (t IndicatorType) IsValid() bool {
+	_, ok := LookupIndicatorType(t)
+	return ok
+}
+
+func (t IndicatorType) Normalize(value string) string {
+	value = strings.TrimSpace(value)
+	if spec, ok := LookupIndicatorType(t); ok && spec.Normalize != nil {
+		return spec.Normalize(value)
+	}
+	return value
+}
+
+func isIPValue(value string) bool {
+	value = strings.TrimSpace(value)
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return addr.Zone() == ""
+	}
+	prefix, err := netip.ParsePrefix(value)
+	return err == nil && prefix.Addr().Zone() == ""
+}
+
+func isEmailValue(value string) bool {
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Name != "" || addr.Address != value {
+		return false
+	}
+	_, domain, _ := strings.Cut(value, "@")
+	return strings.Contains(domain, ".")
+}
+
+func normalizeASN(value string) string {
+	number := strings.TrimPrefix(strings.ToUpper(value), "AS")
+	if n, err := strconv.ParseUint(number, 10, 32); err == nil {
+		return "AS" + strconv.FormatUint(n, 10)
+	}
+	return value
+}
+
+func isASNValue(value string) bool {
+	number, ok := strings.CutPrefix(value, "AS")
+	n, err := strconv.ParseUint(number, 10, 32)
+	return ok && err == nil && n > 0
+}
+
+func isFilePathValue(value string) bool {
+	return filePathPattern.MatchString(value) && hasNoControlCharacters(value)
+}
+
+func isMutexValue(value string) bool {
+	return len(value) <= 260 && hasNoControlCharacters(value)
+}
+
+// normalizeRegistryKey spells out abbreviated hives, so HKLM\Software and
+// HKEY_LOCAL_MACHINE\Software are stored as the same key.
+func normalizeRegistryKey(value string) string {
+	hive, path, _ := strings.Cut(value, `\`)
+	if full, ok := registryHives[strings.ToUpper(hive)]; ok {
+		hive = full
+	}
+	path = strings.TrimRight(path, `\`)
+	if path == "" {
+		return hive
+	}
+	return hive + `\` + path
+}
+
+func isRegistryKeyValue(value string) bool {
+	hive, _, _ := strings.Cut(value, `\`)
+	_, ok := registryHives[hive]
+	return ok && hasNoControlCharacters(value)
+}
+
+func hasNoControlCharacters(value string) bool {
+	return strings.IndexFunc(value, unicode.IsControl) < 0
+}
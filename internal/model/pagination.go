@@ -8,14 +8,26 @@ type Pagination struct {
 }
 
 type SearchParams struct {
-	Type           string `json:"type,omitempty"`
-	Value          string `json:"value,omitempty"`
-	ThreatActorID  string `json:"threat_actor,omitempty"`
-	CampaignID     string `json:"campaign,omitempty"`
-	FirstSeenAfter string `json:"first_seen_after,omitempty"`
-	LastSeenBefore string `json:"last_seen_before,omitempty"`
-	Page           int    `json:"page"`
-	Limit          int    `json:"limit"`
+	Query            string          `json:"q,omitempty"`
+	Type             string          `json:"type,omitempty"`
+	Value            string          `json:"value,omitempty"`
+	ThreatActorID    string          `json:"threat_actor,omitempty"`
+	CampaignID       string          `json:"campaign,omitempty"`
+	FirstSeenAfter   string          `json:"first_seen_after,omitempty"`
+	LastSeenBefore   string          `json:"last_seen_before,omitempty"`
+	MinConfidence    int             `json:"min_confidence,omitempty"`
+	MinSeverity      string          `json:"min_severity,omitempty"`
+	Types            []IndicatorType `json:"types,omitempty"`
+	ActiveOnly       bool            `json:"active_only,omitempty"`
+	IncludeRevoked   bool            `json:"include_revoked,omitempty"`
+	CIDR             string          `json:"cidr,omitempty"`
+	IPInRange        string          `json:"ip_in_range,omitempty"`
+	DomainSuffix     string          `json:"domain_suffix,omitempty"`
+	RegisteredDomain string          `json:"registered_domain,omitempty"`
+	TLD              string          `json:"tld,omitempty"`
+	HashAlgo         string          `json:"hash_algo,omitempty"`
+	Page             int             `json:"page"`
+	Limit            int             `json:"limit"`
 }
 
 type SearchResult struct {
@@ -27,13 +39,19 @@ type SearchResult struct {
 }
 
 type IndicatorSearchResult struct {
-	ID               string `json:"id"`
-	Type             string `json:"type"`
-	Value            string `json:"value"`
-	Confidence       int    `json:"confidence"`
-	FirstSeen        string `json:"first_seen,omitempty"`
-	CampaignCount    int    `json:"campaign_count"`
-	ThreatActorCount int    `json:"threat_actor_count"`
+	ID               string  `json:"id"`
+	Type             string  `json:"type"`
+	Value            string  `json:"value"`
+	Confidence       int     `json:"confidence"`
+	TLP              string  `json:"tlp"`
+	FirstSeen        string  `json:"first_seen,omitempty"`
+	CampaignCount    int     `json:"campaign_count"`
+	ThreatActorCount int     `json:"threat_actor_count"`
+	RevokedAt        string  `json:"revoked_at,omitempty"`
+	RevocationReason string  `json:"revocation_reason,omitempty"`
+	HashAlgo         string  `json:"hash_algo,omitempty"`
+	Rank             float64 `json:"rank,omitempty"`
+	Snippet          string  `json:"snippet,omitempty"`
 }
 
 type TimelineParams struct {
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type TaxiiObjectParams struct {
+	CampaignID string
+	AddedAfter *time.Time
+	AfterDate  *time.Time
+	AfterID    string
+	Limit      int
+}
+
+type TaxiiIndicator struct {
+	Indicator Indicator
+	DateAdded time.Time
+}
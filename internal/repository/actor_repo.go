@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+const actorAliasesColumn = `COALESCE((SELECT array_agg(a.alias ORDER BY a.alias)
+	FROM threat_actor_aliases a WHERE a.actor_id = ta.id), '{}') AS aliases`
+
+type ActorRepository struct {
+	db *sql.DB
+	sq squirrel.StatementBuilderType
+}
+
+func NewActorRepository(db *sql.DB) *ActorRepository {
+	return &ActorRepository{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *ActorRepository) List(ctx context.Context, params model.ActorListParams) (*model.ActorListResult, error) {
+	tenantID := tenant.FromContext(ctx)
+	countSQL, countArgs, err := applyActorFilters(r.sq.Select("COUNT(*)").From("threat_actors ta"), tenantID, params).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build actor count query: %w", err)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count threat actors: %w", err)
+	}
+
+	query := applyActorFilters(r.sq.Select(threatActorColumns, actorAliasesColumn).Column(`(
+		SELECT COUNT(*) FROM indicator_actors ia
+		JOIN indicators i ON i.id = ia.indicator_id
+		WHERE ia.actor_id = ta.id AND i.tlp = ANY(?) AND i.revoked_at IS NULL) AS indicator_count`, pq.Array(allowedTLP(ctx)),
+	).From("threat_actors ta"), tenantID, params).
+		OrderBy("ta.name").
+		Limit(uint64(params.Limit)).
+		Offset(uint64((params.Page - 1) * params.Limit))
+
+	querySQL, queryArgs, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build actor list query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, querySQL, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list threat actors: %w", err)
+	}
+	defer rows.Close()
+
+	actors := []model.ThreatActorWithCount{}
+	for rows.Next() {
+		var aliases []string
+		var count int
+		actor, err := scanThreatActor(rows, pq.Array(&aliases), &count)
+		if err != nil {
+			return nil, err
+		}
+		actor.Aliases = aliases
+		actors = append(actors, model.ThreatActorWithCount{ThreatActor: *actor, IndicatorCount: count})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate threat actors: %w", err)
+	}
+
+	return &model.ActorListResult{
+		Data:       actors,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: (total + params.Limit - 1) / params.Limit,
+	}, nil
+}
+
+func applyActorFilters(query squirrel.SelectBuilder, tenantID string, params model.ActorListParams) squirrel.SelectBuilder {
+	query = query.Where(squirrel.Eq{"ta.tenant_id": tenantID})
+	if params.Name != "" {
+		pattern := "%" + params.Name + "%"
+		query = query.Where(`(ta.name ILIKE ? OR EXISTS (
+			SELECT 1 FROM threat_actor_aliases a WHERE a.actor_id = ta.id AND a.alias ILIKE ?))`, pattern, pattern)
+	}
+	if params.Country != "" {
+		query = query.Where("LOWER(ta.country) = LOWER(?)", params.Country)
+	}
+	if params.Motivation != "" {
+		query = query.Where(squirrel.Eq{"ta.motivation": params.Motivation})
+	}
+	if params.MinConfidence > 0 {
+		query = query.Where(squirrel.GtOrEq{"ta.confidence_level": params.MinConfidence})
+	}
+	return query
+}
+
+func (r *ActorRepository) GetByID(ctx context.Context, id string) (*model.ThreatActorDetail, error) {
+	tenantID := tenant.FromContext(ctx)
+	var aliases []string
+	actor, err := scanThreatActor(r.db.QueryRowContext(ctx, `
+		SELECT `+threatActorColumns+`, `+actorAliasesColumn+`
+		FROM threat_actors ta
+		WHERE ta.id = $1 AND ta.tenant_id = $2
+	`, id, tenantID), pq.Array(&aliases))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	actor.Aliases = aliases
+
+	detail := &model.ThreatActorDetail{
+		ThreatActor:     *actor,
+		Campaigns:       []model.CampaignSummary{},
+		IndicatorCounts: map[string]int{},
+	}
+	maxTLP := marking.Clear
+
+	campaignRows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, status = 'active', tlp
+		FROM campaigns
+		WHERE threat_actor_id = $1 AND tenant_id = $2 AND tlp = ANY($3)
+		ORDER BY start_date DESC NULLS LAST, name
+	`, id, tenantID, pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actor campaigns: %w", err)
+	}
+	defer campaignRows.Close()
+
+	for campaignRows.Next() {
+		var c model.CampaignSummary
+		var tlp string
+		if err := campaignRows.Scan(&c.ID, &c.Name, &c.Active, &tlp); err != nil {
+			return nil, fmt.Errorf("failed to scan actor campaign: %w", err)
+		}
+		detail.Campaigns = append(detail.Campaigns, c)
+		maxTLP = marking.Max(maxTLP, marking.Level(tlp))
+	}
+	if err := campaignRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate actor campaigns: %w", err)
+	}
+
+	countRows, err := r.db.QueryContext(ctx, `
+		SELECT i.type, i.tlp, COUNT(*)
+		FROM indicator_actors ia
+		JOIN indicators i ON i.id = ia.indicator_id
+		WHERE ia.actor_id = $1 AND i.tenant_id = $2 AND i.tlp = ANY($3) AND i.revoked_at IS NULL
+		GROUP BY i.type, i.tlp
+	`, id, tenantID, pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actor indicator counts: %w", err)
+	}
+	defer countRows.Close()
+
+	for countRows.Next() {
+		var indicatorType, tlp string
+		var count int
+		if err := countRows.Scan(&indicatorType, &tlp, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan actor indicator count: %w", err)
+		}
+		detail.IndicatorCounts[indicatorType] += count
+		maxTLP = marking.Max(maxTLP, marking.Level(tlp))
+	}
+	if err := countRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate actor indicator counts: %w", err)
+	}
+	detail.MaxTLP = string(maxTLP)
+
+	return detail, nil
+}
+
+func (r *ActorRepository) GetIndicators(ctx context.Context, actorID string, params model.ActorIndicatorParams) (*model.ActorIndicatorResult, error) {
+	if err := r.ensureExists(ctx, actorID); err != nil {
+		return nil, err
+	}
+
+	filter := squirrel.And{
+		squirrel.Eq{"ia.actor_id": actorID, "i.tenant_id": tenant.FromContext(ctx), "i.tlp": allowedTLP(ctx)},
+		squirrel.Expr("i.revoked_at IS NULL"),
+	}
+	if params.Type != "" {
+		filter = append(filter, squirrel.Eq{"i.type": params.Type})
+	}
+
+	countSQL, countArgs, err := r.sq.Select("COUNT(*)").
+		From("indicator_actors ia").
+		Join("indicators i ON i.id = ia.indicator_id").
+		Where(filter).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build actor indicator count query: %w", err)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count actor indicators: %w", err)
+	}
+
+	querySQL, queryArgs, err := r.sq.Select(indicatorColumns, "ia.attribution_confidence", "ia.added_at").
+		From("indicator_actors ia").
+		Join("indicators i ON i.id = ia.indicator_id").
+		Where(filter).
+		OrderBy("ia.attribution_confidence DESC NULLS LAST", "i.created_at DESC", "i.id").
+		Limit(uint64(params.Limit)).
+		Offset(uint64((params.Page - 1) * params.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build actor indicator query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, querySQL, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actor indicators: %w", err)
+	}
+	defer rows.Close()
+
+	indicators := []model.ActorIndicator{}
+	for rows.Next() {
+		var confidence sql.NullInt64
+		var addedAt sql.NullTime
+		ind, err := scanIndicator(rows, &confidence, &addedAt)
+		if err != nil {
+			return nil, err
+		}
+		item := model.ActorIndicator{Indicator: *ind, AttributionConfidence: int(confidence.Int64)}
+		if addedAt.Valid {
+			item.AddedAt = addedAt.Time
+		}
+		indicators = append(indicators, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate actor indicators: %w", err)
+	}
+
+	return &model.ActorIndicatorResult{
+		Data:       indicators,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: (total + params.Limit - 1) / params.Limit,
+	}, nil
+}
+
+func (r *ActorRepository) SetAliases(ctx context.Context, actorID string, aliases []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin alias transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tenantID := tenant.FromContext(ctx)
+	var id string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM threat_actors WHERE id = $1 AND tenant_id = $2 FOR UPDATE`, actorID, tenantID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock threat actor: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM threat_actor_aliases WHERE actor_id = $1`, actorID); err != nil {
+		return fmt.Errorf("failed to clear aliases: %w", err)
+	}
+
+	if len(aliases) > 0 {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO threat_actor_aliases (actor_id, alias, tenant_id)
+			SELECT $1, UNNEST($2::TEXT[]), $3
+		`, actorID, pq.Array(aliases), tenantID)
+		if isUniqueViolation(err) {
+			return ErrConflict
+		}
+		if err != nil {
+			return fmt.Errorf("failed to insert aliases: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE threat_actors SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, actorID); err != nil {
+		return fmt.Errorf("failed to touch threat actor: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit aliases: %w", err)
+	}
+	return nil
+}
+
+func (r *ActorRepository) ensureExists(ctx context.Context, actorID string) error {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM threat_actors WHERE id = $1 AND tenant_id = $2)`,
+		actorID, tenant.FromContext(ctx)).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check threat actor: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/Masterminds/squirrel"
+)
+
+const auditColumns = `id, occurred_at, principal_type, principal_id, principal_name,
+	action, entity_type, entity_id, tlp, changes, params`
+
+type AuditRepository struct {
+	db *sql.DB
+	sq squirrel.StatementBuilderType
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, event *model.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (tenant_id, principal_type, principal_id, principal_name,
+			action, entity_type, entity_id, tlp, changes, params)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, occurred_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		tenant.FromContext(ctx), event.PrincipalType, event.PrincipalID, nullString(event.PrincipalName),
+		event.Action, event.EntityType, nullString(event.EntityID), event.TLP, nullString(string(event.Changes)), nullString(string(event.Params)),
+	).Scan(&event.ID, &event.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, params model.AuditListParams) (*model.AuditListResult, error) {
+	countSQL, countArgs, err := applyAuditFilters(ctx, r.sq.Select("COUNT(*)").From("audit_events"), params).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build audit count query: %w", err)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count audit events: %w", err)
+	}
+
+	querySQL, queryArgs, err := applyAuditFilters(ctx, r.sq.Select(auditColumns).From("audit_events"), params).
+		OrderBy("occurred_at DESC", "id DESC").
+		Limit(uint64(params.Limit)).
+		Offset(uint64((params.Page - 1) * params.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build audit list query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, querySQL, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []model.AuditEvent{}
+	for rows.Next() {
+		var event model.AuditEvent
+		var principalName, entityID sql.NullString
+		var changes, eventParams []byte
+		if err := rows.Scan(
+			&event.ID, &event.OccurredAt, &event.PrincipalType, &event.PrincipalID, &principalName,
+			&event.Action, &event.EntityType, &entityID, &event.TLP, &changes, &eventParams,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		event.PrincipalName = principalName.String
+		event.EntityID = entityID.String
+		event.Changes = changes
+		event.Params = eventParams
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit events: %w", err)
+	}
+
+	return &model.AuditListResult{
+		Data:       events,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: (total + params.Limit - 1) / params.Limit,
+	}, nil
+}
+
+func applyAuditFilters(ctx context.Context, query squirrel.SelectBuilder, params model.AuditListParams) squirrel.SelectBuilder {
+	query = query.Where(squirrel.Eq{"tenant_id": tenant.FromContext(ctx)})
+	if params.PrincipalID != "" {
+		query = query.Where(squirrel.Eq{"principal_id": params.PrincipalID})
+	}
+	if params.EntityType != "" {
+		query = query.Where(squirrel.Eq{"entity_type": params.EntityType})
+	}
+	if params.EntityID != "" {
+		query = query.Where(squirrel.Eq{"entity_id": params.EntityID})
+	}
+	if params.Action != "" {
+		query = query.Where(squirrel.Eq{"action": params.Action})
+	}
+	if params.Since != nil {
+		query = query.Where(squirrel.GtOrEq{"occurred_at": *params.Since})
+	}
+	if params.Until != nil {
+		query = query.Where(squirrel.Lt{"occurred_at": *params.Until})
+	}
+	return query
+}
@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/lib/pq"
+)
+
+var importEntityTables = map[string]string{
+	model.EntityIndicator:   "indicators",
+	model.EntityCampaign:    "campaigns",
+	model.EntityThreatActor: "threat_actors",
+}
+
+var importMarkedEntities = map[string]bool{
+	model.EntityIndicator: true,
+	model.EntityCampaign:  true,
+}
+
+type importTx struct {
+	tx       *sql.Tx
+	tenantID string
+	allowed  interface{}
+	refKey   string
+	force    bool
+	ids      map[string]map[string]string
+}
+
+func (r *BundleRepository) Import(ctx context.Context, data *model.IntelImport) (*model.ImportResult, error) {
+	result := &model.ImportResult{Errors: []model.ImportError{}}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin import transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	imp := &importTx{
+		tx:       tx,
+		tenantID: tenant.FromContext(ctx),
+		allowed:  pq.Array(allowedTLP(ctx)),
+		refKey:   data.RefKey,
+		force:    data.Force,
+		ids: map[string]map[string]string{
+			model.EntityIndicator:   {},
+			model.EntityCampaign:    {},
+			model.EntityThreatActor: {},
+		},
+	}
+
+	for _, item := range data.ThreatActors {
+		created, err := imp.upsertThreatActor(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		countImport(&result.ThreatActors, imp.ids[model.EntityThreatActor][item.Ref], created)
+	}
+
+	for _, item := range data.Campaigns {
+		created, err := imp.upsertCampaign(ctx, item)
+		if errors.Is(err, ErrNotWritable) {
+			result.Errors = append(result.Errors, model.ImportError{
+				Ref: item.Ref, Type: model.EntityCampaign, Reason: "cannot be written",
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		countImport(&result.Campaigns, imp.ids[model.EntityCampaign][item.Ref], created)
+	}
+
+	for _, item := range data.Indicators {
+		created, err := imp.upsertIndicator(ctx, item)
+		if errors.Is(err, ErrFalsePositive) {
+			result.Errors = append(result.Errors, model.ImportError{
+				Ref: item.Ref, Type: model.EntityIndicator, Reason: "matches an indicator revoked as a false positive",
+			})
+			continue
+		}
+		if errors.Is(err, ErrNotWritable) || errors.Is(err, ErrConflict) {
+			result.Errors = append(result.Errors, model.ImportError{
+				Ref: item.Ref, Type: model.EntityIndicator, Reason: "cannot be written",
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		countImport(&result.Indicators, imp.ids[model.EntityIndicator][item.Ref], created)
+	}
+
+	for _, rel := range data.Relationships {
+		reason, err := imp.link(ctx, rel)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			result.Errors = append(result.Errors, model.ImportError{Ref: rel.Ref, Type: "relationship", Reason: reason})
+			continue
+		}
+		result.Relationships++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+
+	return result, nil
+}
+
+func countImport(counts *model.ImportCounts, id string, created bool) {
+	if created {
+		counts.Created++
+		counts.IDs.Created = append(counts.IDs.Created, id)
+	} else {
+		counts.Updated++
+		counts.IDs.Updated = append(counts.IDs.Updated, id)
+	}
+}
+
+func (imp *importTx) upsertThreatActor(ctx context.Context, item model.ImportThreatActor) (bool, error) {
+	actor := item.ThreatActor
+	metadata := jsonObjectOrEmpty(actor.Metadata)
+
+	var id string
+	err := imp.tx.QueryRowContext(ctx, `
+		UPDATE threat_actors SET
+			description = COALESCE($3, description),
+			motivation = COALESCE($4, motivation),
+			country = COALESCE($5, country),
+			first_seen = LEAST(first_seen, $6),
+			last_seen = GREATEST(last_seen, $7),
+			confidence_level = $8,
+			metadata = COALESCE(metadata, '{}'::jsonb) || $9::jsonb,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE metadata->>$1 = $2 AND tenant_id = $10
+		RETURNING id
+	`, imp.refKey, item.Ref, nullString(actor.Description), nullString(actor.Motivation), nullString(actor.Country),
+		actor.FirstSeen, actor.LastSeen, actor.ConfidenceLevel, metadata, imp.tenantID,
+	).Scan(&id)
+	if err == nil {
+		imp.ids[model.EntityThreatActor][item.Ref] = id
+		return false, imp.addAliases(ctx, id, actor.Aliases)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("failed to update imported threat actor: %w", err)
+	}
+
+	var inserted bool
+	err = imp.tx.QueryRowContext(ctx, `
+		INSERT INTO threat_actors AS ta (name, description, motivation, country, first_seen, last_seen, confidence_level, metadata, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (tenant_id, name) DO UPDATE SET
+			description = COALESCE(EXCLUDED.description, ta.description),
+			motivation = COALESCE(EXCLUDED.motivation, ta.motivation),
+			country = COALESCE(EXCLUDED.country, ta.country),
+			first_seen = LEAST(ta.first_seen, EXCLUDED.first_seen),
+			last_seen = GREATEST(ta.last_seen, EXCLUDED.last_seen),
+			metadata = COALESCE(ta.metadata, '{}'::jsonb) || EXCLUDED.metadata,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id, (xmax = 0) AS inserted
+	`, actor.Name, nullString(actor.Description), nullString(actor.Motivation), nullString(actor.Country),
+		actor.FirstSeen, actor.LastSeen, actor.ConfidenceLevel, metadata, imp.tenantID,
+	).Scan(&id, &inserted)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert imported threat actor: %w", err)
+	}
+
+	imp.ids[model.EntityThreatActor][item.Ref] = id
+	return inserted, imp.addAliases(ctx, id, actor.Aliases)
+}
+
+func (imp *importTx) addAliases(ctx context.Context, actorID string, aliases []string) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	if _, err := imp.tx.ExecContext(ctx, `
+		INSERT INTO threat_actor_aliases (actor_id, alias, tenant_id)
+		SELECT $1, UNNEST($2::TEXT[]), $3
+		ON CONFLICT DO NOTHING
+	`, actorID, pq.Array(aliases), imp.tenantID); err != nil {
+		return fmt.Errorf("failed to import threat actor aliases: %w", err)
+	}
+	return nil
+}
+
+func (imp *importTx) upsertCampaign(ctx context.Context, item model.ImportCampaign) (bool, error) {
+	campaign := item.Campaign
+	metadata := jsonObjectOrEmpty(campaign.Metadata)
+
+	var id string
+	err := imp.tx.QueryRowContext(ctx, `
+		UPDATE campaigns SET
+			name = $3,
+			description = COALESCE($4, description),
+			start_date = COALESCE($5, start_date),
+			end_date = COALESCE($6, end_date),
+			severity = COALESCE($7, severity),
+			metadata = COALESCE(metadata, '{}'::jsonb) || $8::jsonb,
+			tlp = CASE WHEN marking_rank($10) > marking_rank(tlp) THEN $10 ELSE tlp END,
+			pap = CASE WHEN marking_rank($11) > marking_rank(pap) THEN $11 ELSE pap END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE metadata->>$1 = $2 AND tenant_id = $9 AND tlp = ANY($12)
+		RETURNING id
+	`, imp.refKey, item.Ref, campaign.Name, nullString(campaign.Description),
+		campaign.StartDate, campaign.EndDate, nullString(campaign.Severity), metadata, imp.tenantID,
+		campaign.TLP, campaign.PAP, imp.allowed,
+	).Scan(&id)
+	if err == nil {
+		imp.ids[model.EntityCampaign][item.Ref] = id
+		return false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("failed to update imported campaign: %w", err)
+	}
+
+	var hidden bool
+	if err := imp.tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM campaigns WHERE metadata->>$1 = $2 AND tenant_id = $3)
+	`, imp.refKey, item.Ref, imp.tenantID).Scan(&hidden); err != nil {
+		return false, fmt.Errorf("failed to check imported campaign: %w", err)
+	}
+	if hidden {
+		imp.ids[model.EntityCampaign][item.Ref] = ""
+		return false, ErrNotWritable
+	}
+
+	err = imp.tx.QueryRowContext(ctx, `
+		INSERT INTO campaigns (name, description, start_date, end_date, severity, metadata, tenant_id, tlp, pap)
+		VALUES ($1, $2, $3, $4, COALESCE($5, 'medium'), $6, $7, $8, $9)
+		RETURNING id
+	`, campaign.Name, nullString(campaign.Description), campaign.StartDate, campaign.EndDate,
+		nullString(campaign.Severity), metadata, imp.tenantID, campaign.TLP, campaign.PAP,
+	).Scan(&id)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert imported campaign: %w", err)
+	}
+
+	imp.ids[model.EntityCampaign][item.Ref] = id
+	return true, nil
+}
+
+func (imp *importTx) upsertIndicator(ctx context.Context, item model.ImportIndicator) (bool, error) {
+	indicator := item.Indicator
+	tags, metadata, err := marshalIndicatorJSON(&indicator)
+	if err != nil {
+		return false, err
+	}
+
+	host, registeredDomain, tld := hostColumns(&indicator)
+
+	var id string
+	var inserted bool
+	err = imp.tx.QueryRowContext(ctx, `
+		INSERT INTO indicators AS i (type, value, description, severity, confidence,
+			first_seen, last_seen, is_active, tags, metadata, source, tenant_id, tlp, pap,
+			host, registered_domain, tld, hash_algo)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $16, $17, $18, $19)
+		ON CONFLICT (tenant_id, type, normalized_value) DO UPDATE SET `+indicatorMergeAssignments+`
+		WHERE i.tlp = ANY($20) AND (`+indicatorNotFalsePositive+` OR $15)
+		RETURNING id, (xmax = 0) AS inserted
+	`, indicator.Type, indicator.Value, nullString(indicator.Description),
+		indicator.Severity, indicator.Confidence, indicator.FirstSeen, indicator.LastSeen,
+		indicator.IsActive, tags, metadata, nullString(indicator.Source), imp.tenantID,
+		indicator.TLP, indicator.PAP, imp.force, host, registeredDomain, tld,
+		nullString(indicator.HashAlgo), imp.allowed,
+	).Scan(&id, &inserted)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Relationships must not resolve to the blocked indicator by reference.
+		imp.ids[model.EntityIndicator][item.Ref] = ""
+		return false, upsertBlockedBy(ctx, imp.tx, &indicator)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to upsert imported indicator: %w", err)
+	}
+
+	imp.ids[model.EntityIndicator][item.Ref] = id
+	return inserted, nil
+}
+
+func (imp *importTx) link(ctx context.Context, rel model.ImportRelationship) (string, error) {
+	sourceID, err := imp.resolve(ctx, rel.SourceType, rel.SourceRef)
+	if err != nil {
+		return "", err
+	}
+	if sourceID == "" {
+		return "unknown source_ref " + rel.SourceRef, nil
+	}
+
+	targetID, err := imp.resolve(ctx, rel.TargetType, rel.TargetRef)
+	if err != nil {
+		return "", err
+	}
+	if targetID == "" {
+		return "unknown target_ref " + rel.TargetRef, nil
+	}
+
+	var query string
+	args := []interface{}{sourceID, targetID, imp.tenantID}
+	switch {
+	case rel.SourceType == model.EntityIndicator && rel.TargetType == model.EntityCampaign:
+		query = `
+			INSERT INTO indicator_campaigns (indicator_id, campaign_id, tenant_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+		`
+	case rel.SourceType == model.EntityIndicator && rel.TargetType == model.EntityThreatActor:
+		query = `
+			INSERT INTO indicator_actors (indicator_id, actor_id, tenant_id, attribution_confidence)
+			VALUES ($1, $2, $3, COALESCE($4::INTEGER, 50))
+			ON CONFLICT (indicator_id, actor_id) DO UPDATE SET
+				attribution_confidence = COALESCE($4::INTEGER, indicator_actors.attribution_confidence)
+			WHERE indicator_actors.tenant_id = EXCLUDED.tenant_id
+		`
+		args = append(args, rel.Confidence)
+	case rel.SourceType == model.EntityCampaign && rel.TargetType == model.EntityThreatActor:
+		query = `UPDATE campaigns SET threat_actor_id = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND tenant_id = $3`
+	default:
+		return fmt.Sprintf("unsupported relationship between %s and %s", rel.SourceType, rel.TargetType), nil
+	}
+
+	if _, err := imp.tx.ExecContext(ctx, query, args...); err != nil {
+		return "", fmt.Errorf("failed to import relationship: %w", err)
+	}
+
+	return "", nil
+}
+
+func (imp *importTx) resolve(ctx context.Context, entity, ref string) (string, error) {
+	if id, ok := imp.ids[entity][ref]; ok {
+		return id, nil
+	}
+
+	table, ok := importEntityTables[entity]
+	if !ok {
+		return "", nil
+	}
+
+	_, localID, _ := strings.Cut(ref, "--")
+
+	// Objects above the caller's clearance resolve as unknown references.
+	args := []interface{}{imp.refKey, ref, localID, imp.tenantID}
+	visible := ""
+	if importMarkedEntities[entity] {
+		args = append(args, imp.allowed)
+		visible = " AND tlp = ANY($5)"
+	}
+
+	var id string
+	err := imp.tx.QueryRowContext(ctx, `
+		SELECT id FROM `+table+`
+		WHERE tenant_id = $4`+visible+` AND (metadata->>$1 = $2 OR id::text = $3)
+		ORDER BY COALESCE(metadata->>$1 = $2, FALSE) DESC
+		LIMIT 1
+	`, args...).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s reference: %w", entity, err)
+	}
+
+	imp.ids[entity][ref] = id
+	return id, nil
+}
+
+func jsonObjectOrEmpty(data []byte) string {
+	if len(data) == 0 {
+		return "{}"
+	}
+	return string(data)
+}
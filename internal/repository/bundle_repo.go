@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/lib/pq"
+)
+
+type BundleRepository struct {
+	db *sql.DB
+}
+
+func NewBundleRepository(db *sql.DB) *BundleRepository {
+	return &BundleRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *BundleRepository) GetByIndicator(ctx context.Context, indicatorID string) (*model.IntelBundle, error) {
+	indicators, err := r.getIndicators(ctx, []string{indicatorID})
+	if err != nil {
+		return nil, err
+	}
+	if len(indicators) == 0 {
+		return nil, ErrNotFound
+	}
+
+	campaignLinks, err := r.getCampaignLinks(ctx, `ic.indicator_id = $1`, indicatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	campaignIDs := make([]string, 0, len(campaignLinks))
+	for _, link := range campaignLinks {
+		campaignIDs = append(campaignIDs, link.CampaignID)
+	}
+	campaigns, err := r.getCampaigns(ctx, campaignIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &model.IntelBundle{
+		Indicators:         indicators,
+		Campaigns:          campaigns,
+		IndicatorCampaigns: campaignLinks,
+	}
+	if err := r.attachActors(ctx, bundle); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func (r *BundleRepository) GetByCampaign(ctx context.Context, campaignID string) (*model.IntelBundle, error) {
+	campaigns, err := r.getCampaigns(ctx, []string{campaignID})
+	if err != nil {
+		return nil, err
+	}
+	if len(campaigns) == 0 {
+		return nil, ErrNotFound
+	}
+
+	campaignLinks, err := r.getCampaignLinks(ctx, `ic.campaign_id = $1 AND i.revoked_at IS NULL`, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	indicatorIDs := make([]string, 0, len(campaignLinks))
+	for _, link := range campaignLinks {
+		indicatorIDs = append(indicatorIDs, link.IndicatorID)
+	}
+	indicators, err := r.getIndicators(ctx, indicatorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &model.IntelBundle{
+		Indicators:         indicators,
+		Campaigns:          campaigns,
+		IndicatorCampaigns: campaignLinks,
+	}
+	if err := r.attachActors(ctx, bundle); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func (r *BundleRepository) attachActors(ctx context.Context, bundle *model.IntelBundle) error {
+	indicatorIDs := make([]string, 0, len(bundle.Indicators))
+	for _, ind := range bundle.Indicators {
+		indicatorIDs = append(indicatorIDs, ind.ID)
+	}
+
+	actorLinks, err := r.getActorLinks(ctx, indicatorIDs)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	var actorIDs []string
+	for _, link := range actorLinks {
+		if !seen[link.ActorID] {
+			seen[link.ActorID] = true
+			actorIDs = append(actorIDs, link.ActorID)
+		}
+	}
+	for _, campaign := range bundle.Campaigns {
+		if campaign.ThreatActorID != "" && !seen[campaign.ThreatActorID] {
+			seen[campaign.ThreatActorID] = true
+			actorIDs = append(actorIDs, campaign.ThreatActorID)
+		}
+	}
+
+	actors, err := r.getThreatActors(ctx, actorIDs)
+	if err != nil {
+		return err
+	}
+
+	bundle.IndicatorActors = actorLinks
+	bundle.ThreatActors = actors
+	return nil
+}
+
+func (r *BundleRepository) getIndicators(ctx context.Context, ids []string) ([]model.Indicator, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + indicatorColumns + ` FROM indicators i WHERE i.id = ANY($1::UUID[]) AND i.tenant_id = $2 AND i.tlp = ANY($3) ORDER BY i.created_at, i.id`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle indicators: %w", err)
+	}
+	defer rows.Close()
+
+	return scanIndicators(rows)
+}
+
+func (r *BundleRepository) getCampaigns(ctx context.Context, ids []string) ([]model.Campaign, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + campaignColumns + ` FROM campaigns c WHERE c.id = ANY($1::UUID[]) AND c.tenant_id = $2 AND c.tlp = ANY($3) ORDER BY c.created_at, c.id`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []model.Campaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, *campaign)
+	}
+
+	return campaigns, rows.Err()
+}
+
+func (r *BundleRepository) getThreatActors(ctx context.Context, ids []string) ([]model.ThreatActor, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + threatActorColumns + `, ` + actorAliasesColumn + ` FROM threat_actors ta WHERE ta.id = ANY($1::UUID[]) AND ta.tenant_id = $2 ORDER BY ta.name`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle threat actors: %w", err)
+	}
+	defer rows.Close()
+
+	var actors []model.ThreatActor
+	for rows.Next() {
+		var aliases []string
+		actor, err := scanThreatActor(rows, pq.Array(&aliases))
+		if err != nil {
+			return nil, err
+		}
+		actor.Aliases = aliases
+		actors = append(actors, *actor)
+	}
+
+	return actors, rows.Err()
+}
+
+func (r *BundleRepository) getCampaignLinks(ctx context.Context, condition string, arg interface{}) ([]model.IndicatorCampaignLink, error) {
+	query := `
+		SELECT ic.indicator_id, ic.campaign_id, ic.added_at, ic.notes
+		FROM indicator_campaigns ic
+		JOIN indicators i ON i.id = ic.indicator_id
+		JOIN campaigns c ON c.id = ic.campaign_id
+		WHERE ` + condition + ` AND ic.tenant_id = $2 AND i.tlp = ANY($3) AND c.tlp = ANY($3)
+		ORDER BY ic.added_at, ic.indicator_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, arg, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indicator campaign links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []model.IndicatorCampaignLink
+	for rows.Next() {
+		var link model.IndicatorCampaignLink
+		var notes sql.NullString
+		if err := rows.Scan(&link.IndicatorID, &link.CampaignID, &link.AddedAt, &notes); err != nil {
+			return nil, fmt.Errorf("failed to scan indicator campaign link: %w", err)
+		}
+		link.Notes = notes.String
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+func (r *BundleRepository) getActorLinks(ctx context.Context, indicatorIDs []string) ([]model.IndicatorActorLink, error) {
+	if len(indicatorIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT ia.indicator_id, ia.actor_id, ia.attribution_confidence, ia.added_at
+		FROM indicator_actors ia
+		WHERE ia.indicator_id = ANY($1::UUID[]) AND ia.tenant_id = $2
+		ORDER BY ia.added_at, ia.indicator_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(indicatorIDs), tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indicator actor links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []model.IndicatorActorLink
+	for rows.Next() {
+		var link model.IndicatorActorLink
+		if err := rows.Scan(&link.IndicatorID, &link.ActorID, &link.AttributionConfidence, &link.AddedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan indicator actor link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+const campaignColumns = `c.id, c.name, c.description, c.status, c.start_date, c.end_date,
+			   c.target_sectors, c.target_regions, c.threat_actor_id, c.severity,
+			   c.tlp, c.pap, c.metadata, c.created_at, c.updated_at`
+
+func scanCampaign(row rowScanner, extra ...interface{}) (*model.Campaign, error) {
+	var campaign model.Campaign
+	var description, status, severity, targetSectors, targetRegions, threatActorID, metadata sql.NullString
+	var startDate, endDate sql.NullTime
+
+	dest := []interface{}{
+		&campaign.ID, &campaign.Name, &description, &status,
+		&startDate, &endDate, &targetSectors, &targetRegions,
+		&threatActorID, &severity, &campaign.TLP, &campaign.PAP, &metadata,
+		&campaign.CreatedAt, &campaign.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, fmt.Errorf("failed to scan campaign: %w", err)
+	}
+
+	campaign.Description = description.String
+	campaign.Status = status.String
+	campaign.Severity = severity.String
+	campaign.ThreatActorID = threatActorID.String
+	if startDate.Valid {
+		campaign.StartDate = &startDate.Time
+	}
+	if endDate.Valid {
+		campaign.EndDate = &endDate.Time
+	}
+	if targetSectors.Valid {
+		json.Unmarshal([]byte(targetSectors.String), &campaign.TargetSectors)
+	}
+	if targetRegions.Valid {
+		json.Unmarshal([]byte(targetRegions.String), &campaign.TargetRegions)
+	}
+	if metadata.Valid {
+		campaign.Metadata = json.RawMessage(metadata.String)
+	}
+
+	return &campaign, nil
+}
+
+const threatActorColumns = `ta.id, ta.name, ta.description, ta.country, ta.motivation,
+			   ta.first_seen, ta.last_seen, ta.confidence_level,
+			   ta.metadata, ta.created_at, ta.updated_at`
+
+func scanThreatActor(row rowScanner, extra ...interface{}) (*model.ThreatActor, error) {
+	var actor model.ThreatActor
+	var description, country, motivation, metadata sql.NullString
+	var firstSeen, lastSeen sql.NullTime
+	var confidence sql.NullInt64
+
+	dest := []interface{}{
+		&actor.ID, &actor.Name, &description, &country, &motivation,
+		&firstSeen, &lastSeen, &confidence,
+		&metadata, &actor.CreatedAt, &actor.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, fmt.Errorf("failed to scan threat actor: %w", err)
+	}
+
+	actor.Description = description.String
+	actor.Country = country.String
+	actor.Motivation = motivation.String
+	actor.ConfidenceLevel = int(confidence.Int64)
+	if firstSeen.Valid {
+		actor.FirstSeen = &firstSeen.Time
+	}
+	if lastSeen.Valid {
+		actor.LastSeen = &lastSeen.Time
+	}
+	if metadata.Valid {
+		actor.Metadata = json.RawMessage(metadata.String)
+	}
+
+	return &actor, nil
+}
@@ -3,56 +3,225 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 )
 
 type CampaignRepository struct {
 	db *sql.DB
+	sq squirrel.StatementBuilderType
 }
 
 func NewCampaignRepository(db *sql.DB) *CampaignRepository {
-	return &CampaignRepository{db: db}
+	return &CampaignRepository{
+		db: db,
+		sq: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
 }
 
 func (r *CampaignRepository) GetByID(ctx context.Context, id string) (*model.Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns c WHERE c.id = $1 AND c.tenant_id = $2 AND c.tlp = ANY($3)`
+
+	campaign, err := scanCampaign(r.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx))))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+func (r *CampaignRepository) List(ctx context.Context, params model.CampaignListParams) (*model.CampaignListResult, error) {
+	countSQL, countArgs, err := applyCampaignFilters(ctx, r.sq.Select("COUNT(*)").From("campaigns c"), params).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build campaign count query: %w", err)
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count campaigns: %w", err)
+	}
+
+	query := applyCampaignFilters(ctx, r.sq.Select(campaignColumns).Column(`(
+		SELECT COUNT(*) FROM indicator_campaigns ic
+		JOIN indicators i ON i.id = ic.indicator_id
+		WHERE ic.campaign_id = c.id AND i.tlp = ANY(?) AND i.revoked_at IS NULL) AS indicator_count`, pq.Array(allowedTLP(ctx)),
+	).From("campaigns c"), params).
+		OrderBy("c.start_date DESC NULLS LAST", "c.name", "c.id").
+		Limit(uint64(params.Limit)).
+		Offset(uint64((params.Page - 1) * params.Limit))
+
+	querySQL, queryArgs, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build campaign list query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, querySQL, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	campaigns := []model.CampaignWithCount{}
+	for rows.Next() {
+		var count int
+		campaign, err := scanCampaign(rows, &count)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, model.CampaignWithCount{Campaign: *campaign, IndicatorCount: count})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate campaigns: %w", err)
+	}
+
+	return &model.CampaignListResult{
+		Data:       campaigns,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: (total + params.Limit - 1) / params.Limit,
+	}, nil
+}
+
+func applyCampaignFilters(ctx context.Context, query squirrel.SelectBuilder, params model.CampaignListParams) squirrel.SelectBuilder {
+	query = query.Where(squirrel.Eq{"c.tenant_id": tenant.FromContext(ctx), "c.tlp": allowedTLP(ctx)})
+	if params.Status != "" {
+		query = query.Where(squirrel.Eq{"c.status": params.Status})
+	}
+	if params.Severity != "" {
+		query = query.Where(squirrel.Eq{"c.severity": params.Severity})
+	}
+	if params.ThreatActorID != "" {
+		query = query.Where(squirrel.Eq{"c.threat_actor_id": params.ThreatActorID})
+	}
+	if params.Sector != "" {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(c.target_sectors) s WHERE LOWER(s) = LOWER(?))`, params.Sector)
+	}
+	if params.Region != "" {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(c.target_regions) rg WHERE LOWER(rg) = LOWER(?))`, params.Region)
+	}
+	return query
+}
+
+func (r *CampaignRepository) Create(ctx context.Context, campaign *model.Campaign) error {
+	sectors, regions, metadata, err := marshalCampaignJSON(campaign)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		SELECT id, name, description, status, start_date, end_date,
-			   target_sectors, target_regions, threat_actor_id, severity,
-			   created_at, updated_at
-		FROM campaigns
-		WHERE id = $1
+		INSERT INTO campaigns (name, description, status, start_date, end_date,
+			target_sectors, target_regions, threat_actor_id, severity, metadata, tenant_id, tlp, pap)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id, created_at, updated_at
 	`
 
-	var campaign model.Campaign
-	var startDate, endDate sql.NullTime
-	var targetSectors, targetRegions, threatActorID sql.NullString
+	err = r.db.QueryRowContext(ctx, query,
+		campaign.Name, nullString(campaign.Description), campaign.Status, campaign.StartDate, campaign.EndDate,
+		sectors, regions, nullString(campaign.ThreatActorID), campaign.Severity, metadata, tenant.FromContext(ctx),
+		campaign.TLP, campaign.PAP,
+	).Scan(&campaign.ID, &campaign.CreatedAt, &campaign.UpdatedAt)
+	if isForeignKeyViolation(err) {
+		return ErrInvalidReference
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&campaign.ID, &campaign.Name, &campaign.Description, &campaign.Status,
-		&startDate, &endDate, &targetSectors, &targetRegions,
-		&threatActorID, &campaign.Severity, &campaign.CreatedAt, &campaign.UpdatedAt,
-	)
+	return nil
+}
+
+func (r *CampaignRepository) Update(ctx context.Context, campaign *model.Campaign) error {
+	sectors, regions, metadata, err := marshalCampaignJSON(campaign)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE campaigns
+		SET name = $2, description = $3, status = $4, start_date = $5, end_date = $6,
+			target_sectors = $7, target_regions = $8, threat_actor_id = $9, severity = $10,
+			metadata = $11, tlp = $13, pap = $14, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $12 AND tlp = ANY($15)
+		RETURNING created_at, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		campaign.ID, campaign.Name, nullString(campaign.Description), campaign.Status, campaign.StartDate,
+		campaign.EndDate, sectors, regions, nullString(campaign.ThreatActorID), campaign.Severity, metadata,
+		tenant.FromContext(ctx), campaign.TLP, campaign.PAP, pq.Array(allowedTLP(ctx)),
+	).Scan(&campaign.CreatedAt, &campaign.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, ErrNotFound
+		return ErrNotFound
+	}
+	if isForeignKeyViolation(err) {
+		return ErrInvalidReference
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get campaign: %w", err)
+		return fmt.Errorf("failed to update campaign: %w", err)
 	}
 
-	if startDate.Valid {
-		campaign.StartDate = &startDate.Time
+	return nil
+}
+
+func (r *CampaignRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM campaigns WHERE id = $1 AND tenant_id = $2 AND tlp = ANY($3)`,
+		id, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *CampaignRepository) LinkIndicator(ctx context.Context, link *model.IndicatorCampaignLink) (bool, error) {
+	return linkIndicatorCampaign(ctx, r.db, link)
+}
+
+func (r *CampaignRepository) UnlinkIndicator(ctx context.Context, campaignID, indicatorID string) error {
+	return unlinkIndicatorCampaign(ctx, r.db, indicatorID, campaignID)
+}
+
+func marshalCampaignJSON(campaign *model.Campaign) (string, string, string, error) {
+	sectors := campaign.TargetSectors
+	if sectors == nil {
+		sectors = []string{}
+	}
+	sectorsJSON, err := json.Marshal(sectors)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode target sectors: %w", err)
 	}
-	if endDate.Valid {
-		campaign.EndDate = &endDate.Time
+
+	regions := campaign.TargetRegions
+	if regions == nil {
+		regions = []string{}
 	}
-	if threatActorID.Valid {
-		campaign.ThreatActorID = threatActorID.String
+	regionsJSON, err := json.Marshal(regions)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode target regions: %w", err)
 	}
 
-	return &campaign, nil
+	return string(sectorsJSON), string(regionsJSON), jsonObjectOrEmpty(campaign.Metadata), nil
 }
 
 func (r *CampaignRepository) GetIndicatorsTimeline(ctx context.Context, campaignID string, params model.TimelineParams) (*model.CampaignWithTimeline, error) {
@@ -69,14 +238,16 @@ func (r *CampaignRepository) GetIndicatorsTimeline(ctx context.Context, campaign
 	query := fmt.Sprintf(`
 		SELECT
 			DATE_TRUNC('%s', COALESCE(i.first_seen, ic.added_at)) as period,
-			i.id, i.type, i.value
+			i.id, i.type, i.value, COALESCE(i.hash_algo, ''), i.tlp, ic.notes
 		FROM indicators i
 		JOIN indicator_campaigns ic ON ic.indicator_id = i.id
-		WHERE ic.campaign_id = $1
+		WHERE ic.campaign_id = $1 AND i.tenant_id = $2 AND i.tlp = ANY($3) AND i.revoked_at IS NULL
 	`, dateTrunc)
 
-	args := []interface{}{campaignID}
-	argIdx := 2
+	tenantID := tenant.FromContext(ctx)
+	allowed := pq.Array(allowedTLP(ctx))
+	args := []interface{}{campaignID, tenantID, allowed}
+	argIdx := 4
 
 	if params.StartDate != "" {
 		query += fmt.Sprintf(" AND COALESCE(i.first_seen, ic.added_at) >= $%d", argIdx)
@@ -103,10 +274,12 @@ func (r *CampaignRepository) GetIndicatorsTimeline(ctx context.Context, campaign
 	for rows.Next() {
 		var period time.Time
 		var ind model.TimelineIndicator
+		var notes sql.NullString
 
-		if err := rows.Scan(&period, &ind.ID, &ind.Type, &ind.Value); err != nil {
+		if err := rows.Scan(&period, &ind.ID, &ind.Type, &ind.Value, &ind.HashAlgo, &ind.TLP, &notes); err != nil {
 			return nil, fmt.Errorf("failed to scan timeline row: %w", err)
 		}
+		ind.Notes = notes.String
 
 		periodStr := period.Format("2006-01-02")
 		if _, exists := periodMap[periodStr]; !exists {
@@ -130,40 +303,45 @@ func (r *CampaignRepository) GetIndicatorsTimeline(ctx context.Context, campaign
 	summaryQuery := `
 		SELECT
 			COUNT(DISTINCT i.id) as total_indicators,
-			COUNT(DISTINCT CASE WHEN i.type = 'ip' THEN i.id END) as unique_ips,
-			COUNT(DISTINCT CASE WHEN i.type = 'domain' THEN i.id END) as unique_domains,
 			COALESCE(
 				EXTRACT(DAY FROM MAX(COALESCE(i.first_seen, ic.added_at)) - MIN(COALESCE(i.first_seen, ic.added_at))),
 				0
 			)::INTEGER as duration_days
 		FROM indicators i
 		JOIN indicator_campaigns ic ON ic.indicator_id = i.id
-		WHERE ic.campaign_id = $1
+		WHERE ic.campaign_id = $1 AND i.tenant_id = $2 AND i.tlp = ANY($3) AND i.revoked_at IS NULL
 	`
 
 	var summary model.TimelineSummary
-	err = r.db.QueryRowContext(ctx, summaryQuery, campaignID).Scan(
-		&summary.TotalIndicators, &summary.UniqueIPs,
-		&summary.UniqueDomains, &summary.DurationDays,
+	err = r.db.QueryRowContext(ctx, summaryQuery, campaignID, tenantID, allowed).Scan(
+		&summary.TotalIndicators, &summary.DurationDays,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get summary: %w", err)
 	}
 
+	summary.ByType, err = r.campaignTypeCounts(ctx, campaignID, tenantID, allowed)
+	if err != nil {
+		return nil, err
+	}
+	summary.UniqueIPs = summary.ByType[string(model.IndicatorTypeIP)]
+	summary.UniqueDomains = summary.ByType[string(model.IndicatorTypeDomain)]
+
 	var firstSeen, lastSeen sql.NullTime
 	seenQuery := `
 		SELECT MIN(COALESCE(i.first_seen, ic.added_at)), MAX(COALESCE(i.last_seen, ic.added_at))
 		FROM indicators i
 		JOIN indicator_campaigns ic ON ic.indicator_id = i.id
-		WHERE ic.campaign_id = $1
+		WHERE ic.campaign_id = $1 AND i.tenant_id = $2 AND i.tlp = ANY($3) AND i.revoked_at IS NULL
 	`
-	r.db.QueryRowContext(ctx, seenQuery, campaignID).Scan(&firstSeen, &lastSeen)
+	r.db.QueryRowContext(ctx, seenQuery, campaignID, tenantID, allowed).Scan(&firstSeen, &lastSeen)
 
 	campaignDetail := model.CampaignDetail{
 		ID:          campaign.ID,
 		Name:        campaign.Name,
 		Description: campaign.Description,
 		Status:      campaign.Status,
+		TLP:         campaign.TLP,
 	}
 	if firstSeen.Valid {
 		campaignDetail.FirstSeen = &firstSeen.Time
@@ -178,3 +356,37 @@ func (r *CampaignRepository) GetIndicatorsTimeline(ctx context.Context, campaign
 		Summary:  summary,
 	}, nil
 }
+
+// campaignTypeCounts counts the campaign's indicators per registered type, so
+// types without indicators are reported as zero.
+func (r *CampaignRepository) campaignTypeCounts(ctx context.Context, campaignID, tenantID string, allowed interface{}) (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, indicatorType := range model.IndicatorTypes() {
+		counts[string(indicatorType)] = 0
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.type, COUNT(DISTINCT i.id)
+		FROM indicators i
+		JOIN indicator_campaigns ic ON ic.indicator_id = i.id
+		WHERE ic.campaign_id = $1 AND i.tenant_id = $2 AND i.tlp = ANY($3) AND i.revoked_at IS NULL
+		GROUP BY i.type
+	`, campaignID, tenantID, allowed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count campaign indicators by type: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var indicatorType string
+		var count int
+		if err := rows.Scan(&indicatorType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan indicator type count: %w", err)
+		}
+		counts[indicatorType] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate indicator type counts: %w", err)
+	}
+	return counts, nil
+}
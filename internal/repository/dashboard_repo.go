@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/lib/pq"
 )
 
 type DashboardRepository struct {
@@ -33,15 +35,21 @@ func (r *DashboardRepository) GetSummary(ctx context.Context, timeRange string)
 		IndicatorDistribution: make(map[string]int),
 		TopThreatActors:       []model.ThreatActorWithCount{},
 	}
+	for _, indicatorType := range model.IndicatorTypes() {
+		summary.NewIndicators[string(indicatorType)] = 0
+		summary.IndicatorDistribution[string(indicatorType)] = 0
+	}
 
 	newIndicatorsQuery := fmt.Sprintf(`
 		SELECT type, COUNT(*) as count
 		FROM indicators
-		WHERE created_at >= NOW() - INTERVAL '%s'
+		WHERE tenant_id = $1 AND tlp = ANY($2) AND revoked_at IS NULL AND created_at >= NOW() - INTERVAL '%s'
 		GROUP BY type
 	`, interval)
 
-	rows, err := r.db.QueryContext(ctx, newIndicatorsQuery)
+	tenantID := tenant.FromContext(ctx)
+	allowed := pq.Array(allowedTLP(ctx))
+	rows, err := r.db.QueryContext(ctx, newIndicatorsQuery, tenantID, allowed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get new indicators: %w", err)
 	}
@@ -56,8 +64,8 @@ func (r *DashboardRepository) GetSummary(ctx context.Context, timeRange string)
 		summary.NewIndicators[indicatorType] = count
 	}
 
-	activeCampaignsQuery := `SELECT COUNT(*) FROM campaigns WHERE status = 'active'`
-	if err := r.db.QueryRowContext(ctx, activeCampaignsQuery).Scan(&summary.ActiveCampaigns); err != nil {
+	activeCampaignsQuery := `SELECT COUNT(*) FROM campaigns WHERE tenant_id = $1 AND tlp = ANY($2) AND status = 'active'`
+	if err := r.db.QueryRowContext(ctx, activeCampaignsQuery, tenantID, allowed).Scan(&summary.ActiveCampaigns); err != nil {
 		return nil, fmt.Errorf("failed to get active campaigns: %w", err)
 	}
 
@@ -65,9 +73,11 @@ func (r *DashboardRepository) GetSummary(ctx context.Context, timeRange string)
 		SELECT ta.id, ta.name, ta.description, ta.country, ta.motivation,
 			   ta.first_seen, ta.last_seen, ta.confidence_level,
 			   ta.created_at, ta.updated_at,
-			   COUNT(DISTINCT ia.indicator_id) as indicator_count
+			   COUNT(DISTINCT i.id) as indicator_count
 		FROM threat_actors ta
 		LEFT JOIN indicator_actors ia ON ia.actor_id = ta.id
+		LEFT JOIN indicators i ON i.id = ia.indicator_id AND i.tlp = ANY($2) AND i.revoked_at IS NULL
+		WHERE ta.tenant_id = $1
 		GROUP BY ta.id, ta.name, ta.description, ta.country, ta.motivation,
 				 ta.first_seen, ta.last_seen, ta.confidence_level,
 				 ta.created_at, ta.updated_at
@@ -75,7 +85,7 @@ func (r *DashboardRepository) GetSummary(ctx context.Context, timeRange string)
 		LIMIT 5
 	`
 
-	actorRows, err := r.db.QueryContext(ctx, topActorsQuery)
+	actorRows, err := r.db.QueryContext(ctx, topActorsQuery, tenantID, allowed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get top actors: %w", err)
 	}
@@ -113,9 +123,9 @@ func (r *DashboardRepository) GetSummary(ctx context.Context, timeRange string)
 		summary.TopThreatActors = append(summary.TopThreatActors, actor)
 	}
 
-	distributionQuery := `SELECT type, COUNT(*) as count FROM indicators GROUP BY type`
+	distributionQuery := `SELECT type, COUNT(*) as count FROM indicators WHERE tenant_id = $1 AND tlp = ANY($2) AND revoked_at IS NULL GROUP BY type`
 
-	distRows, err := r.db.QueryContext(ctx, distributionQuery)
+	distRows, err := r.db.QueryContext(ctx, distributionQuery, tenantID, allowed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get distribution: %w", err)
 	}
@@ -1,7 +1,25 @@
 package repository
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
 
 var (
-	ErrNotFound = errors.New("resource not found")
+	ErrNotFound         = errors.New("resource not found")
+	ErrConflict         = errors.New("resource already exists")
+	ErrInvalidReference = errors.New("referenced resource does not exist")
+	ErrFalsePositive    = errors.New("resource was revoked as a false positive")
+	ErrNotWritable      = errors.New("resource cannot be written")
 )
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/lib/pq"
+)
+
+const bulkStagingTable = "indicator_staging"
+
+func (r *IndicatorRepository) BulkUpsert(ctx context.Context, indicators []model.BulkIndicator, force bool) (*model.BulkResult, error) {
+	result := &model.BulkResult{Errors: []model.BulkError{}}
+	if len(indicators) == 0 {
+		return result, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin bulk transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	createStaging := `
+		CREATE TEMP TABLE ` + bulkStagingTable + ` (LIKE indicators INCLUDING DEFAULTS INCLUDING GENERATED) ON COMMIT DROP;
+		ALTER TABLE ` + bulkStagingTable + `
+			ADD COLUMN line INTEGER NOT NULL,
+			ADD COLUMN campaign_ids JSONB NOT NULL DEFAULT '[]',
+			ADD COLUMN actor_ids JSONB NOT NULL DEFAULT '[]'
+	`
+	if _, err := tx.ExecContext(ctx, createStaging); err != nil {
+		return nil, fmt.Errorf("failed to create staging table: %w", err)
+	}
+
+	if err := copyIntoStaging(ctx, tx, indicators); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `CREATE INDEX ON `+bulkStagingTable+` (type, normalized_value)`); err != nil {
+		return nil, fmt.Errorf("failed to index staging table: %w", err)
+	}
+
+	tenantID := tenant.FromContext(ctx)
+	for _, ref := range []struct{ column, table, reason string }{
+		{"campaign_ids", "campaigns", "unknown campaign id"},
+		{"actor_ids", "threat_actors", "unknown threat actor id"},
+	} {
+		rejected, err := rejectUnknownReferences(ctx, tx, ref.column, ref.table, ref.reason, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		result.Errors = append(result.Errors, rejected...)
+	}
+
+	hidden, err := rejectHiddenConflicts(ctx, tx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	result.Errors = append(result.Errors, hidden...)
+
+	if !force {
+		falsePositives, err := rejectFalsePositives(ctx, tx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		result.Errors = append(result.Errors, falsePositives...)
+	}
+
+	mergeQuery := `
+		WITH merged AS (
+			SELECT s.type,
+				   (array_agg(s.value ORDER BY s.line))[1] AS value,
+				   (array_agg(s.description ORDER BY s.line DESC) FILTER (WHERE s.description IS NOT NULL))[1] AS description,
+				   (array_agg(s.severity ORDER BY array_position(ARRAY['low', 'medium', 'high', 'critical']::VARCHAR[], s.severity) DESC))[1] AS severity,
+				   MAX(s.confidence) AS confidence,
+				   MIN(s.first_seen) AS first_seen,
+				   MAX(s.last_seen) AS last_seen,
+				   bool_or(s.is_active) AS is_active,
+				   (array_agg(s.tlp ORDER BY marking_rank(s.tlp) DESC))[1] AS tlp,
+				   (array_agg(s.pap ORDER BY marking_rank(s.pap) DESC))[1] AS pap,
+				   COALESCE(jsonb_agg(DISTINCT t.tag) FILTER (WHERE t.tag IS NOT NULL), '[]'::jsonb) AS tags,
+				   (array_agg(s.metadata ORDER BY s.line DESC))[1] AS metadata,
+				   (array_agg(s.source ORDER BY s.line) FILTER (WHERE s.source IS NOT NULL))[1] AS source,
+				   (array_agg(s.host ORDER BY s.line))[1] AS host,
+				   (array_agg(s.registered_domain ORDER BY s.line))[1] AS registered_domain,
+				   (array_agg(s.tld ORDER BY s.line))[1] AS tld,
+				   (array_agg(s.hash_algo ORDER BY s.line))[1] AS hash_algo
+			FROM ` + bulkStagingTable + ` s
+			LEFT JOIN LATERAL jsonb_array_elements_text(s.tags) AS t(tag) ON TRUE
+			GROUP BY s.type, s.normalized_value
+		), upserted AS (
+			INSERT INTO indicators AS i (type, value, description, severity, confidence,
+				first_seen, last_seen, is_active, tags, metadata, source, tenant_id, tlp, pap,
+				host, registered_domain, tld, hash_algo)
+			SELECT type, value, description, severity, confidence,
+				   first_seen, last_seen, is_active, tags, metadata, source, $1, tlp, pap,
+				   host, registered_domain, tld, hash_algo
+			FROM merged
+			ON CONFLICT (tenant_id, type, normalized_value) DO UPDATE SET ` + indicatorMergeAssignments + `
+			RETURNING i.id, i.type, i.normalized_value, (xmax = 0) AS inserted
+		), campaign_links AS (
+			INSERT INTO indicator_campaigns (indicator_id, campaign_id, tenant_id)
+			SELECT DISTINCT u.id, c.id::UUID, $1
+			FROM upserted u
+			JOIN ` + bulkStagingTable + ` s ON s.type = u.type AND s.normalized_value = u.normalized_value
+			CROSS JOIN LATERAL jsonb_array_elements_text(s.campaign_ids) AS c(id)
+			ON CONFLICT (indicator_id, campaign_id) DO NOTHING
+		), actor_links AS (
+			INSERT INTO indicator_actors (indicator_id, actor_id, tenant_id)
+			SELECT DISTINCT u.id, a.id::UUID, $1
+			FROM upserted u
+			JOIN ` + bulkStagingTable + ` s ON s.type = u.type AND s.normalized_value = u.normalized_value
+			CROSS JOIN LATERAL jsonb_array_elements_text(s.actor_ids) AS a(id)
+			ON CONFLICT (indicator_id, actor_id) DO NOTHING
+		)
+		SELECT COALESCE(array_agg(id) FILTER (WHERE inserted), '{}'),
+			   COALESCE(array_agg(id) FILTER (WHERE NOT inserted), '{}')
+		FROM upserted
+	`
+	var created, updated pq.StringArray
+	if err := tx.QueryRowContext(ctx, mergeQuery, tenantID).Scan(&created, &updated); err != nil {
+		return nil, fmt.Errorf("failed to merge staged indicators: %w", err)
+	}
+	result.Affected = model.AffectedIDs{Created: created, Updated: updated}
+	result.Inserted, result.Updated = len(created), len(updated)
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit bulk transaction: %w", err)
+	}
+
+	result.Rejected = len(result.Errors)
+	return result, nil
+}
+
+func copyIntoStaging(ctx context.Context, tx *sql.Tx, indicators []model.BulkIndicator) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(bulkStagingTable,
+		"line", "type", "value", "description", "severity", "confidence",
+		"first_seen", "last_seen", "is_active", "tags", "metadata", "source",
+		"tlp", "pap", "host", "registered_domain", "tld", "hash_algo", "campaign_ids", "actor_ids",
+	))
+	if err != nil {
+		return fmt.Errorf("failed to prepare copy: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, item := range indicators {
+		ind := item.Indicator
+
+		tags, metadata, err := marshalIndicatorJSON(&ind)
+		if err != nil {
+			return err
+		}
+		campaignIDs, err := marshalIDs(item.CampaignIDs)
+		if err != nil {
+			return err
+		}
+		actorIDs, err := marshalIDs(item.ThreatActorIDs)
+		if err != nil {
+			return err
+		}
+
+		host, registeredDomain, tld := hostColumns(&ind)
+
+		if _, err := stmt.ExecContext(ctx,
+			item.Line, ind.Type, ind.Value, nullString(ind.Description), ind.Severity, ind.Confidence,
+			ind.FirstSeen, ind.LastSeen, ind.IsActive, tags, metadata, nullString(ind.Source),
+			ind.TLP, ind.PAP, host, registeredDomain, tld, nullString(ind.HashAlgo), campaignIDs, actorIDs,
+		); err != nil {
+			return fmt.Errorf("failed to copy indicator on line %d: %w", item.Line, err)
+		}
+	}
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush copy: %w", err)
+	}
+
+	return nil
+}
+
+// Campaigns above the caller's clearance count as unknown, as they do when
+// linking a single indicator; threat actors carry no marking.
+func rejectUnknownReferences(ctx context.Context, tx *sql.Tx, column, table, reason, tenantID string) ([]model.BulkError, error) {
+	args := []interface{}{tenantID}
+	visible := ""
+	if table == "campaigns" {
+		args = append(args, pq.Array(allowedTLP(ctx)))
+		visible = " AND t.tlp = ANY($2)"
+	}
+
+	query := fmt.Sprintf(`
+		DELETE FROM %[1]s s
+		WHERE EXISTS (
+			SELECT 1
+			FROM jsonb_array_elements_text(s.%[2]s) AS ref(id)
+			WHERE NOT EXISTS (SELECT 1 FROM %[3]s t WHERE t.id = ref.id::UUID AND t.tenant_id = $1%[4]s)
+		)
+		RETURNING s.line, s.value
+	`, bulkStagingTable, column, table, visible)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s references: %w", table, err)
+	}
+	defer rows.Close()
+
+	var rejected []model.BulkError
+	for rows.Next() {
+		bulkErr := model.BulkError{Reason: reason}
+		if err := rows.Scan(&bulkErr.Line, &bulkErr.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan rejected line: %w", err)
+		}
+		rejected = append(rejected, bulkErr)
+	}
+
+	return rejected, rows.Err()
+}
+
+func rejectHiddenConflicts(ctx context.Context, tx *sql.Tx, tenantID string) ([]model.BulkError, error) {
+	query := `
+		DELETE FROM ` + bulkStagingTable + ` s
+		USING indicators i
+		WHERE i.tenant_id = $1 AND i.type = s.type AND i.normalized_value = s.normalized_value
+		  AND NOT (i.tlp = ANY($2))
+		RETURNING s.line, s.value
+	`
+
+	rows, err := tx.QueryContext(ctx, query, tenantID, pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check indicator markings: %w", err)
+	}
+	defer rows.Close()
+
+	var rejected []model.BulkError
+	for rows.Next() {
+		bulkErr := model.BulkError{Reason: "cannot be written"}
+		if err := rows.Scan(&bulkErr.Line, &bulkErr.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan rejected line: %w", err)
+		}
+		rejected = append(rejected, bulkErr)
+	}
+
+	return rejected, rows.Err()
+}
+
+func rejectFalsePositives(ctx context.Context, tx *sql.Tx, tenantID string) ([]model.BulkError, error) {
+	query := `
+		DELETE FROM ` + bulkStagingTable + ` s
+		USING indicators i
+		WHERE i.tenant_id = $1 AND i.type = s.type AND i.normalized_value = s.normalized_value
+		  AND i.revocation_reason = 'false_positive'
+		RETURNING s.line, s.value
+	`
+
+	rows, err := tx.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check revoked indicators: %w", err)
+	}
+	defer rows.Close()
+
+	var rejected []model.BulkError
+	for rows.Next() {
+		bulkErr := model.BulkError{Reason: "matches an indicator revoked as a false positive"}
+		if err := rows.Scan(&bulkErr.Line, &bulkErr.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan rejected line: %w", err)
+		}
+		rejected = append(rejected, bulkErr)
+	}
+
+	return rejected, rows.Err()
+}
+
+func marshalIDs(ids []string) (string, error) {
+	if ids == nil {
+		ids = []string{}
+	}
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode ids: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+const (
+	exportCursorName = "indicator_export"
+	exportFetchSize  = 1000
+)
+
+const attributionColumns = `
+	COALESCE((SELECT array_agg(c.name ORDER BY c.name) FROM indicator_campaigns ic
+		JOIN campaigns c ON c.id = ic.campaign_id WHERE ic.indicator_id = i.id AND c.tlp = ANY(?)), '{}') AS campaign_names,
+	COALESCE((SELECT array_agg(ta.name ORDER BY ta.name) FROM indicator_actors ia
+		JOIN threat_actors ta ON ta.id = ia.actor_id WHERE ia.indicator_id = i.id), '{}') AS threat_actor_names`
+
+func (r *IndicatorRepository) StreamSearch(ctx context.Context, params model.SearchParams, fn func(*model.Indicator) error) error {
+	return r.streamCursor(ctx, r.exportQuery(ctx, params, indicatorColumns), func(rows *sql.Rows) error {
+		ind, err := scanIndicator(rows)
+		if err != nil {
+			return err
+		}
+		return fn(ind)
+	})
+}
+
+func (r *IndicatorRepository) StreamAttributed(ctx context.Context, params model.SearchParams, fn func(*model.AttributedIndicator) error) error {
+	query := r.exportQuery(ctx, params, indicatorColumns).Column(attributionColumns, pq.Array(allowedTLP(ctx)))
+	return r.streamCursor(ctx, query, func(rows *sql.Rows) error {
+		var campaigns, actors []string
+		ind, err := scanIndicator(rows, pq.Array(&campaigns), pq.Array(&actors))
+		if err != nil {
+			return err
+		}
+		return fn(&model.AttributedIndicator{Indicator: *ind, Campaigns: campaigns, ThreatActors: actors})
+	})
+}
+
+func (r *IndicatorRepository) streamCursor(ctx context.Context, builder squirrel.SelectBuilder, scan func(*sql.Rows) error) error {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build export query: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin export transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DECLARE `+exportCursorName+` NO SCROLL CURSOR FOR `+query, args...); err != nil {
+		return fmt.Errorf("failed to declare export cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf(`FETCH FORWARD %d FROM %s`, exportFetchSize, exportCursorName)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		fetched, err := fetchExportBatch(ctx, tx, fetch, scan)
+		if err != nil {
+			return err
+		}
+		if fetched < exportFetchSize {
+			return nil
+		}
+	}
+}
+
+func fetchExportBatch(ctx context.Context, tx *sql.Tx, fetch string, scan func(*sql.Rows) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch export rows: %w", err)
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		fetched++
+		if err := scan(rows); err != nil {
+			return fetched, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fetched, fmt.Errorf("failed to iterate export rows: %w", err)
+	}
+
+	return fetched, nil
+}
+
+func (r *IndicatorRepository) exportQuery(ctx context.Context, params model.SearchParams, columns string) squirrel.SelectBuilder {
+	query := r.sq.Select(columns).From("indicators i").
+		Where(squirrel.Eq{"i.tenant_id": tenant.FromContext(ctx), "i.tlp": allowedTLP(ctx)})
+
+	if params.Type != "" {
+		query = query.Where(squirrel.Eq{"i.type": params.Type})
+	}
+	if params.Value != "" {
+		query = query.Where(squirrel.ILike{"i.value": "%" + params.Value + "%"})
+	}
+	if params.ThreatActorID != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM indicator_actors ia WHERE ia.indicator_id = i.id AND ia.actor_id = ?)`, params.ThreatActorID)
+	}
+	if params.CampaignID != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM indicator_campaigns ic WHERE ic.indicator_id = i.id AND ic.campaign_id = ?)`, params.CampaignID)
+	}
+	if params.FirstSeenAfter != "" {
+		query = query.Where(squirrel.GtOrEq{"i.first_seen": params.FirstSeenAfter})
+	}
+	if params.LastSeenBefore != "" {
+		query = query.Where(squirrel.LtOrEq{"i.last_seen": params.LastSeenBefore})
+	}
+	if len(params.Types) > 0 {
+		query = query.Where(squirrel.Eq{"i.type": params.Types})
+	}
+	if params.ActiveOnly {
+		query = query.Where(squirrel.Eq{"i.is_active": true})
+	}
+	query = applyThresholdFilters(query, params)
+	query = applyTextSearch(ctx, query, params)
+
+	return query.OrderBy("i.created_at", "i.id")
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/lib/pq"
+)
+
+const indicatorHistoryColumns = `h.indicator_id, h.type, h.value, h.description, h.severity, h.confidence,
+			   h.first_seen, h.last_seen, h.is_active, h.tlp, h.pap, h.tags, h.metadata, h.source,
+			   h.created_at, h.updated_at, h.revoked_at, h.revocation_reason, h.hash_algo`
+
+// An indicator's history is only visible while its latest version is, so
+// raising the marking also hides the versions recorded before.
+const indicatorHistoryVisible = `h.tenant_id = $2 AND h.tlp = ANY($3) AND (
+			SELECT l.tlp FROM indicator_history l
+			WHERE l.indicator_id = h.indicator_id
+			ORDER BY l.version DESC
+			LIMIT 1
+		) = ANY($3)`
+
+func (r *IndicatorRepository) History(ctx context.Context, id string, params model.IndicatorHistoryParams) (*model.IndicatorHistoryResult, error) {
+	tenantID := tenant.FromContext(ctx)
+	allowed := pq.Array(allowedTLP(ctx))
+
+	countQuery := `SELECT COUNT(*) FROM indicator_history h WHERE h.indicator_id = $1 AND ` + indicatorHistoryVisible
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, countQuery, id, tenantID, allowed).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count indicator history: %w", err)
+	}
+	if total == 0 {
+		return nil, ErrNotFound
+	}
+
+	query := `
+		SELECT ` + indicatorHistoryColumns + `, h.version, h.operation, h.valid_from
+		FROM indicator_history h
+		WHERE h.indicator_id = $1 AND ` + indicatorHistoryVisible + `
+		ORDER BY h.version DESC
+		LIMIT $4 OFFSET $5
+	`
+	rows, err := r.db.QueryContext(ctx, query, id, tenantID, allowed, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indicator history: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []model.IndicatorVersion{}
+	for rows.Next() {
+		var version model.IndicatorVersion
+		ind, err := scanIndicator(rows, &version.Version, &version.Operation, &version.ValidFrom)
+		if err != nil {
+			return nil, err
+		}
+		version.Indicator = *ind
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate indicator history: %w", err)
+	}
+
+	return &model.IndicatorHistoryResult{
+		Data:       versions,
+		Total:      total,
+		Page:       params.Page,
+		Limit:      params.Limit,
+		TotalPages: (total + params.Limit - 1) / params.Limit,
+	}, nil
+}
+
+func (r *IndicatorRepository) GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.IndicatorWithRelations, error) {
+	tenantID := tenant.FromContext(ctx)
+	allowed := pq.Array(allowedTLP(ctx))
+
+	query := `
+		SELECT ` + indicatorHistoryColumns + `
+		FROM (
+			SELECT *
+			FROM indicator_history
+			WHERE indicator_id = $1 AND tenant_id = $2 AND valid_from <= $4
+			ORDER BY version DESC
+			LIMIT 1
+		) h
+		WHERE h.operation <> 'delete' AND ` + indicatorHistoryVisible
+
+	ind, err := scanIndicator(r.db.QueryRowContext(ctx, query, id, tenantID, allowed, asOf))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	indicator := &model.IndicatorWithRelations{Indicator: *ind}
+
+	actorQuery := `
+		SELECT ta.id, ta.name, ah.attribution_confidence
+		FROM indicator_actor_history ah
+		JOIN threat_actors ta ON ta.id = ah.actor_id
+		WHERE ah.indicator_id = $1 AND ah.tenant_id = $2
+		  AND ah.valid_from <= $3 AND (ah.valid_to IS NULL OR ah.valid_to > $3)
+	`
+	actorRows, err := r.db.QueryContext(ctx, actorQuery, id, tenantID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get threat actors: %w", err)
+	}
+	defer actorRows.Close()
+
+	for actorRows.Next() {
+		var actor model.ThreatActorSummary
+		if err := actorRows.Scan(&actor.ID, &actor.Name, &actor.Confidence); err != nil {
+			return nil, fmt.Errorf("failed to scan threat actor: %w", err)
+		}
+		indicator.ThreatActors = append(indicator.ThreatActors, actor)
+	}
+	if err := actorRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate threat actors: %w", err)
+	}
+
+	campaignQuery := `
+		SELECT c.id, c.name, c.status = 'active' as active
+		FROM indicator_campaign_history ch
+		JOIN campaigns c ON c.id = ch.campaign_id
+		WHERE ch.indicator_id = $1 AND ch.tenant_id = $2 AND c.tlp = ANY($3)
+		  AND ch.valid_from <= $4 AND (ch.valid_to IS NULL OR ch.valid_to > $4)
+	`
+	campRows, err := r.db.QueryContext(ctx, campaignQuery, id, tenantID, allowed, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaigns: %w", err)
+	}
+	defer campRows.Close()
+
+	for campRows.Next() {
+		var campaign model.CampaignSummary
+		if err := campRows.Scan(&campaign.ID, &campaign.Name, &campaign.Active); err != nil {
+			return nil, fmt.Errorf("failed to scan campaign: %w", err)
+		}
+		indicator.Campaigns = append(indicator.Campaigns, campaign)
+	}
+	if err := campRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate campaigns: %w", err)
+	}
+
+	return indicator, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/hostname"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+const hostBackfillBatchSize = 500
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func hostColumns(indicator *model.Indicator) (sql.NullString, sql.NullString, sql.NullString) {
+	parts := hostname.Parse(indicator.Type, indicator.Value)
+	return nullString(parts.Host), nullString(parts.RegisteredDomain), nullString(parts.TLD)
+}
+
+// Matching reversed hosts by prefix lets idx_indicators_host_reversed serve
+// suffix lookups; the leading dot keeps "example.com" from matching
+// "badexample.com".
+func applyHostFilters(query squirrel.SelectBuilder, params model.SearchParams) squirrel.SelectBuilder {
+	if params.DomainSuffix != "" {
+		query = query.Where(squirrel.Or{
+			squirrel.Eq{"i.host": params.DomainSuffix},
+			squirrel.Expr("reverse(i.host) LIKE ?", likeEscaper.Replace(reverse("."+params.DomainSuffix))+"%"),
+		})
+	}
+	if params.RegisteredDomain != "" {
+		query = query.Where(squirrel.Eq{"i.registered_domain": params.RegisteredDomain})
+	}
+	if params.TLD != "" {
+		query = query.Where(squirrel.Eq{"i.tld": params.TLD})
+	}
+	return query
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// BackfillHosts fills the host columns of domain and URL indicators stored
+// before they existed. It runs across every tenant at startup, so it is not
+// scoped by the request context.
+func (r *IndicatorRepository) BackfillHosts(ctx context.Context) (int, error) {
+	filled := 0
+	lastID := "00000000-0000-0000-0000-000000000000"
+
+	for {
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT id, type, value
+			FROM indicators
+			WHERE type IN ('domain', 'url') AND host IS NULL AND id > $1
+			ORDER BY id
+			LIMIT $2
+		`, lastID, hostBackfillBatchSize)
+		if err != nil {
+			return filled, fmt.Errorf("failed to list indicators without host: %w", err)
+		}
+
+		var ids, hosts, registeredDomains, tlds []string
+		count := 0
+		for rows.Next() {
+			var ind model.Indicator
+			if err := rows.Scan(&ind.ID, &ind.Type, &ind.Value); err != nil {
+				rows.Close()
+				return filled, fmt.Errorf("failed to scan indicator: %w", err)
+			}
+			count++
+			lastID = ind.ID
+
+			parts := hostname.Parse(ind.Type, ind.Value)
+			if parts.Host == "" {
+				continue
+			}
+			ids = append(ids, ind.ID)
+			hosts = append(hosts, parts.Host)
+			registeredDomains = append(registeredDomains, parts.RegisteredDomain)
+			tlds = append(tlds, parts.TLD)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return filled, fmt.Errorf("failed to iterate indicators: %w", err)
+		}
+
+		if len(ids) > 0 {
+			result, err := r.db.ExecContext(ctx, `
+				UPDATE indicators i
+				SET host = v.host, registered_domain = NULLIF(v.registered_domain, ''), tld = v.tld
+				FROM unnest($1::UUID[], $2::TEXT[], $3::TEXT[], $4::TEXT[]) AS v(id, host, registered_domain, tld)
+				WHERE i.id = v.id
+			`, pq.Array(ids), pq.Array(hosts), pq.Array(registeredDomains), pq.Array(tlds))
+			if err != nil {
+				return filled, fmt.Errorf("failed to backfill indicator hosts: %w", err)
+			}
+			updated, _ := result.RowsAffected()
+			filled += int(updated)
+		}
+
+		if count < hostBackfillBatchSize {
+			return filled, nil
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/lib/pq"
+)
+
+func (r *IndicatorRepository) LinkActor(ctx context.Context, link *model.IndicatorActorLink) (bool, error) {
+	query := `
+		INSERT INTO indicator_actors (indicator_id, actor_id, attribution_confidence, tenant_id)
+		SELECT i.id, $2::UUID, $3::INTEGER, i.tenant_id
+		FROM indicators i
+		WHERE i.id = $1 AND i.tenant_id = $4 AND i.tlp = ANY($5)
+		ON CONFLICT (indicator_id, actor_id) DO UPDATE SET
+			attribution_confidence = EXCLUDED.attribution_confidence
+		WHERE indicator_actors.tenant_id = EXCLUDED.tenant_id
+		RETURNING added_at, (xmax = 0) AS inserted
+	`
+
+	var inserted bool
+	err := r.db.QueryRowContext(ctx, query, link.IndicatorID, link.ActorID, link.AttributionConfidence, tenant.FromContext(ctx),
+		pq.Array(allowedTLP(ctx))).
+		Scan(&link.AddedAt, &inserted)
+	if err == sql.ErrNoRows || isForeignKeyViolation(err) {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to link threat actor to indicator: %w", err)
+	}
+
+	return inserted, nil
+}
+
+func (r *IndicatorRepository) UnlinkActor(ctx context.Context, indicatorID, actorID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM indicator_actors ia USING indicators i
+		WHERE ia.indicator_id = $1 AND ia.actor_id = $2 AND ia.tenant_id = $3
+			AND i.id = ia.indicator_id AND i.tlp = ANY($4)`,
+		indicatorID, actorID, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return fmt.Errorf("failed to unlink threat actor from indicator: %w", err)
+	}
+
+	return requireAffected(result, "failed to unlink threat actor from indicator")
+}
+
+func (r *IndicatorRepository) LinkCampaign(ctx context.Context, link *model.IndicatorCampaignLink) (bool, error) {
+	return linkIndicatorCampaign(ctx, r.db, link)
+}
+
+func (r *IndicatorRepository) UnlinkCampaign(ctx context.Context, indicatorID, campaignID string) error {
+	return unlinkIndicatorCampaign(ctx, r.db, indicatorID, campaignID)
+}
+
+func linkIndicatorCampaign(ctx context.Context, db *sql.DB, link *model.IndicatorCampaignLink) (bool, error) {
+	query := `
+		INSERT INTO indicator_campaigns (indicator_id, campaign_id, notes, tenant_id)
+		SELECT i.id, c.id, $3::TEXT, i.tenant_id
+		FROM indicators i
+		JOIN campaigns c ON c.id = $2 AND c.tenant_id = i.tenant_id AND c.tlp = ANY($5)
+		WHERE i.id = $1 AND i.tenant_id = $4 AND i.tlp = ANY($5)
+		ON CONFLICT (indicator_id, campaign_id) DO UPDATE SET notes = EXCLUDED.notes
+		WHERE indicator_campaigns.tenant_id = EXCLUDED.tenant_id
+		RETURNING added_at, (xmax = 0) AS inserted
+	`
+
+	var inserted bool
+	err := db.QueryRowContext(ctx, query, link.IndicatorID, link.CampaignID, nullString(link.Notes), tenant.FromContext(ctx),
+		pq.Array(allowedTLP(ctx))).
+		Scan(&link.AddedAt, &inserted)
+	if err == sql.ErrNoRows || isForeignKeyViolation(err) {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to link indicator to campaign: %w", err)
+	}
+
+	return inserted, nil
+}
+
+func unlinkIndicatorCampaign(ctx context.Context, db *sql.DB, indicatorID, campaignID string) error {
+	result, err := db.ExecContext(ctx,
+		`DELETE FROM indicator_campaigns ic USING indicators i, campaigns c
+		WHERE ic.indicator_id = $1 AND ic.campaign_id = $2 AND ic.tenant_id = $3
+			AND i.id = ic.indicator_id AND c.id = ic.campaign_id
+			AND i.tlp = ANY($4) AND c.tlp = ANY($4)`,
+		indicatorID, campaignID, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return fmt.Errorf("failed to unlink indicator from campaign: %w", err)
+	}
+
+	return requireAffected(result, "failed to unlink indicator from campaign")
+}
+
+func requireAffected(result sql.Result, message string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
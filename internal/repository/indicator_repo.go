@@ -4,12 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
 	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 )
 
 type IndicatorRepository struct {
@@ -28,21 +32,24 @@ func (r *IndicatorRepository) GetByID(ctx context.Context, id string) (*model.In
 	query := `
 		SELECT
 			i.id, i.type, i.value, i.description, i.severity,
-			i.confidence, i.first_seen, i.last_seen, i.is_active,
-			i.tags, i.metadata, i.source, i.created_at, i.updated_at
+			i.confidence, i.first_seen, i.last_seen, i.is_active, i.tlp, i.pap,
+			i.tags, i.metadata, i.source, i.created_at, i.updated_at,
+			i.revoked_at, i.revocation_reason, i.hash_algo, i.sample_id
 		FROM indicators i
-		WHERE i.id = $1
+		WHERE i.id = $1 AND i.tenant_id = $2 AND i.tlp = ANY($3)
 	`
 
+	tenantID := tenant.FromContext(ctx)
+	allowed := pq.Array(allowedTLP(ctx))
 	var indicator model.IndicatorWithRelations
-	var description, severity, tags, metadata, source sql.NullString
-	var firstSeen, lastSeen sql.NullTime
+	var description, severity, tags, metadata, source, revocationReason, hashAlgo, sampleID sql.NullString
+	var firstSeen, lastSeen, revokedAt sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id, tenantID, allowed).Scan(
 		&indicator.ID, &indicator.Type, &indicator.Value, &description,
 		&severity, &indicator.Confidence, &firstSeen, &lastSeen,
-		&indicator.IsActive, &tags, &metadata, &source,
-		&indicator.CreatedAt, &indicator.UpdatedAt,
+		&indicator.IsActive, &indicator.TLP, &indicator.PAP, &tags, &metadata, &source,
+		&indicator.CreatedAt, &indicator.UpdatedAt, &revokedAt, &revocationReason, &hashAlgo, &sampleID,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
@@ -69,14 +76,23 @@ func (r *IndicatorRepository) GetByID(ctx context.Context, id string) (*model.In
 	if tags.Valid {
 		json.Unmarshal([]byte(tags.String), &indicator.Tags)
 	}
+	if metadata.Valid {
+		indicator.Metadata = json.RawMessage(metadata.String)
+	}
+	if revokedAt.Valid {
+		indicator.RevokedAt = &revokedAt.Time
+	}
+	indicator.RevocationReason = revocationReason.String
+	indicator.HashAlgo = hashAlgo.String
+	indicator.SampleID = sampleID.String
 
 	actorQuery := `
 		SELECT ta.id, ta.name, ia.attribution_confidence
 		FROM threat_actors ta
 		JOIN indicator_actors ia ON ia.actor_id = ta.id
-		WHERE ia.indicator_id = $1
+		WHERE ia.indicator_id = $1 AND ia.tenant_id = $2
 	`
-	actorRows, err := r.db.QueryContext(ctx, actorQuery, id)
+	actorRows, err := r.db.QueryContext(ctx, actorQuery, id, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get threat actors: %w", err)
 	}
@@ -94,9 +110,9 @@ func (r *IndicatorRepository) GetByID(ctx context.Context, id string) (*model.In
 		SELECT c.id, c.name, c.status = 'active' as active
 		FROM campaigns c
 		JOIN indicator_campaigns ic ON ic.campaign_id = c.id
-		WHERE ic.indicator_id = $1
+		WHERE ic.indicator_id = $1 AND ic.tenant_id = $2 AND c.tlp = ANY($3)
 	`
-	campRows, err := r.db.QueryContext(ctx, campaignQuery, id)
+	campRows, err := r.db.QueryContext(ctx, campaignQuery, id, tenantID, allowed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get campaigns: %w", err)
 	}
@@ -113,18 +129,21 @@ func (r *IndicatorRepository) GetByID(ctx context.Context, id string) (*model.In
 	relatedQuery := `
 		SELECT i.id, i.type, i.value, 'same_campaign' as relationship
 		FROM indicators i
-		WHERE i.id IN (
+		WHERE i.tenant_id = $2 AND i.tlp = ANY($3) AND i.revoked_at IS NULL AND i.id IN (
 			SELECT DISTINCT ic2.indicator_id
 			FROM indicator_campaigns ic2
 			WHERE ic2.campaign_id IN (
-				SELECT campaign_id FROM indicator_campaigns WHERE indicator_id = $1
+				SELECT ic.campaign_id
+				FROM indicator_campaigns ic
+				JOIN campaigns c ON c.id = ic.campaign_id
+				WHERE ic.indicator_id = $1 AND ic.tenant_id = $2 AND c.tlp = ANY($3)
 			)
 			AND ic2.indicator_id != $1
 		)
 		ORDER BY i.last_seen DESC NULLS LAST
 		LIMIT 5
 	`
-	relRows, err := r.db.QueryContext(ctx, relatedQuery, id)
+	relRows, err := r.db.QueryContext(ctx, relatedQuery, id, tenantID, allowed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get related indicators: %w", err)
 	}
@@ -138,20 +157,28 @@ func (r *IndicatorRepository) GetByID(ctx context.Context, id string) (*model.In
 		indicator.RelatedIndicators = append(indicator.RelatedIndicators, related)
 	}
 
+	if indicator.SampleID != "" {
+		indicator.SiblingHashes, err = sampleHashes(ctx, r.db, indicator.SampleID, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &indicator, nil
 }
 
 func (r *IndicatorRepository) Search(ctx context.Context, params model.SearchParams) (*model.SearchResult, error) {
 	baseQuery := r.sq.Select(
-		"i.id", "i.type", "i.value", "i.confidence",
-		"i.first_seen",
+		"i.id", "i.type", "i.value", "i.confidence", "i.tlp",
+		"i.first_seen", "i.revoked_at", "i.revocation_reason", "i.hash_algo",
 		"COUNT(DISTINCT ic.campaign_id) as campaign_count",
 		"COUNT(DISTINCT ia.actor_id) as threat_actor_count",
 	).
 		From("indicators i").
 		LeftJoin("indicator_campaigns ic ON ic.indicator_id = i.id").
 		LeftJoin("indicator_actors ia ON ia.indicator_id = i.id").
-		GroupBy("i.id", "i.type", "i.value", "i.confidence", "i.first_seen")
+		Where(squirrel.Eq{"i.tenant_id": tenant.FromContext(ctx), "i.tlp": allowedTLP(ctx)}).
+		GroupBy("i.id", "i.type", "i.value", "i.confidence", "i.tlp", "i.first_seen", "i.revoked_at", "i.revocation_reason", "i.hash_algo")
 
 	if params.Type != "" {
 		baseQuery = baseQuery.Where(squirrel.Eq{"i.type": params.Type})
@@ -171,11 +198,14 @@ func (r *IndicatorRepository) Search(ctx context.Context, params model.SearchPar
 	if params.LastSeenBefore != "" {
 		baseQuery = baseQuery.Where(squirrel.LtOrEq{"i.last_seen": params.LastSeenBefore})
 	}
+	baseQuery = applyThresholdFilters(baseQuery, params)
+	baseQuery = applyTextSearch(ctx, baseQuery, params)
 
 	countQuery := r.sq.Select("COUNT(DISTINCT i.id)").
 		From("indicators i").
 		LeftJoin("indicator_campaigns ic ON ic.indicator_id = i.id").
-		LeftJoin("indicator_actors ia ON ia.indicator_id = i.id")
+		LeftJoin("indicator_actors ia ON ia.indicator_id = i.id").
+		Where(squirrel.Eq{"i.tenant_id": tenant.FromContext(ctx), "i.tlp": allowedTLP(ctx)})
 
 	if params.Type != "" {
 		countQuery = countQuery.Where(squirrel.Eq{"i.type": params.Type})
@@ -195,6 +225,8 @@ func (r *IndicatorRepository) Search(ctx context.Context, params model.SearchPar
 	if params.LastSeenBefore != "" {
 		countQuery = countQuery.Where(squirrel.LtOrEq{"i.last_seen": params.LastSeenBefore})
 	}
+	countQuery = applyThresholdFilters(countQuery, params)
+	countQuery = applyTextSearch(ctx, countQuery, params)
 
 	countSQL, countArgs, err := countQuery.ToSql()
 	if err != nil {
@@ -206,6 +238,10 @@ func (r *IndicatorRepository) Search(ctx context.Context, params model.SearchPar
 		return nil, fmt.Errorf("failed to get total count: %w", err)
 	}
 
+	if params.Query != "" {
+		baseQuery = textSearchColumns(ctx, baseQuery, params.Query).OrderBy("rank DESC")
+	}
+
 	offset := (params.Page - 1) * params.Limit
 	baseQuery = baseQuery.
 		OrderBy("i.created_at DESC").
@@ -226,18 +262,29 @@ func (r *IndicatorRepository) Search(ctx context.Context, params model.SearchPar
 	var results []model.IndicatorSearchResult
 	for rows.Next() {
 		var r model.IndicatorSearchResult
-		var firstSeen sql.NullTime
+		var firstSeen, revokedAt sql.NullTime
+		var revocationReason, hashAlgo, snippet sql.NullString
 
-		if err := rows.Scan(
-			&r.ID, &r.Type, &r.Value, &r.Confidence,
-			&firstSeen, &r.CampaignCount, &r.ThreatActorCount,
-		); err != nil {
+		dest := []interface{}{
+			&r.ID, &r.Type, &r.Value, &r.Confidence, &r.TLP,
+			&firstSeen, &revokedAt, &revocationReason, &hashAlgo, &r.CampaignCount, &r.ThreatActorCount,
+		}
+		if params.Query != "" {
+			dest = append(dest, &r.Rank, &snippet)
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return nil, fmt.Errorf("failed to scan search result: %w", err)
 		}
 
 		if firstSeen.Valid {
 			r.FirstSeen = firstSeen.Time.Format(time.RFC3339)
 		}
+		if revokedAt.Valid {
+			r.RevokedAt = revokedAt.Time.Format(time.RFC3339)
+		}
+		r.RevocationReason = revocationReason.String
+		r.HashAlgo = hashAlgo.String
+		r.Snippet = highlightSnippet(snippet)
 		results = append(results, r)
 	}
 
@@ -258,19 +305,18 @@ func (r *IndicatorRepository) GetIndicatorsByIDs(ctx context.Context, ids []stri
 	}
 
 	placeholders := make([]string, len(ids))
-	args := make([]interface{}, len(ids))
+	args := make([]interface{}, 0, len(ids)+2)
+	args = append(args, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)))
 	for i, id := range ids {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		args[i] = id
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+		args = append(args, id)
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, type, value, description, severity, confidence,
-			   first_seen, last_seen, is_active, tags, metadata, source,
-			   created_at, updated_at
-		FROM indicators
-		WHERE id IN (%s)
-	`, strings.Join(placeholders, ", "))
+		SELECT %s
+		FROM indicators i
+		WHERE i.tenant_id = $1 AND i.tlp = ANY($2) AND i.id IN (%s)
+	`, indicatorColumns, strings.Join(placeholders, ", "))
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -278,33 +324,388 @@ func (r *IndicatorRepository) GetIndicatorsByIDs(ctx context.Context, ids []stri
 	}
 	defer rows.Close()
 
+	return scanIndicators(rows)
+}
+
+func (r *IndicatorRepository) Create(ctx context.Context, indicator *model.Indicator) error {
+	tags, metadata, err := marshalIndicatorJSON(indicator)
+	if err != nil {
+		return err
+	}
+
+	host, registeredDomain, tld := hostColumns(indicator)
+
+	query := `
+		INSERT INTO indicators (type, value, description, severity, confidence,
+			first_seen, last_seen, is_active, tags, metadata, source, tenant_id, tlp, pap,
+			host, registered_domain, tld, hash_algo)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		indicator.Type, indicator.Value, nullString(indicator.Description),
+		indicator.Severity, indicator.Confidence, indicator.FirstSeen, indicator.LastSeen,
+		indicator.IsActive, tags, metadata, nullString(indicator.Source), tenant.FromContext(ctx),
+		indicator.TLP, indicator.PAP, host, registeredDomain, tld, nullString(indicator.HashAlgo),
+	).Scan(&indicator.ID, &indicator.CreatedAt, &indicator.UpdatedAt)
+	if isUniqueViolation(err) {
+		return conflictWith(ctx, r.db, indicator)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create indicator: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IndicatorRepository) Update(ctx context.Context, indicator *model.Indicator) error {
+	tags, metadata, err := marshalIndicatorJSON(indicator)
+	if err != nil {
+		return err
+	}
+
+	host, registeredDomain, tld := hostColumns(indicator)
+
+	query := `
+		UPDATE indicators
+		SET type = $2, value = $3, description = $4, severity = $5, confidence = $6,
+			first_seen = $7, last_seen = $8, is_active = $9, tags = $10, metadata = $11,
+			source = $12, tlp = $14, pap = $15, host = $17, registered_domain = $18, tld = $19,
+			hash_algo = $20, updated_at = CURRENT_TIMESTAMP,
+			sample_id = CASE
+				WHEN $2 = 'hash' AND normalized_value = indicator_normalized_value($2, $3) THEN sample_id
+			END
+		WHERE id = $1 AND tenant_id = $13 AND tlp = ANY($16)
+		RETURNING created_at, updated_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		indicator.ID, indicator.Type, indicator.Value, nullString(indicator.Description),
+		indicator.Severity, indicator.Confidence, indicator.FirstSeen, indicator.LastSeen,
+		indicator.IsActive, tags, metadata, nullString(indicator.Source), tenant.FromContext(ctx),
+		indicator.TLP, indicator.PAP, pq.Array(allowedTLP(ctx)), host, registeredDomain, tld,
+		nullString(indicator.HashAlgo),
+	).Scan(&indicator.CreatedAt, &indicator.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if isUniqueViolation(err) {
+		return conflictWith(ctx, r.db, indicator)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update indicator: %w", err)
+	}
+
+	return nil
+}
+
+const indicatorMergeAssignments = `
+			description = COALESCE(EXCLUDED.description, i.description),
+			severity = CASE
+				WHEN array_position(ARRAY['low', 'medium', 'high', 'critical']::VARCHAR[], EXCLUDED.severity) >
+					 COALESCE(array_position(ARRAY['low', 'medium', 'high', 'critical']::VARCHAR[], i.severity), 0)
+				THEN EXCLUDED.severity
+				ELSE i.severity
+			END,
+			confidence = GREATEST(i.confidence, EXCLUDED.confidence),
+			first_seen = LEAST(i.first_seen, EXCLUDED.first_seen),
+			last_seen = GREATEST(i.last_seen, EXCLUDED.last_seen),
+			is_active = i.is_active OR EXCLUDED.is_active,
+			tlp = CASE WHEN marking_rank(EXCLUDED.tlp) > marking_rank(i.tlp) THEN EXCLUDED.tlp ELSE i.tlp END,
+			pap = CASE WHEN marking_rank(EXCLUDED.pap) > marking_rank(i.pap) THEN EXCLUDED.pap ELSE i.pap END,
+			tags = (
+				SELECT COALESCE(jsonb_agg(DISTINCT t.tag), '[]'::jsonb)
+				FROM jsonb_array_elements_text(COALESCE(i.tags, '[]'::jsonb) || EXCLUDED.tags) AS t(tag)
+			),
+			metadata = COALESCE(i.metadata, '{}'::jsonb) || EXCLUDED.metadata,
+			source = COALESCE(i.source, EXCLUDED.source),
+			hash_algo = COALESCE(i.hash_algo, EXCLUDED.hash_algo),
+			revoked_at = NULL,
+			revocation_reason = NULL,
+			updated_at = CURRENT_TIMESTAMP`
+
+const indicatorNotFalsePositive = `i.revocation_reason IS DISTINCT FROM 'false_positive'`
+
+// Upsert returns the row it merged into as it was before the merge, read and
+// locked in the same transaction, or nil when the indicator was created.
+func (r *IndicatorRepository) Upsert(ctx context.Context, indicator *model.Indicator, force bool) (*model.Indicator, bool, error) {
+	tags, metadata, err := marshalIndicatorJSON(indicator)
+	if err != nil {
+		return nil, false, err
+	}
+
+	host, registeredDomain, tld := hostColumns(indicator)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin upsert transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	previous, err := scanIndicator(tx.QueryRowContext(ctx, `
+		SELECT `+indicatorColumns+`
+		FROM indicators i
+		WHERE i.tenant_id = $1 AND i.type = $2 AND i.normalized_value = indicator_normalized_value($2, $3)
+		  AND i.tlp = ANY($4)
+		FOR UPDATE
+	`, tenant.FromContext(ctx), indicator.Type, indicator.Value, pq.Array(allowedTLP(ctx))))
+	if errors.Is(err, sql.ErrNoRows) {
+		previous = nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	query := `
+		INSERT INTO indicators AS i (type, value, description, severity, confidence,
+			first_seen, last_seen, is_active, tags, metadata, source, tenant_id, tlp, pap,
+			host, registered_domain, tld, hash_algo)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $17, $18, $19, $20)
+		ON CONFLICT (tenant_id, type, normalized_value) DO UPDATE SET ` + indicatorMergeAssignments + `
+		WHERE i.tlp = ANY($15) AND (` + indicatorNotFalsePositive + ` OR $16)
+		RETURNING id, value, description, severity, confidence, first_seen, last_seen,
+			is_active, tlp, pap, tags, metadata, source, hash_algo, created_at, updated_at, (xmax = 0) AS inserted
+	`
+
+	var description, severity, source, hashAlgo sql.NullString
+	var tagsOut, metadataOut sql.NullString
+	var firstSeen, lastSeen sql.NullTime
+	var inserted bool
+
+	err = tx.QueryRowContext(ctx, query,
+		indicator.Type, indicator.Value, nullString(indicator.Description),
+		indicator.Severity, indicator.Confidence, indicator.FirstSeen, indicator.LastSeen,
+		indicator.IsActive, tags, metadata, nullString(indicator.Source), tenant.FromContext(ctx),
+		indicator.TLP, indicator.PAP, pq.Array(allowedTLP(ctx)), force, host, registeredDomain, tld,
+		nullString(indicator.HashAlgo),
+	).Scan(
+		&indicator.ID, &indicator.Value, &description, &severity, &indicator.Confidence,
+		&firstSeen, &lastSeen, &indicator.IsActive, &indicator.TLP, &indicator.PAP,
+		&tagsOut, &metadataOut, &source, &hashAlgo,
+		&indicator.CreatedAt, &indicator.UpdatedAt, &inserted,
+	)
+	if err == sql.ErrNoRows {
+		return nil, false, upsertBlockedBy(ctx, tx, indicator)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to upsert indicator: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit upsert: %w", err)
+	}
+
+	indicator.Description = description.String
+	indicator.Severity = severity.String
+	indicator.Source = source.String
+	indicator.HashAlgo = hashAlgo.String
+	indicator.FirstSeen = nil
+	if firstSeen.Valid {
+		indicator.FirstSeen = &firstSeen.Time
+	}
+	indicator.LastSeen = nil
+	if lastSeen.Valid {
+		indicator.LastSeen = &lastSeen.Time
+	}
+	indicator.Tags = nil
+	if tagsOut.Valid {
+		json.Unmarshal([]byte(tagsOut.String), &indicator.Tags)
+	}
+	if metadataOut.Valid {
+		indicator.Metadata = json.RawMessage(metadataOut.String)
+	}
+
+	return previous, inserted, nil
+}
+
+// upsertBlockedBy explains why an upsert returned no row: the existing
+// indicator is either above the caller's clearance or a revoked false positive.
+// A hidden indicator is reported as ErrNotWritable rather than a conflict, so
+// the caller is never told that an indicator it cannot see holds the value.
+func upsertBlockedBy(ctx context.Context, q queryer, indicator *model.Indicator) error {
+	query := `
+		SELECT i.tlp = ANY($4), i.revocation_reason
+		FROM indicators i
+		WHERE i.tenant_id = $1 AND i.type = $2 AND i.normalized_value = indicator_normalized_value($2, $3)
+	`
+
+	var visible bool
+	var reason sql.NullString
+	err := q.QueryRowContext(ctx, query, tenant.FromContext(ctx), indicator.Type, indicator.Value,
+		pq.Array(allowedTLP(ctx))).Scan(&visible, &reason)
+	if err == sql.ErrNoRows {
+		return ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check conflicting indicator: %w", err)
+	}
+	if !visible {
+		return ErrNotWritable
+	}
+	if reason.String == model.RevocationFalsePositive {
+		return ErrFalsePositive
+	}
+	return ErrConflict
+}
+
+// conflictWith explains a unique violation on create or update, where a
+// revoked false positive is an ordinary conflict.
+func conflictWith(ctx context.Context, q queryer, indicator *model.Indicator) error {
+	err := upsertBlockedBy(ctx, q, indicator)
+	if errors.Is(err, ErrFalsePositive) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (r *IndicatorRepository) Revoke(ctx context.Context, id, reason string) (*time.Time, error) {
+	query := `
+		UPDATE indicators
+		SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP),
+			revocation_reason = COALESCE($4, revocation_reason),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND tlp = ANY($3)
+		RETURNING revoked_at
+	`
+
+	var revokedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)),
+		nullString(reason)).Scan(&revokedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke indicator: %w", err)
+	}
+
+	return &revokedAt, nil
+}
+
+func (r *IndicatorRepository) Restore(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE indicators
+		SET revoked_at = NULL, revocation_reason = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND tlp = ANY($3)
+	`, id, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return fmt.Errorf("failed to restore indicator: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to restore indicator: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func marshalIndicatorJSON(indicator *model.Indicator) (string, string, error) {
+	tags := indicator.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode tags: %w", err)
+	}
+
+	metadata := "{}"
+	if len(indicator.Metadata) > 0 {
+		metadata = string(indicator.Metadata)
+	}
+
+	return string(tagsJSON), metadata, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+const indicatorColumns = `i.id, i.type, i.value, i.description, i.severity, i.confidence,
+			   i.first_seen, i.last_seen, i.is_active, i.tlp, i.pap, i.tags, i.metadata, i.source,
+			   i.created_at, i.updated_at, i.revoked_at, i.revocation_reason, i.hash_algo`
+
+func scanIndicators(rows *sql.Rows) ([]model.Indicator, error) {
 	var indicators []model.Indicator
 	for rows.Next() {
-		var ind model.Indicator
-		var tags, metadata sql.NullString
-		var firstSeen, lastSeen sql.NullTime
-
-		if err := rows.Scan(
-			&ind.ID, &ind.Type, &ind.Value, &ind.Description,
-			&ind.Severity, &ind.Confidence, &firstSeen, &lastSeen,
-			&ind.IsActive, &tags, &metadata, &ind.Source,
-			&ind.CreatedAt, &ind.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan indicator: %w", err)
-		}
-
-		if firstSeen.Valid {
-			ind.FirstSeen = &firstSeen.Time
-		}
-		if lastSeen.Valid {
-			ind.LastSeen = &lastSeen.Time
-		}
-		if tags.Valid {
-			json.Unmarshal([]byte(tags.String), &ind.Tags)
+		ind, err := scanIndicator(rows)
+		if err != nil {
+			return nil, err
 		}
+		indicators = append(indicators, *ind)
+	}
 
-		indicators = append(indicators, ind)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate indicators: %w", err)
 	}
 
 	return indicators, nil
 }
+
+func scanIndicator(row rowScanner, extra ...interface{}) (*model.Indicator, error) {
+	var ind model.Indicator
+	var description, severity, tags, metadata, source, revocationReason, hashAlgo sql.NullString
+	var firstSeen, lastSeen, revokedAt sql.NullTime
+
+	dest := []interface{}{
+		&ind.ID, &ind.Type, &ind.Value, &description,
+		&severity, &ind.Confidence, &firstSeen, &lastSeen,
+		&ind.IsActive, &ind.TLP, &ind.PAP, &tags, &metadata, &source,
+		&ind.CreatedAt, &ind.UpdatedAt, &revokedAt, &revocationReason, &hashAlgo,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, fmt.Errorf("failed to scan indicator: %w", err)
+	}
+
+	ind.Description = description.String
+	ind.Severity = severity.String
+	ind.Source = source.String
+	ind.RevocationReason = revocationReason.String
+	ind.HashAlgo = hashAlgo.String
+	if firstSeen.Valid {
+		ind.FirstSeen = &firstSeen.Time
+	}
+	if lastSeen.Valid {
+		ind.LastSeen = &lastSeen.Time
+	}
+	if tags.Valid {
+		json.Unmarshal([]byte(tags.String), &ind.Tags)
+	}
+	if metadata.Valid {
+		ind.Metadata = json.RawMessage(metadata.String)
+	}
+	if revokedAt.Valid {
+		ind.RevokedAt = &revokedAt.Time
+	}
+
+	return &ind, nil
+}
+
+func allowedTLP(ctx context.Context) []string {
+	return marking.Within(marking.ClearanceFromContext(ctx))
+}
+
+func applyThresholdFilters(query squirrel.SelectBuilder, params model.SearchParams) squirrel.SelectBuilder {
+	if params.MinConfidence > 0 {
+		query = query.Where(squirrel.GtOrEq{"i.confidence": params.MinConfidence})
+	}
+	if params.MinSeverity != "" {
+		query = query.Where(squirrel.Eq{"i.severity": model.SeveritiesAtLeast(params.MinSeverity)})
+	}
+	if !params.IncludeRevoked {
+		query = query.Where("i.revoked_at IS NULL")
+	}
+	if params.CIDR != "" {
+		query = query.Where("i.ip <<= ?::inet", params.CIDR)
+	}
+	if params.IPInRange != "" {
+		query = query.Where("i.ip >>= ?::inet", params.IPInRange)
+	}
+	if params.HashAlgo != "" {
+		query = query.Where(squirrel.Eq{"i.hash_algo": params.HashAlgo})
+	}
+	return applyHostFilters(query, params)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// LinkSample puts two hashes into the same sample, merging the samples they
+// already belong to. A sample holds at most one hash per algorithm.
+func (r *IndicatorRepository) LinkSample(ctx context.Context, indicatorID, siblingID string) (*model.IndicatorSample, error) {
+	tenantID := tenant.FromContext(ctx)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin sample transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, sample_id
+		FROM indicators
+		WHERE id = ANY($1) AND tenant_id = $2 AND tlp = ANY($3) AND type = 'hash'
+		FOR UPDATE
+	`, pq.Array([]string{indicatorID, siblingID}), tenantID, pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock sample hashes: %w", err)
+	}
+	samples := make(map[string]string, 2)
+	for rows.Next() {
+		var id string
+		var sampleID sql.NullString
+		if err := rows.Scan(&id, &sampleID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan sample hash: %w", err)
+		}
+		samples[id] = sampleID.String
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sample hashes: %w", err)
+	}
+	if len(samples) != 2 {
+		return nil, ErrNotFound
+	}
+
+	sampleID := samples[indicatorID]
+	if sampleID == "" {
+		sampleID = samples[siblingID]
+	}
+	if sampleID == "" {
+		sampleID = uuid.NewString()
+	}
+
+	merged := []string{}
+	for _, existing := range samples {
+		if existing != "" {
+			merged = append(merged, existing)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE indicators
+		SET sample_id = $1
+		WHERE tenant_id = $2 AND (id = ANY($3) OR sample_id = ANY($4))
+	`, sampleID, tenantID, pq.Array([]string{indicatorID, siblingID}), pq.Array(merged)); err != nil {
+		return nil, fmt.Errorf("failed to link sample hashes: %w", err)
+	}
+
+	var duplicated bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM indicators
+			WHERE sample_id = $1 AND tenant_id = $2
+			GROUP BY hash_algo
+			HAVING COUNT(*) > 1
+		)
+	`, sampleID, tenantID).Scan(&duplicated); err != nil {
+		return nil, fmt.Errorf("failed to check sample hashes: %w", err)
+	}
+	if duplicated {
+		return nil, ErrConflict
+	}
+
+	hashes, err := sampleHashes(ctx, tx, sampleID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit sample transaction: %w", err)
+	}
+
+	return &model.IndicatorSample{ID: sampleID, Hashes: hashes}, nil
+}
+
+// UnlinkSample takes a hash out of its sample and dissolves the sample when a
+// single hash is left in it.
+func (r *IndicatorRepository) UnlinkSample(ctx context.Context, indicatorID string) error {
+	tenantID := tenant.FromContext(ctx)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin sample transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var sampleID string
+	err = tx.QueryRowContext(ctx, `
+		SELECT sample_id
+		FROM indicators
+		WHERE id = $1 AND tenant_id = $2 AND tlp = ANY($3) AND sample_id IS NOT NULL
+		FOR UPDATE
+	`, indicatorID, tenantID, pq.Array(allowedTLP(ctx))).Scan(&sampleID)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get indicator sample: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE indicators
+		SET sample_id = NULL
+		WHERE tenant_id = $2 AND (id = $1 OR (
+			sample_id = $3 AND (SELECT COUNT(*) FROM indicators WHERE sample_id = $3 AND tenant_id = $2) <= 2
+		))
+	`, indicatorID, tenantID, sampleID); err != nil {
+		return fmt.Errorf("failed to unlink indicator sample: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sample transaction: %w", err)
+	}
+	return nil
+}
+
+func sampleHashes(ctx context.Context, q queryer, sampleID, excludeID string) ([]model.SiblingHash, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, value, COALESCE(hash_algo, '')
+		FROM indicators
+		WHERE sample_id = $1 AND tenant_id = $2 AND tlp = ANY($3) AND revoked_at IS NULL
+		  AND id IS DISTINCT FROM $4::UUID
+		ORDER BY hash_algo, id
+	`, sampleID, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)), nullString(excludeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sample hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := []model.SiblingHash{}
+	for rows.Next() {
+		var hash model.SiblingHash
+		if err := rows.Scan(&hash.ID, &hash.Value, &hash.HashAlgo); err != nil {
+			return nil, fmt.Errorf("failed to scan sample hash: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sample hashes: %w", err)
+	}
+	return hashes, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"html"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+const (
+	snippetStartSel = "\x02"
+	snippetStopSel  = "\x03"
+
+	snippetOptions = `StartSel="` + snippetStartSel + `", StopSel="` + snippetStopSel + `", ` +
+		`MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" … "`
+)
+
+// Each branch of the union is served by its own GIN index, so q= never has
+// to compute a tsvector per indicator row.
+const textSearchMatch = `i.id IN (
+		SELECT s.id FROM indicators s
+		WHERE s.search_vector @@ websearch_to_tsquery('english', ?)
+		UNION
+		SELECT lc.indicator_id FROM indicator_campaigns lc
+		JOIN campaigns c ON c.id = lc.campaign_id
+		WHERE c.tlp = ANY(?) AND to_tsvector('english', c.name) @@ websearch_to_tsquery('english', ?)
+		UNION
+		SELECT la.indicator_id FROM indicator_actors la
+		JOIN threat_actors ta ON ta.id = la.actor_id
+		WHERE to_tsvector('english', ta.name) @@ websearch_to_tsquery('english', ?)
+	)`
+
+const linkedNames = `concat_ws(' ',
+		(SELECT string_agg(c.name, ' ') FROM indicator_campaigns lc
+			JOIN campaigns c ON c.id = lc.campaign_id
+			WHERE lc.indicator_id = i.id AND c.tlp = ANY(?)),
+		(SELECT string_agg(ta.name, ' ') FROM indicator_actors la
+			JOIN threat_actors ta ON ta.id = la.actor_id
+			WHERE la.indicator_id = i.id))`
+
+const textSearchRank = `ts_rank(
+		i.search_vector || setweight(to_tsvector('english', ` + linkedNames + `), 'B'),
+		websearch_to_tsquery('english', ?)
+	) AS rank`
+
+const textSearchSnippet = `ts_headline('english',
+		concat_ws(' … ', i.value, i.description,
+			(SELECT string_agg(t, ' ') FROM jsonb_array_elements_text(i.tags) t),
+			i.source, ` + linkedNames + `),
+		websearch_to_tsquery('english', ?), ?
+	) AS snippet`
+
+func applyTextSearch(ctx context.Context, query squirrel.SelectBuilder, params model.SearchParams) squirrel.SelectBuilder {
+	if params.Query == "" {
+		return query
+	}
+	return query.Where(textSearchMatch, params.Query, pq.Array(allowedTLP(ctx)), params.Query, params.Query)
+}
+
+func textSearchColumns(ctx context.Context, query squirrel.SelectBuilder, q string) squirrel.SelectBuilder {
+	allowed := pq.Array(allowedTLP(ctx))
+	return query.
+		Column(textSearchRank, allowed, q).
+		Column(textSearchSnippet, allowed, q, snippetOptions)
+}
+
+// ts_headline marks matches with control characters rather than tags so the
+// surrounding text can be escaped before the <mark> elements are added.
+func highlightSnippet(headline sql.NullString) string {
+	if !strings.Contains(headline.String, snippetStartSel) {
+		return ""
+	}
+	snippet := html.EscapeString(headline.String)
+	snippet = strings.ReplaceAll(snippet, snippetStartSel, "<mark>")
+	return strings.ReplaceAll(snippet, snippetStopSel, "</mark>")
+}
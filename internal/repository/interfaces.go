@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
 )
@@ -9,14 +10,67 @@ import (
 type IndicatorRepositoryInterface interface {
 	GetByID(ctx context.Context, id string) (*model.IndicatorWithRelations, error)
 	Search(ctx context.Context, params model.SearchParams) (*model.SearchResult, error)
+	StreamSearch(ctx context.Context, params model.SearchParams, fn func(*model.Indicator) error) error
+	StreamAttributed(ctx context.Context, params model.SearchParams, fn func(*model.AttributedIndicator) error) error
 	GetIndicatorsByIDs(ctx context.Context, ids []string) ([]model.Indicator, error)
+	Create(ctx context.Context, indicator *model.Indicator) error
+	Update(ctx context.Context, indicator *model.Indicator) error
+	Upsert(ctx context.Context, indicator *model.Indicator, force bool) (*model.Indicator, bool, error)
+	BulkUpsert(ctx context.Context, indicators []model.BulkIndicator, force bool) (*model.BulkResult, error)
+	Revoke(ctx context.Context, id, reason string) (*time.Time, error)
+	Restore(ctx context.Context, id string) error
+	LinkActor(ctx context.Context, link *model.IndicatorActorLink) (bool, error)
+	UnlinkActor(ctx context.Context, indicatorID, actorID string) error
+	LinkCampaign(ctx context.Context, link *model.IndicatorCampaignLink) (bool, error)
+	UnlinkCampaign(ctx context.Context, indicatorID, campaignID string) error
+	LinkSample(ctx context.Context, indicatorID, siblingID string) (*model.IndicatorSample, error)
+	UnlinkSample(ctx context.Context, indicatorID string) error
+	History(ctx context.Context, id string, params model.IndicatorHistoryParams) (*model.IndicatorHistoryResult, error)
+	GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.IndicatorWithRelations, error)
 }
 
 type CampaignRepositoryInterface interface {
+	List(ctx context.Context, params model.CampaignListParams) (*model.CampaignListResult, error)
 	GetByID(ctx context.Context, id string) (*model.Campaign, error)
+	Create(ctx context.Context, campaign *model.Campaign) error
+	Update(ctx context.Context, campaign *model.Campaign) error
+	Delete(ctx context.Context, id string) error
+	LinkIndicator(ctx context.Context, link *model.IndicatorCampaignLink) (bool, error)
+	UnlinkIndicator(ctx context.Context, campaignID, indicatorID string) error
 	GetIndicatorsTimeline(ctx context.Context, campaignID string, params model.TimelineParams) (*model.CampaignWithTimeline, error)
 }
 
+type ActorRepositoryInterface interface {
+	List(ctx context.Context, params model.ActorListParams) (*model.ActorListResult, error)
+	GetByID(ctx context.Context, id string) (*model.ThreatActorDetail, error)
+	GetIndicators(ctx context.Context, actorID string, params model.ActorIndicatorParams) (*model.ActorIndicatorResult, error)
+	SetAliases(ctx context.Context, actorID string, aliases []string) error
+}
+
 type DashboardRepositoryInterface interface {
 	GetSummary(ctx context.Context, timeRange string) (*model.DashboardSummary, error)
 }
+
+type BundleRepositoryInterface interface {
+	GetByIndicator(ctx context.Context, indicatorID string) (*model.IntelBundle, error)
+	GetByCampaign(ctx context.Context, campaignID string) (*model.IntelBundle, error)
+	Import(ctx context.Context, data *model.IntelImport) (*model.ImportResult, error)
+}
+
+type TaxiiRepositoryInterface interface {
+	ListCampaigns(ctx context.Context) ([]model.Campaign, error)
+	GetCampaign(ctx context.Context, id string) (*model.Campaign, error)
+	GetObjects(ctx context.Context, params model.TaxiiObjectParams) ([]model.TaxiiIndicator, error)
+}
+
+type APIKeyRepositoryInterface interface {
+	Create(ctx context.Context, key *model.APIKey, hash string) error
+	List(ctx context.Context) ([]model.APIKey, error)
+	GetActiveByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	Revoke(ctx context.Context, id string) (*model.APIKey, error)
+}
+
+type AuditRepositoryInterface interface {
+	Create(ctx context.Context, event *model.AuditEvent) error
+	List(ctx context.Context, params model.AuditListParams) (*model.AuditListResult, error)
+}
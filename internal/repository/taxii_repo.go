@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/tenant"
+	"github.com/lib/pq"
+)
+
+type TaxiiRepository struct {
+	db *sql.DB
+}
+
+func NewTaxiiRepository(db *sql.DB) *TaxiiRepository {
+	return &TaxiiRepository{db: db}
+}
+
+func (r *TaxiiRepository) ListCampaigns(ctx context.Context) ([]model.Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns c WHERE c.tenant_id = $1 AND c.tlp = ANY($2) ORDER BY c.name, c.id`
+	rows, err := r.db.QueryContext(ctx, query, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	var campaigns []model.Campaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, *campaign)
+	}
+
+	return campaigns, rows.Err()
+}
+
+func (r *TaxiiRepository) GetCampaign(ctx context.Context, id string) (*model.Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns c WHERE c.id = $1 AND c.tenant_id = $2 AND c.tlp = ANY($3)`
+	campaign, err := scanCampaign(r.db.QueryRowContext(ctx, query, id, tenant.FromContext(ctx), pq.Array(allowedTLP(ctx))))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (r *TaxiiRepository) GetObjects(ctx context.Context, params model.TaxiiObjectParams) ([]model.TaxiiIndicator, error) {
+	dateAdded := `i.updated_at`
+	from := `indicators i`
+	var args []interface{}
+
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	conditions := []string{
+		`i.tenant_id = ` + addArg(tenant.FromContext(ctx)),
+		`i.tlp = ANY(` + addArg(pq.Array(allowedTLP(ctx))) + `)`,
+	}
+
+	if params.CampaignID != "" {
+		dateAdded = `GREATEST(ic.added_at, i.updated_at)`
+		from = `indicators i JOIN indicator_campaigns ic ON ic.indicator_id = i.id`
+		conditions = append(conditions, `ic.campaign_id = `+addArg(params.CampaignID))
+	}
+	if params.AddedAfter != nil {
+		conditions = append(conditions, dateAdded+` > `+addArg(*params.AddedAfter))
+	}
+	if params.AfterDate != nil {
+		conditions = append(conditions, fmt.Sprintf(`(%s, i.id) > (%s, %s::UUID)`,
+			dateAdded, addArg(*params.AfterDate), addArg(params.AfterID)))
+	}
+
+	query := `SELECT ` + indicatorColumns + `, ` + dateAdded + ` AS date_added FROM ` + from +
+		` WHERE ` + strings.Join(conditions, ` AND `) +
+		` ORDER BY date_added, i.id LIMIT ` + addArg(params.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get taxii objects: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []model.TaxiiIndicator
+	for rows.Next() {
+		var object model.TaxiiIndicator
+		ind, err := scanIndicator(rows, &object.DateAdded)
+		if err != nil {
+			return nil, err
+		}
+		object.Indicator = *ind
+		objects = append(objects, object)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate taxii objects: %w", err)
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,303 @@
+package rules
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"math"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/hostname"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+const (
+	EngineSuricata = "suricata"
+
+	ListMD5    = model.HashAlgoMD5
+	ListSHA1   = model.HashAlgoSHA1
+	ListSHA256 = model.HashAlgoSHA256
+
+	SIDBase   = 1_000_000_000
+	sidSlots  = 2
+	sidBucket = (math.MaxUint32 - SIDBase) / sidSlots
+
+	msgPrefix = "ThreatIntel"
+)
+
+type HashList struct {
+	Name     string
+	Keyword  string
+	Filename string
+	SID      uint32
+}
+
+var HashLists = map[string]HashList{
+	ListMD5:    {Name: ListMD5, Keyword: "filemd5", Filename: "threat-intel-md5.list", SID: SIDBase - 3},
+	ListSHA1:   {Name: ListSHA1, Keyword: "filesha1", Filename: "threat-intel-sha1.list", SID: SIDBase - 2},
+	ListSHA256: {Name: ListSHA256, Keyword: "filesha256", Filename: "threat-intel-sha256.list", SID: SIDBase - 1},
+}
+
+var severityPriorities = map[string]int{"critical": 1, "high": 2, "medium": 3, "low": 4}
+
+// SID depends on nothing but the indicator UUID: a 64-bit FNV-1a hash of it
+// picks one of sidBucket buckets above SIDBase, and each bucket holds one SID
+// per rule variant.
+func SID(indicatorID string, variant int) uint32 {
+	return sidForBucket(bucketFor(indicatorID), variant)
+}
+
+func bucketFor(indicatorID string) uint32 {
+	h := fnv.New64a()
+	h.Write([]byte(indicatorID))
+	return uint32(h.Sum64() % sidBucket)
+}
+
+func sidForBucket(bucket uint32, variant int) uint32 {
+	return SIDBase + bucket*sidSlots + uint32(variant)
+}
+
+type SuricataWriter struct {
+	w       *bufio.Writer
+	buckets map[uint32]string
+	lists   map[string]bool
+}
+
+func NewSuricataWriter(w io.Writer) *SuricataWriter {
+	return &SuricataWriter{w: bufio.NewWriter(w), buckets: map[uint32]string{}, lists: map[string]bool{}}
+}
+
+func (sw *SuricataWriter) Header() error {
+	_, err := sw.w.WriteString("# Threat intel detection rules for Suricata\n")
+	return err
+}
+
+func (sw *SuricataWriter) Encode(ind *model.AttributedIndicator) error {
+	if ind.Type == model.IndicatorTypeHash {
+		return sw.encodeHashRule(&ind.Indicator)
+	}
+
+	bodies := ruleBodies(ind)
+	if len(bodies) == 0 {
+		return nil
+	}
+
+	bucket, owner := sw.claimBucket(ind.ID)
+	if owner != ind.ID {
+		// Moving the indicator to another SID would make its SID depend on
+		// what else is in the download, so it is left out instead.
+		slog.Warn("Skipping Suricata rule with colliding SID",
+			"indicator_id", ind.ID, "sid", sidForBucket(bucket, 0), "colliding_indicator_id", owner)
+		_, err := fmt.Fprintf(sw.w, "# skipped indicator %s: sid %d is already used by indicator %s\n",
+			ind.ID, sidForBucket(bucket, 0), owner)
+		return err
+	}
+	for variant, body := range bodies {
+		if _, err := fmt.Fprintf(sw.w, "%s; %s sid:%d; rev:%d;)\n",
+			body, ruleOptions(ind), sidForBucket(bucket, variant), revision(ind)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sw *SuricataWriter) Flush() error {
+	return sw.w.Flush()
+}
+
+// claimBucket returns the indicator's bucket and the indicator that owns it,
+// which is a different one when their SIDs collide.
+func (sw *SuricataWriter) claimBucket(indicatorID string) (uint32, string) {
+	bucket := bucketFor(indicatorID)
+	if owner, ok := sw.buckets[bucket]; ok {
+		return bucket, owner
+	}
+	sw.buckets[bucket] = indicatorID
+	return bucket, indicatorID
+}
+
+func (sw *SuricataWriter) encodeHashRule(ind *model.Indicator) error {
+	list, ok := hashListFor(ind)
+	if !ok || sw.lists[list.Name] {
+		return nil
+	}
+	sw.lists[list.Name] = true
+
+	_, err := fmt.Fprintf(sw.w, "alert http any any -> any any (msg:\"%s known malicious file (%s list)\"; %s:%s; classtype:trojan-activity; sid:%d; rev:1;)\n",
+		msgPrefix, strings.ToUpper(list.Name), list.Keyword, list.Filename, list.SID)
+	return err
+}
+
+func ruleBodies(ind *model.AttributedIndicator) []string {
+	msg := escapeMsg(message(ind))
+
+	switch ind.Type {
+	case model.IndicatorTypeIP:
+		prefix, ok := parseAddress(ind.Value)
+		if !ok {
+			return nil
+		}
+		address := prefix.String()
+		if prefix.IsSingleIP() {
+			address = prefix.Addr().String()
+		}
+		return []string{
+			fmt.Sprintf(`alert ip $HOME_NET any <> %s any (msg:"%s"`, address, msg),
+		}
+	case model.IndicatorTypeDomain:
+		domain := hostname.Normalize(ind.Value)
+		if domain == "" {
+			return nil
+		}
+		match := fmt.Sprintf(`dotprefix; content:"%s"; nocase; endswith`, escapeContent("."+domain))
+		return []string{
+			fmt.Sprintf(`alert dns $HOME_NET any -> any any (msg:"%s"; dns.query; %s`, msg, match),
+			fmt.Sprintf(`alert tls $HOME_NET any -> any any (msg:"%s"; tls.sni; %s`, msg, match),
+		}
+	case model.IndicatorTypeURL:
+		host, uri, ok := splitURL(ind.Value)
+		if !ok {
+			return nil
+		}
+		return []string{
+			fmt.Sprintf(`alert http $HOME_NET any -> any any (msg:"%s"; flow:established,to_server; http.host; content:"%s"; http.uri; content:"%s"; startswith`,
+				msg, escapeContent(host), escapeContent(uri)),
+		}
+	}
+	return nil
+}
+
+func ruleOptions(ind *model.AttributedIndicator) string {
+	priority, ok := severityPriorities[ind.Severity]
+	if !ok {
+		priority = 3
+	}
+	return fmt.Sprintf("classtype:trojan-activity; priority:%d; metadata:indicator_id %s, confidence %d;",
+		priority, ind.ID, ind.Confidence)
+}
+
+func revision(ind *model.AttributedIndicator) int64 {
+	if ind.UpdatedAt.IsZero() {
+		return 1
+	}
+	return ind.UpdatedAt.Unix()
+}
+
+func message(ind *model.AttributedIndicator) string {
+	msg := fmt.Sprintf("%s %s %s", msgPrefix, ind.Type, ind.Value)
+	if len(ind.Campaigns) > 0 {
+		msg += " - campaigns: " + strings.Join(ind.Campaigns, ", ")
+	}
+	if len(ind.ThreatActors) > 0 {
+		msg += " - actors: " + strings.Join(ind.ThreatActors, ", ")
+	}
+	return msg
+}
+
+func parseAddress(value string) (netip.Prefix, bool) {
+	value = strings.TrimSpace(value)
+	if addr, err := netip.ParseAddr(value); err == nil {
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), true
+	}
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		return prefix.Masked(), true
+	}
+	return netip.Prefix{}, false
+}
+
+func splitURL(value string) (string, string, bool) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Hostname() == "" {
+		return "", "", false
+	}
+
+	uri := u.EscapedPath()
+	if uri == "" {
+		uri = "/"
+	}
+	if u.RawQuery != "" {
+		uri += "?" + u.RawQuery
+	}
+	return strings.ToLower(u.Hostname()), uri, true
+}
+
+// hashListFor prefers the stored algorithm, so an imphash never lands in the
+// md5 list just because it looks like one.
+func hashListFor(ind *model.Indicator) (HashList, bool) {
+	hashAlgo := ind.HashAlgo
+	if hashAlgo == "" {
+		hashAlgo = model.DetectHashAlgo(ind.Value)
+	}
+	if !model.IsValidHash(hashAlgo, ind.Value) {
+		return HashList{}, false
+	}
+	list, ok := HashLists[hashAlgo]
+	return list, ok
+}
+
+func escapeMsg(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '"' || r == ';' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			b.WriteByte(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func escapeContent(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '"' || c == ';' || c == '\\' || c == '|' || c < 0x20 || c >= 0x7f {
+			fmt.Fprintf(&b, "|%02X|", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+type HashListWriter struct {
+	list HashList
+	w    *bufio.Writer
+}
+
+func NewHashListWriter(list HashList, w io.Writer) *HashListWriter {
+	return &HashListWriter{list: list, w: bufio.NewWriter(w)}
+}
+
+func (hw *HashListWriter) Header() error {
+	return nil
+}
+
+func (hw *HashListWriter) Encode(ind *model.AttributedIndicator) error {
+	if ind.Type != model.IndicatorTypeHash {
+		return nil
+	}
+	list, ok := hashListFor(&ind.Indicator)
+	if !ok || list.Name != hw.list.Name {
+		return nil
+	}
+	_, err := hw.w.WriteString(strings.ToLower(strings.TrimSpace(ind.Value)) + "\n")
+	return err
+}
+
+func (hw *HashListWriter) Flush() error {
+	return hw.w.Flush()
+}
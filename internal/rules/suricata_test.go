@@ -0,0 +1,161 @@
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderRules(t *testing.T, indicators ...model.AttributedIndicator) []string {
+	var buf bytes.Buffer
+	w := NewSuricataWriter(&buf)
+	require.NoError(t, w.Header())
+	for i := range indicators {
+		require.NoError(t, w.Encode(&indicators[i]))
+	}
+	require.NoError(t, w.Flush())
+
+	var rules []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			rules = append(rules, line)
+		}
+	}
+	return rules
+}
+
+func attributed(id string, t model.IndicatorType, value string) model.AttributedIndicator {
+	return model.AttributedIndicator{
+		Indicator: model.Indicator{
+			ID: id, Type: t, Value: value, Severity: "high", Confidence: 80,
+			UpdatedAt: time.Unix(1709294400, 0),
+		},
+	}
+}
+
+func TestSID_StableAndInRange(t *testing.T) {
+	id := "550e8400-e29b-41d4-a716-446655440000"
+
+	assert.Equal(t, SID(id, 0), SID(id, 0))
+	assert.Equal(t, SID(id, 0)+1, SID(id, 1))
+	assert.GreaterOrEqual(t, SID(id, 0), uint32(SIDBase))
+	assert.NotEqual(t, SID(id, 0), SID("550e8400-e29b-41d4-a716-446655440001", 0))
+}
+
+func TestSuricataWriter_IP(t *testing.T) {
+	ind := attributed("550e8400-e29b-41d4-a716-446655440000", model.IndicatorTypeIP, "203.0.113.7")
+	ind.Campaigns = []string{"Operation Dark Night"}
+	ind.ThreatActors = []string{"APT-C-23", "Sandworm"}
+
+	rules := renderRules(t, ind)
+
+	require.Len(t, rules, 1)
+	assert.Equal(t, fmt.Sprintf(`alert ip $HOME_NET any <> 203.0.113.7 any (msg:"ThreatIntel ip 203.0.113.7 - campaigns: Operation Dark Night - actors: APT-C-23, Sandworm"; `+
+		`classtype:trojan-activity; priority:2; metadata:indicator_id %s, confidence 80; sid:%d; rev:1709294400;)`, ind.ID, SID(ind.ID, 0)), rules[0])
+}
+
+func TestSuricataWriter_Domain(t *testing.T) {
+	ind := attributed("550e8400-e29b-41d4-a716-446655440001", model.IndicatorTypeDomain, "Evil.Example")
+
+	rules := renderRules(t, ind)
+
+	require.Len(t, rules, 2)
+	assert.Contains(t, rules[0], `alert dns $HOME_NET any -> any any`)
+	assert.Contains(t, rules[0], `dns.query; dotprefix; content:".evil.example"; nocase; endswith;`)
+	assert.Contains(t, rules[0], fmt.Sprintf("sid:%d;", SID(ind.ID, 0)))
+	assert.Contains(t, rules[1], `alert tls $HOME_NET any -> any any`)
+	assert.Contains(t, rules[1], `tls.sni; dotprefix; content:".evil.example"; nocase; endswith;`)
+	assert.Contains(t, rules[1], fmt.Sprintf("sid:%d;", SID(ind.ID, 1)))
+}
+
+func TestSuricataWriter_URLEscaping(t *testing.T) {
+	ind := attributed("550e8400-e29b-41d4-a716-446655440002", model.IndicatorTypeURL, `https://Evil.Example/a;b?q="x"`)
+
+	rules := renderRules(t, ind)
+
+	require.Len(t, rules, 1)
+	assert.Contains(t, rules[0], `msg:"ThreatIntel url https://Evil.Example/a\;b?q=\"x\""`)
+	assert.Contains(t, rules[0], `http.host; content:"evil.example"; http.uri; content:"/a|3B|b?q=|22|x|22|"; startswith;`)
+}
+
+func TestSuricataWriter_HashListRulesOncePerAlgorithm(t *testing.T) {
+	rules := renderRules(t,
+		attributed("1", model.IndicatorTypeHash, "d41d8cd98f00b204e9800998ecf8427e"),
+		attributed("2", model.IndicatorTypeHash, "0cc175b9c0f1b6a831c399e269772661"),
+		attributed("3", model.IndicatorTypeHash, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"),
+		attributed("4", model.IndicatorTypeHash, "not-a-hash"),
+	)
+
+	require.Len(t, rules, 2)
+	assert.Contains(t, rules[0], "filemd5:threat-intel-md5.list;")
+	assert.Contains(t, rules[1], "filesha256:threat-intel-sha256.list;")
+}
+
+func TestHashListWriter_UsesStoredAlgorithm(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewHashListWriter(HashLists[ListMD5], &buf)
+
+	imphash := attributed("1", model.IndicatorTypeHash, "f34d5f2d4577ed6d9ceec516c1f5a744")
+	imphash.HashAlgo = model.HashAlgoImphash
+	require.NoError(t, w.Encode(&imphash))
+	require.NoError(t, w.Flush())
+
+	assert.Empty(t, buf.String())
+}
+
+func TestSuricataWriter_SkipsInvalidValues(t *testing.T) {
+	rules := renderRules(t,
+		attributed("1", model.IndicatorTypeIP, "not-an-ip"),
+		attributed("2", model.IndicatorTypeDomain, "bad domain"),
+		attributed("3", model.IndicatorTypeURL, "http://"),
+	)
+
+	assert.Empty(t, rules)
+}
+
+func TestSuricataWriter_SkipsSIDCollisions(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewSuricataWriter(&buf)
+	first := attributed("550e8400-e29b-41d4-a716-446655440000", model.IndicatorTypeIP, "203.0.113.7")
+	second := attributed("550e8400-e29b-41d4-a716-446655440001", model.IndicatorTypeIP, "203.0.113.8")
+	w.buckets[bucketFor(second.ID)] = first.ID
+
+	require.NoError(t, w.Encode(&second))
+	require.NoError(t, w.Flush())
+
+	assert.Equal(t, fmt.Sprintf("# skipped indicator %s: sid %d is already used by indicator %s\n", second.ID, SID(second.ID, 0), first.ID), buf.String())
+}
+
+func TestSuricataWriter_SIDIndependentOfOtherIndicators(t *testing.T) {
+	ind := attributed("550e8400-e29b-41d4-a716-446655440000", model.IndicatorTypeIP, "203.0.113.7")
+	other := attributed("550e8400-e29b-41d4-a716-446655440001", model.IndicatorTypeIP, "203.0.113.8")
+
+	alone := renderRules(t, ind)
+	after := renderRules(t, other, ind)
+
+	require.Len(t, alone, 1)
+	require.Len(t, after, 2)
+	assert.Equal(t, alone[0], after[1])
+}
+
+func TestHashListWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewHashListWriter(HashLists[ListMD5], &buf)
+
+	for _, ind := range []model.AttributedIndicator{
+		attributed("1", model.IndicatorTypeHash, "D41D8CD98F00B204E9800998ECF8427E"),
+		attributed("2", model.IndicatorTypeHash, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"),
+		attributed("3", model.IndicatorTypeIP, "203.0.113.7"),
+	} {
+		require.NoError(t, w.Encode(&ind))
+	}
+	require.NoError(t, w.Flush())
+
+	assert.Equal(t, "d41d8cd98f00b204e9800998ecf8427e\n", buf.String())
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+)
+
+const maxActorAliases = 100
+
+type ActorService struct {
+	repo  repository.ActorRepositoryInterface
+	cache *cache.Cache
+	audit *AuditService
+}
+
+func NewActorService(repo repository.ActorRepositoryInterface, c *cache.Cache, audit *AuditService) *ActorService {
+	return &ActorService{
+		repo:  repo,
+		cache: c,
+		audit: audit,
+	}
+}
+
+func (s *ActorService) List(ctx context.Context, params model.ActorListParams) (*model.ActorListResult, error) {
+	params.Page, params.Limit = normalizePage(params.Page, params.Limit)
+	return s.repo.List(ctx, params)
+}
+
+func (s *ActorService) GetByID(ctx context.Context, id string) (*model.ThreatActorDetail, error) {
+	cacheKey := cache.GenerateKey(ctx, "actor", map[string]string{"id": id})
+	actor, found := s.cache.Get(cacheKey)
+	if !found {
+		fetched, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(cacheKey, fetched, cache.TTLActorDetail)
+		actor = fetched
+	}
+
+	result := actor.(*model.ThreatActorDetail)
+	if err := s.audit.RecordRead(ctx, model.EntityThreatActor, id, result.MaxTLP); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ActorService) GetIndicators(ctx context.Context, actorID string, params model.ActorIndicatorParams) (*model.ActorIndicatorResult, error) {
+	params.Page, params.Limit = normalizePage(params.Page, params.Limit)
+
+	result, err := s.repo.GetIndicators(ctx, actorID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	tlps := make([]string, len(result.Data))
+	for i, indicator := range result.Data {
+		tlps[i] = indicator.TLP
+	}
+	if err := s.audit.RecordQuery(ctx, model.EntityThreatActor, actorID, params, tlps); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ActorService) SetAliases(ctx context.Context, actorID string, input model.ActorAliasesInput) (*model.ThreatActorDetail, error) {
+	aliases, err := normalizeAliases(input.Aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetAliases(ctx, actorID, aliases); err != nil {
+		return nil, err
+	}
+
+	s.cache.DeleteKey(ctx, "actor", map[string]string{"id": actorID})
+	if err := s.audit.RecordWrite(ctx, model.AuditActionUpdate, model.EntityThreatActor, actorID,
+		map[string][]string{"aliases": current.Aliases}, map[string][]string{"aliases": aliases}); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, actorID)
+}
+
+func normalizeAliases(aliases []string) ([]string, error) {
+	if len(aliases) > maxActorAliases {
+		return nil, newValidationError("aliases", "must contain at most 100 values")
+	}
+
+	seen := make(map[string]bool, len(aliases))
+	normalized := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			return nil, newValidationError("aliases", "must not contain empty values")
+		}
+		if len(alias) > 255 {
+			return nil, newValidationError("aliases", "values must be at most 255 characters")
+		}
+		key := strings.ToLower(alias)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, alias)
+	}
+	return normalized, nil
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
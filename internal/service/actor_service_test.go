@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupActorService(t *testing.T) (*ActorService, *MockActorRepository, *cache.Cache) {
+	mockRepo := new(MockActorRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	audit, _ := newMockAuditService()
+	svc := NewActorService(mockRepo, c, audit)
+	return svc, mockRepo, c
+}
+
+func TestActorService_List_DefaultsPagination(t *testing.T) {
+	svc, mockRepo, _ := setupActorService(t)
+	ctx := context.Background()
+
+	expectedParams := model.ActorListParams{Country: "RU", Page: 1, Limit: 100}
+	mockRepo.On("List", ctx, expectedParams).Return(&model.ActorListResult{Page: 1, Limit: 100}, nil)
+
+	_, err := svc.List(ctx, model.ActorListParams{Country: "RU", Page: 0, Limit: 500})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestActorService_GetByID_UsesCache(t *testing.T) {
+	svc, mockRepo, _ := setupActorService(t)
+	ctx := context.Background()
+
+	expected := &model.ThreatActorDetail{ThreatActor: model.ThreatActor{ID: "actor-1", Name: "APT28"}}
+	mockRepo.On("GetByID", ctx, "actor-1").Return(expected, nil).Once()
+
+	result, err := svc.GetByID(ctx, "actor-1")
+	require.NoError(t, err)
+	assert.Equal(t, "APT28", result.Name)
+
+	time.Sleep(20 * time.Millisecond)
+
+	result, err = svc.GetByID(ctx, "actor-1")
+	require.NoError(t, err)
+	assert.Equal(t, "APT28", result.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestActorService_AuditsSensitiveReads(t *testing.T) {
+	mockRepo := new(MockActorRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	auditRepo := new(MockAuditRepository)
+	svc := NewActorService(mockRepo, c, NewAuditService(auditRepo))
+	ctx := context.Background()
+
+	mockRepo.On("GetByID", ctx, "actor-1").Return(&model.ThreatActorDetail{ThreatActor: model.ThreatActor{ID: "actor-1"}, MaxTLP: "red"}, nil).Once()
+	mockRepo.On("GetByID", ctx, "actor-2").Return(&model.ThreatActorDetail{ThreatActor: model.ThreatActor{ID: "actor-2"}, MaxTLP: "green"}, nil).Once()
+	mockRepo.On("GetIndicators", ctx, "actor-1", model.ActorIndicatorParams{Page: 1, Limit: 20}).Return(&model.ActorIndicatorResult{
+		Data: []model.ActorIndicator{{Indicator: model.Indicator{ID: "ind-1", TLP: "green"}}, {Indicator: model.Indicator{ID: "ind-2", TLP: "amber"}}},
+	}, nil)
+
+	auditRepo.On("Create", ctx, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditActionRead && e.EntityType == model.EntityThreatActor && e.EntityID == "actor-1"
+	})).Return(nil).Times(3)
+
+	for i := 0; i < 2; i++ {
+		_, err = svc.GetByID(ctx, "actor-1")
+		require.NoError(t, err)
+		time.Sleep(20 * time.Millisecond)
+	}
+	_, err = svc.GetByID(ctx, "actor-2")
+	require.NoError(t, err)
+	_, err = svc.GetIndicators(ctx, "actor-1", model.ActorIndicatorParams{})
+	require.NoError(t, err)
+
+	auditRepo.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestActorService_GetByID_NotFound(t *testing.T) {
+	svc, mockRepo, _ := setupActorService(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByID", ctx, "missing").Return(nil, repository.ErrNotFound)
+
+	result, err := svc.GetByID(ctx, "missing")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestActorService_SetAliases_NormalizesAndInvalidates(t *testing.T) {
+	svc, mockRepo, c := setupActorService(t)
+	ctx := context.Background()
+
+	stale := &model.ThreatActorDetail{ThreatActor: model.ThreatActor{ID: "actor-1"}}
+	c.Set(cache.GenerateKey(ctx, "actor", map[string]string{"id": "actor-1"}), stale, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	updated := &model.ThreatActorDetail{ThreatActor: model.ThreatActor{ID: "actor-1", Aliases: []string{"APT28", "Fancy Bear"}}}
+	mockRepo.On("SetAliases", ctx, "actor-1", []string{"APT28", "Fancy Bear"}).Return(nil)
+	mockRepo.On("GetByID", ctx, "actor-1").Return(updated, nil)
+
+	result, err := svc.SetAliases(ctx, "actor-1", model.ActorAliasesInput{Aliases: []string{" APT28 ", "Fancy Bear", "apt28"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"APT28", "Fancy Bear"}, result.Aliases)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestActorService_SetAliases_ValidationErrors(t *testing.T) {
+	svc, mockRepo, _ := setupActorService(t)
+	ctx := context.Background()
+
+	tests := map[string][]string{
+		"empty alias": {"APT28", "  "},
+		"too long":    {strings.Repeat("a", 256)},
+	}
+
+	for name, aliases := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.SetAliases(ctx, "actor-1", model.ActorAliasesInput{Aliases: aliases})
+
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "aliases", validationErr.Field)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "SetAliases")
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+)
+
+type APIKeyService struct {
+	repo  repository.APIKeyRepositoryInterface
+	cache *cache.Cache
+	audit *AuditService
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepositoryInterface, c *cache.Cache, audit *AuditService) *APIKeyService {
+	return &APIKeyService{
+		repo:  repo,
+		cache: c,
+		audit: audit,
+	}
+}
+
+func (s *APIKeyService) Create(ctx context.Context, input model.APIKeyInput) (*model.IssuedAPIKey, error) {
+	name := strings.TrimSpace(input.Name)
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	if len(input.Scopes) == 0 {
+		return nil, newValidationError("scopes", "at least one scope is required")
+	}
+	scopes, err := auth.ParseScopes(input.Scopes)
+	if err != nil {
+		return nil, newValidationError("scopes", "must contain only: read, write, export, admin")
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, newValidationError("expires_at", "must be in the future")
+	}
+	maxTLP := marking.Green
+	if input.MaxTLP != "" {
+		level, ok := marking.Parse(input.MaxTLP)
+		if !ok {
+			return nil, newValidationError("max_tlp", "must be one of: clear, green, amber, red")
+		}
+		maxTLP = level
+	}
+	if !marking.ClearanceFromContext(ctx).Allows(maxTLP) {
+		return nil, newValidationError("max_tlp", "must not exceed your clearance")
+	}
+
+	plaintext, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &model.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		Scopes:    scopeStrings(scopes),
+		MaxTLP:    string(maxTLP),
+		ExpiresAt: input.ExpiresAt,
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		key.CreatedBy = principal.String()
+	}
+
+	if err := s.repo.Create(ctx, key, auth.HashAPIKey(plaintext)); err != nil {
+		return nil, err
+	}
+
+	if err := s.audit.RecordWrite(ctx, model.AuditActionCreate, model.EntityAPIKey, key.ID, nil, key); err != nil {
+		return nil, err
+	}
+	return &model.IssuedAPIKey{APIKey: *key, Key: plaintext}, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]model.APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id string) (*model.APIKey, error) {
+	key, err := s.repo.Revoke(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.DeletePrefix("api_key")
+	if err := s.audit.RecordWrite(ctx, model.AuditActionRevoke, model.EntityAPIKey, id,
+		map[string]interface{}{"revoked_at": nil}, map[string]interface{}{"revoked_at": key.RevokedAt}); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *APIKeyService) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	if !auth.LooksLikeAPIKey(token) {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	hash := auth.HashAPIKey(token)
+	cacheKey := cache.GenerateKey(ctx, "api_key", map[string]string{"hash": hash})
+
+	var key *model.APIKey
+	if cached, found := s.cache.Get(cacheKey); found {
+		key = cached.(*model.APIKey)
+	} else {
+		stored, err := s.repo.GetActiveByHash(ctx, hash)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, auth.ErrInvalidCredentials
+		}
+		if err != nil {
+			return nil, err
+		}
+		key = stored
+		s.cache.Set(cacheKey, key, cache.TTLAPIKey)
+	}
+
+	if !key.IsUsable(time.Now()) {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	scopes := make([]auth.Scope, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, auth.Scope(scope))
+	}
+
+	return &auth.Principal{
+		ID:        key.ID,
+		Name:      key.Name,
+		Type:      auth.PrincipalAPIKey,
+		TenantID:  key.TenantID,
+		Scopes:    scopes,
+		Clearance: marking.Level(key.MaxTLP),
+	}, nil
+}
+
+func scopeStrings(scopes []auth.Scope) []string {
+	values := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		values = append(values, string(scope))
+	}
+	return values
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAPIKeyService(t *testing.T) (*APIKeyService, *MockAPIKeyRepository) {
+	mockRepo := new(MockAPIKeyRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	audit, _ := newMockAuditService()
+	return NewAPIKeyService(mockRepo, c, audit), mockRepo
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	svc, mockRepo := setupAPIKeyService(t)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "admin-1", Type: auth.PrincipalAPIKey})
+
+	var storedHash string
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(k *model.APIKey) bool {
+		return k.Name == "siem" && assert.ObjectsAreEqual([]string{"read", "export"}, k.Scopes) && k.CreatedBy == "api_key:admin-1" && k.MaxTLP == "green"
+	}), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		storedHash = args.String(2)
+		args.Get(1).(*model.APIKey).ID = "key-1"
+	}).Return(nil)
+
+	issued, err := svc.Create(ctx, model.APIKeyInput{Name: " siem ", Scopes: []string{"read", "EXPORT", "read"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", issued.ID)
+	assert.Equal(t, auth.HashAPIKey(issued.Key), storedHash)
+	assert.NotContains(t, storedHash, issued.Key)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Create_ValidationErrors(t *testing.T) {
+	svc, mockRepo := setupAPIKeyService(t)
+	past := time.Now().Add(-time.Hour)
+
+	tests := map[string]struct {
+		input model.APIKeyInput
+		field string
+	}{
+		"missing name":    {model.APIKeyInput{Scopes: []string{"read"}}, "name"},
+		"no scopes":       {model.APIKeyInput{Name: "siem"}, "scopes"},
+		"unknown scope":   {model.APIKeyInput{Name: "siem", Scopes: []string{"root"}}, "scopes"},
+		"already expired": {model.APIKeyInput{Name: "siem", Scopes: []string{"read"}, ExpiresAt: &past}, "expires_at"},
+		"unknown max tlp": {model.APIKeyInput{Name: "siem", Scopes: []string{"read"}, MaxTLP: "purple"}, "max_tlp"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Create(context.Background(), tt.input)
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestAPIKeyService_Create_CannotExceedCreatorClearance(t *testing.T) {
+	svc, mockRepo := setupAPIKeyService(t)
+	ctx := marking.WithClearance(context.Background(), marking.Amber)
+
+	_, err := svc.Create(ctx, model.APIKeyInput{Name: "siem", Scopes: []string{"read"}, MaxTLP: "TLP:RED"})
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "max_tlp", validationErr.Field)
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	svc, mockRepo := setupAPIKeyService(t)
+	ctx := context.Background()
+
+	token, _, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+
+	mockRepo.On("GetActiveByHash", ctx, auth.HashAPIKey(token)).Return(&model.APIKey{
+		ID: "key-1", Name: "siem", TenantID: "emea", Scopes: []string{"read", "export"}, MaxTLP: "amber",
+	}, nil).Once()
+
+	principal, err := svc.Authenticate(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", principal.ID)
+	assert.Equal(t, "emea", principal.TenantID)
+	assert.Equal(t, marking.Amber, principal.Clearance)
+	assert.True(t, principal.HasScope(auth.ScopeExport))
+	assert.False(t, principal.HasScope(auth.ScopeWrite))
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = svc.Authenticate(ctx, token)
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAPIKeyService_Authenticate_Rejected(t *testing.T) {
+	svc, mockRepo := setupAPIKeyService(t)
+	ctx := context.Background()
+
+	token, _, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	mockRepo.On("GetActiveByHash", ctx, auth.HashAPIKey(token)).Return(nil, repository.ErrNotFound)
+
+	_, err = svc.Authenticate(ctx, token)
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = svc.Authenticate(ctx, "not-an-api-key")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+}
+
+func TestAPIKeyService_Authenticate_ExpiredWhileCached(t *testing.T) {
+	svc, mockRepo := setupAPIKeyService(t)
+	ctx := context.Background()
+
+	token, _, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	expiresAt := time.Now().Add(-time.Second)
+	svc.cache.Set(cache.GenerateKey(ctx, "api_key", map[string]string{"hash": auth.HashAPIKey(token)}),
+		&model.APIKey{ID: "key-1", Scopes: []string{"read"}, ExpiresAt: &expiresAt}, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = svc.Authenticate(ctx, token)
+
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	mockRepo.AssertNotCalled(t, "GetActiveByHash")
+}
+
+func TestAPIKeyService_Revoke_ClearsCache(t *testing.T) {
+	svc, mockRepo := setupAPIKeyService(t)
+	ctx := context.Background()
+
+	token, _, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	key := cache.GenerateKey(ctx, "api_key", map[string]string{"hash": auth.HashAPIKey(token)})
+	svc.cache.Set(key, &model.APIKey{ID: "key-1", Scopes: []string{"read"}}, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	now := time.Now()
+	mockRepo.On("Revoke", ctx, "key-1").Return(&model.APIKey{ID: "key-1", RevokedAt: &now}, nil)
+
+	_, err = svc.Revoke(ctx, "key-1")
+	require.NoError(t, err)
+
+	_, found := svc.cache.Get(key)
+	assert.False(t, found)
+}
+
+func TestAPIKeyService_Authenticate_RepositoryError(t *testing.T) {
+	svc, mockRepo := setupAPIKeyService(t)
+	ctx := context.Background()
+
+	token, _, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+	mockRepo.On("GetActiveByHash", ctx, auth.HashAPIKey(token)).Return(nil, errors.New("connection refused"))
+
+	_, err = svc.Authenticate(ctx, token)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, auth.ErrInvalidCredentials)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+)
+
+const principalAnonymous = "anonymous"
+
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+type AuditService struct {
+	repo repository.AuditRepositoryInterface
+}
+
+func NewAuditService(repo repository.AuditRepositoryInterface) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+func (s *AuditService) List(ctx context.Context, params model.AuditListParams) (*model.AuditListResult, error) {
+	if params.Action != "" && !model.IsValidAuditAction(params.Action) {
+		return nil, newValidationError("action", "unknown audit action")
+	}
+	if params.Since != nil && params.Until != nil && !params.Until.After(*params.Since) {
+		return nil, newValidationError("until", "must be after since")
+	}
+	params.Page, params.Limit = normalizePage(params.Page, params.Limit)
+
+	result, err := s.repo.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// The event itself stays visible so the trail has no gaps, but the state
+	// and query it carries are only shown to callers cleared for its marking.
+	clearance := marking.ClearanceFromContext(ctx)
+	for i := range result.Data {
+		event := &result.Data[i]
+		if !clearance.Allows(marking.Level(event.TLP)) {
+			event.Changes, event.Params, event.Redacted = nil, nil, true
+		}
+	}
+	return result, nil
+}
+
+// RecordWrite runs after the write has been committed. Its error is returned
+// to the caller, so a change is never reported as successful without an
+// audit record.
+func (s *AuditService) RecordWrite(ctx context.Context, action, entityType, entityID string, before, after interface{}) error {
+	event := model.AuditEvent{Action: action, EntityType: entityType, EntityID: entityID}
+
+	var current map[string]interface{}
+	old, err := auditFields(before)
+	if err == nil {
+		current, err = auditFields(after)
+	}
+	if err == nil {
+		event.TLP = auditStateTLP(old, current)
+		event.Changes, err = auditDiff(old, current)
+	}
+	if err == nil {
+		err = s.record(ctx, &event)
+	}
+	if err != nil {
+		slog.Error("Failed to record audit event", "error", err,
+			"action", action, "entity_type", entityType, "entity_id", entityID)
+	}
+	return err
+}
+
+func (s *AuditService) RecordExport(ctx context.Context, entityType, entityID string, params interface{}) error {
+	event := model.AuditEvent{Action: model.AuditActionExport, EntityType: entityType, EntityID: entityID}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit params: %w", err)
+		}
+		event.Params = data
+	}
+	return s.record(ctx, &event)
+}
+
+func (s *AuditService) RecordImport(ctx context.Context, entityType string, params interface{}) error {
+	event := model.AuditEvent{Action: model.AuditActionImport, EntityType: entityType}
+
+	data, err := json.Marshal(params)
+	if err == nil {
+		event.Params = data
+		err = s.record(ctx, &event)
+	}
+	if err != nil {
+		slog.Error("Failed to record audit event", "error", err, "action", event.Action, "entity_type", entityType)
+	}
+	return err
+}
+
+func (s *AuditService) RecordRead(ctx context.Context, entityType, entityID, tlp string) error {
+	if marking.Green.Allows(marking.Level(tlp)) {
+		return nil
+	}
+	return s.record(ctx, &model.AuditEvent{Action: model.AuditActionRead, EntityType: entityType, EntityID: entityID, TLP: tlp})
+}
+
+// RecordQuery audits a read returning many objects, such as a search page,
+// as one event carrying the query when any of them is above TLP:GREEN.
+func (s *AuditService) RecordQuery(ctx context.Context, entityType, entityID string, params interface{}, tlps []string) error {
+	highest := marking.Clear
+	for _, tlp := range tlps {
+		highest = marking.Max(highest, marking.Level(tlp))
+	}
+	if marking.Green.Allows(highest) {
+		return nil
+	}
+
+	event := model.AuditEvent{Action: model.AuditActionRead, EntityType: entityType, EntityID: entityID, TLP: string(highest)}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit params: %w", err)
+	}
+	event.Params = data
+	return s.record(ctx, &event)
+}
+
+// record marks an event that carries no TLP of its own, such as an export
+// query or an import, with the caller's clearance, the most it can reveal.
+func (s *AuditService) record(ctx context.Context, event *model.AuditEvent) error {
+	if event.TLP == "" {
+		event.TLP = string(marking.ClearanceFromContext(ctx))
+	}
+	event.PrincipalType, event.PrincipalID = principalAnonymous, principalAnonymous
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		event.PrincipalType = principal.Type
+		event.PrincipalID = principal.ID
+		event.PrincipalName = principal.Name
+	}
+	return s.repo.Create(ctx, event)
+}
+
+func auditDiff(old, current map[string]interface{}) (json.RawMessage, error) {
+	changes := make(map[string]model.AuditChange)
+	for field, value := range current {
+		if !auditIgnoredFields[field] && !reflect.DeepEqual(old[field], value) {
+			changes[field] = model.AuditChange{Before: old[field], After: value}
+		}
+	}
+	for field, value := range old {
+		if _, ok := current[field]; !ok && !auditIgnoredFields[field] {
+			changes[field] = model.AuditChange{Before: value}
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(changes)
+}
+
+// auditStateTLP returns the higher marking of the before and after state, or
+// "" when neither is a marked object.
+func auditStateTLP(states ...map[string]interface{}) string {
+	var tlp marking.Level
+	for _, state := range states {
+		if level, ok := state["tlp"].(string); ok {
+			tlp = marking.Max(tlp, marking.Level(level))
+		}
+	}
+	return string(tlp)
+}
+
+func auditFields(v interface{}) (map[string]interface{}, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit state: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode audit state: %w", err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/auth"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_RecordWrite_StoresDiffAndPrincipal(t *testing.T) {
+	repo := new(MockAuditRepository)
+	svc := NewAuditService(repo)
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "key-1", Name: "ci", Type: auth.PrincipalAPIKey})
+
+	var recorded *model.AuditEvent
+	repo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = args.Get(1).(*model.AuditEvent)
+	}).Return(nil)
+
+	before := &model.Indicator{ID: "ind-1", Value: "10.0.0.1", Severity: "low", UpdatedAt: time.Now()}
+	after := &model.Indicator{ID: "ind-1", Value: "10.0.0.1", Severity: "high", UpdatedAt: time.Now().Add(time.Minute)}
+	svc.RecordWrite(ctx, model.AuditActionUpdate, model.EntityIndicator, "ind-1", before, after)
+
+	require.NotNil(t, recorded)
+	assert.Equal(t, auth.PrincipalAPIKey, recorded.PrincipalType)
+	assert.Equal(t, "key-1", recorded.PrincipalID)
+	assert.Equal(t, "ci", recorded.PrincipalName)
+	assert.Equal(t, model.AuditActionUpdate, recorded.Action)
+	assert.Equal(t, "ind-1", recorded.EntityID)
+
+	var changes map[string]model.AuditChange
+	require.NoError(t, json.Unmarshal(recorded.Changes, &changes))
+	assert.Equal(t, map[string]model.AuditChange{"severity": {Before: "low", After: "high"}}, changes)
+}
+
+func TestAuditService_RecordWrite_ReturnsRepositoryFailure(t *testing.T) {
+	repo := new(MockAuditRepository)
+	svc := NewAuditService(repo)
+	ctx := context.Background()
+
+	repo.On("Create", ctx, mock.Anything).Return(errors.New("db down"))
+
+	err := svc.RecordWrite(ctx, model.AuditActionDelete, model.EntityCampaign, "camp-1", &model.Campaign{ID: "camp-1"}, nil)
+
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestAuditService_RecordRead_OnlyAboveGreen(t *testing.T) {
+	repo := new(MockAuditRepository)
+	svc := NewAuditService(repo)
+	ctx := context.Background()
+
+	repo.On("Create", ctx, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditActionRead && e.EntityID == "amber-1" && e.PrincipalID == principalAnonymous
+	})).Return(nil).Once()
+
+	require.NoError(t, svc.RecordRead(ctx, model.EntityIndicator, "green-1", "green"))
+	require.NoError(t, svc.RecordRead(ctx, model.EntityIndicator, "amber-1", "amber"))
+	repo.AssertExpectations(t)
+}
+
+func TestAuditService_RecordQuery_StoresParamsAboveGreen(t *testing.T) {
+	repo := new(MockAuditRepository)
+	svc := NewAuditService(repo)
+	ctx := context.Background()
+
+	repo.On("Create", ctx, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditActionRead && e.EntityType == model.EntityIndicator && string(e.Params) == `{"q":"apt28"}`
+	})).Return(nil).Once()
+
+	params := map[string]string{"q": "apt28"}
+	require.NoError(t, svc.RecordQuery(ctx, model.EntityIndicator, "", params, []string{"clear", "green"}))
+	require.NoError(t, svc.RecordQuery(ctx, model.EntityIndicator, "", params, nil))
+	require.NoError(t, svc.RecordQuery(ctx, model.EntityIndicator, "", params, []string{"green", "red"}))
+	repo.AssertExpectations(t)
+}
+
+func TestIndicatorService_Search_AuditsCacheHits(t *testing.T) {
+	mockRepo := new(MockIndicatorRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	auditRepo := new(MockAuditRepository)
+	svc := NewIndicatorService(mockRepo, c, NewAuditService(auditRepo))
+	ctx := context.Background()
+
+	params := model.SearchParams{Type: "ip", Page: 1, Limit: 20}
+	mockRepo.On("Search", ctx, params).Return(&model.SearchResult{
+		Data: []model.IndicatorSearchResult{{ID: "ind-1", TLP: "amber"}},
+	}, nil).Once()
+	auditRepo.On("Create", ctx, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditActionRead && e.EntityType == model.EntityIndicator
+	})).Return(nil).Twice()
+
+	_, err = svc.Search(ctx, params)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	_, err = svc.Search(ctx, params)
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	auditRepo.AssertExpectations(t)
+}
+
+func TestAuditService_RecordWrite_MarksEventWithStateTLP(t *testing.T) {
+	repo := new(MockAuditRepository)
+	svc := NewAuditService(repo)
+	ctx := marking.WithClearance(context.Background(), marking.Amber)
+
+	var recorded []*model.AuditEvent
+	repo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = append(recorded, args.Get(1).(*model.AuditEvent))
+	}).Return(nil)
+
+	svc.RecordWrite(ctx, model.AuditActionUpdate, model.EntityIndicator, "ind-1",
+		&model.Indicator{ID: "ind-1", TLP: "green"}, &model.Indicator{ID: "ind-1", TLP: "red"})
+	svc.RecordWrite(ctx, model.AuditActionLink, model.EntityIndicator, "ind-1", nil,
+		&model.IndicatorCampaignLink{IndicatorID: "ind-1", CampaignID: "camp-1"})
+
+	require.Len(t, recorded, 2)
+	assert.Equal(t, "red", recorded[0].TLP)
+	assert.Equal(t, "amber", recorded[1].TLP, "state without a marking takes the caller's clearance")
+}
+
+func TestAuditService_List_RedactsEventsAboveClearance(t *testing.T) {
+	repo := new(MockAuditRepository)
+	svc := NewAuditService(repo)
+	ctx := marking.WithClearance(context.Background(), marking.Green)
+
+	changes := json.RawMessage(`{"value":{"before":"10.0.0.1","after":"10.0.0.2"}}`)
+	repo.On("List", ctx, model.AuditListParams{Page: 1, Limit: 20}).Return(&model.AuditListResult{
+		Data: []model.AuditEvent{
+			{ID: 2, Action: model.AuditActionUpdate, EntityType: model.EntityIndicator, EntityID: "red-1", TLP: "red", Changes: changes},
+			{ID: 1, Action: model.AuditActionUpdate, EntityType: model.EntityIndicator, EntityID: "green-1", TLP: "green", Changes: changes},
+		},
+	}, nil)
+
+	result, err := svc.List(ctx, model.AuditListParams{})
+
+	require.NoError(t, err)
+	require.Len(t, result.Data, 2)
+	assert.Equal(t, "red-1", result.Data[0].EntityID)
+	assert.True(t, result.Data[0].Redacted)
+	assert.Nil(t, result.Data[0].Changes)
+	assert.False(t, result.Data[1].Redacted)
+	assert.JSONEq(t, string(changes), string(result.Data[1].Changes))
+}
+
+func TestAuditService_List_Validation(t *testing.T) {
+	svc := NewAuditService(new(MockAuditRepository))
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		params model.AuditListParams
+		field  string
+	}{
+		{"unknown action", model.AuditListParams{Action: "explode"}, "action"},
+		{"until before since", model.AuditListParams{Since: &now, Until: &earlier}, "until"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.List(context.Background(), tt.params)
+
+			assert.Nil(t, result)
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
+
+func TestIndicatorService_Create_FailsWithoutAudit(t *testing.T) {
+	mockRepo := new(MockIndicatorRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	auditRepo := new(MockAuditRepository)
+	svc := NewIndicatorService(mockRepo, c, NewAuditService(auditRepo))
+	ctx := context.Background()
+
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Indicator")).Return(nil)
+	auditRepo.On("Create", ctx, mock.Anything).Return(errors.New("db down"))
+
+	indicatorType := model.IndicatorTypeIP
+	value := "10.0.0.1"
+	result, err := svc.Create(ctx, model.IndicatorInput{Type: &indicatorType, Value: &value})
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	auditRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_Export_FailsClosedWithoutAudit(t *testing.T) {
+	mockRepo := new(MockIndicatorRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	auditRepo := new(MockAuditRepository)
+	svc := NewIndicatorService(mockRepo, c, NewAuditService(auditRepo))
+	ctx := context.Background()
+
+	auditRepo.On("Create", ctx, mock.Anything).Return(errors.New("db down"))
+
+	err = svc.Export(ctx, model.SearchParams{Type: "ip"}, func(*model.Indicator) error { return nil })
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "StreamSearch", mock.Anything, mock.Anything, mock.Anything)
+}
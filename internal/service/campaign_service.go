@@ -2,21 +2,29 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/google/uuid"
 )
 
+const maxCampaignTargets = 100
+
 type CampaignService struct {
 	repo  repository.CampaignRepositoryInterface
 	cache *cache.Cache
+	audit *AuditService
 }
 
-func NewCampaignService(repo repository.CampaignRepositoryInterface, c *cache.Cache) *CampaignService {
+func NewCampaignService(repo repository.CampaignRepositoryInterface, c *cache.Cache, audit *AuditService) *CampaignService {
 	return &CampaignService{
 		repo:  repo,
 		cache: c,
+		audit: audit,
 	}
 }
 
@@ -28,21 +36,280 @@ func (s *CampaignService) GetIndicatorsTimeline(ctx context.Context, campaignID
 		params.GroupBy = "day"
 	}
 
-	cacheKey := cache.GenerateKey("campaign_timeline", map[string]interface{}{
+	cacheKey := cache.GenerateKey(ctx, "campaign_timeline", map[string]interface{}{
 		"id":         campaignID,
 		"group_by":   params.GroupBy,
 		"start_date": params.StartDate,
 		"end_date":   params.EndDate,
 	})
-	if cached, found := s.cache.Get(cacheKey); found {
-		return cached.(*model.CampaignWithTimeline), nil
+	timeline, found := s.cache.Get(cacheKey)
+	if !found {
+		fetched, err := s.repo.GetIndicatorsTimeline(ctx, campaignID, params)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(cacheKey, fetched, cache.TTLCampaignTimeline)
+		timeline = fetched
+	}
+
+	result := timeline.(*model.CampaignWithTimeline)
+	tlps := []string{result.Campaign.TLP}
+	for _, period := range result.Timeline {
+		for _, indicator := range period.Indicators {
+			tlps = append(tlps, indicator.TLP)
+		}
+	}
+	if err := s.audit.RecordQuery(ctx, model.EntityCampaign, campaignID, params, tlps); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *CampaignService) List(ctx context.Context, params model.CampaignListParams) (*model.CampaignListResult, error) {
+	params.Page, params.Limit = normalizePage(params.Page, params.Limit)
+	return s.repo.List(ctx, params)
+}
+
+func (s *CampaignService) GetByID(ctx context.Context, id string) (*model.Campaign, error) {
+	cacheKey := cache.GenerateKey(ctx, "campaign", map[string]string{"id": id})
+	campaign, found := s.cache.Get(cacheKey)
+	if !found {
+		fetched, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(cacheKey, fetched, cache.TTLCampaignDetail)
+		campaign = fetched
+	}
+
+	result := campaign.(*model.Campaign)
+	if err := s.audit.RecordRead(ctx, model.EntityCampaign, id, result.TLP); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *CampaignService) Create(ctx context.Context, input model.CampaignInput) (*model.Campaign, error) {
+	campaign := newCampaignWithDefaults()
+	applyCampaignInput(campaign, input)
+
+	if err := validateCampaignFor(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	if err := s.audit.RecordWrite(ctx, model.AuditActionCreate, model.EntityCampaign, campaign.ID, nil, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (s *CampaignService) Update(ctx context.Context, id string, input model.CampaignInput) (*model.Campaign, error) {
+	campaign := newCampaignWithDefaults()
+	campaign.ID = id
+	applyCampaignInput(campaign, input)
+
+	if err := validateCampaignFor(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, campaign); err != nil {
+		return nil, err
 	}
 
-	timeline, err := s.repo.GetIndicatorsTimeline(ctx, campaignID, params)
+	s.invalidate()
+	if err := s.audit.RecordWrite(ctx, model.AuditActionUpdate, model.EntityCampaign, id, current, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (s *CampaignService) Patch(ctx context.Context, id string, input model.CampaignInput) (*model.Campaign, error) {
+	campaign, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	s.cache.Set(cacheKey, timeline, cache.TTLCampaignTimeline)
-	return timeline, nil
+	before := *campaign
+	applyCampaignInput(campaign, input)
+
+	if err := validateCampaignFor(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	if err := s.audit.RecordWrite(ctx, model.AuditActionUpdate, model.EntityCampaign, id, &before, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (s *CampaignService) Delete(ctx context.Context, id string) error {
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.invalidate()
+	if err := s.audit.RecordWrite(ctx, model.AuditActionDelete, model.EntityCampaign, id, current, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *CampaignService) LinkIndicator(ctx context.Context, campaignID, indicatorID string, input model.CampaignIndicatorInput) (*model.IndicatorCampaignLink, bool, error) {
+	link, err := newCampaignLink(indicatorID, campaignID, input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	created, err := s.repo.LinkIndicator(ctx, link)
+	if err != nil {
+		return nil, false, err
+	}
+
+	invalidateIndicatorLinks(s.cache)
+	if err := s.audit.RecordWrite(ctx, model.AuditActionLink, model.EntityCampaign, campaignID, nil, link); err != nil {
+		return nil, false, err
+	}
+	return link, created, nil
+}
+
+func (s *CampaignService) UnlinkIndicator(ctx context.Context, campaignID, indicatorID string) error {
+	if err := s.repo.UnlinkIndicator(ctx, campaignID, indicatorID); err != nil {
+		return err
+	}
+
+	invalidateIndicatorLinks(s.cache)
+	if err := s.audit.RecordWrite(ctx, model.AuditActionUnlink, model.EntityCampaign, campaignID,
+		map[string]string{"indicator_id": indicatorID, "campaign_id": campaignID}, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *CampaignService) invalidate() {
+	for _, prefix := range []string{"campaign", "campaign_timeline", "indicator", "dashboard_summary", "actor"} {
+		s.cache.DeletePrefix(prefix)
+	}
+}
+
+func newCampaignWithDefaults() *model.Campaign {
+	return &model.Campaign{
+		Status:   "active",
+		Severity: "medium",
+		TLP:      string(marking.Clear),
+		PAP:      string(marking.Clear),
+	}
+}
+
+func applyCampaignInput(campaign *model.Campaign, input model.CampaignInput) {
+	if input.Name != nil {
+		campaign.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		campaign.Description = *input.Description
+	}
+	if input.Status != nil {
+		campaign.Status = *input.Status
+	}
+	if input.StartDate != nil {
+		campaign.StartDate = input.StartDate
+	}
+	if input.EndDate != nil {
+		campaign.EndDate = input.EndDate
+	}
+	if input.TargetSectors != nil {
+		campaign.TargetSectors = normalizeTags(input.TargetSectors)
+	}
+	if input.TargetRegions != nil {
+		campaign.TargetRegions = normalizeTags(input.TargetRegions)
+	}
+	if input.ThreatActorID != nil {
+		campaign.ThreatActorID = strings.TrimSpace(*input.ThreatActorID)
+	}
+	if input.Severity != nil {
+		campaign.Severity = *input.Severity
+	}
+	if input.TLP != nil {
+		campaign.TLP = normalizeMarking(*input.TLP)
+	}
+	if input.PAP != nil {
+		campaign.PAP = normalizeMarking(*input.PAP)
+	}
+	if input.Metadata != nil {
+		campaign.Metadata = input.Metadata
+	}
+}
+
+func validateCampaignFor(ctx context.Context, campaign *model.Campaign) error {
+	if err := validateCampaign(campaign); err != nil {
+		return err
+	}
+	return checkClearance(ctx, campaign.TLP)
+}
+
+func validateCampaign(campaign *model.Campaign) error {
+	if err := validateName(campaign.Name); err != nil {
+		return err
+	}
+	if !model.IsValidCampaignStatus(campaign.Status) {
+		return newValidationError("status", "must be one of: active, inactive, historical")
+	}
+	if !model.IsValidSeverity(campaign.Severity) {
+		return newValidationError("severity", "must be one of: low, medium, high, critical")
+	}
+	if campaign.StartDate != nil && campaign.EndDate != nil && campaign.EndDate.Before(*campaign.StartDate) {
+		return newValidationError("end_date", "must not be before start_date")
+	}
+	if campaign.ThreatActorID != "" {
+		if _, err := uuid.Parse(campaign.ThreatActorID); err != nil {
+			return newValidationError("threat_actor_id", "must be a valid UUID")
+		}
+	}
+	if err := validateTargets("target_sectors", campaign.TargetSectors); err != nil {
+		return err
+	}
+	if err := validateTargets("target_regions", campaign.TargetRegions); err != nil {
+		return err
+	}
+	if len(campaign.Metadata) > 0 {
+		var metadata map[string]interface{}
+		if err := json.Unmarshal(campaign.Metadata, &metadata); err != nil {
+			return newValidationError("metadata", "must be a JSON object")
+		}
+	}
+	return validateMarkings(campaign.TLP, campaign.PAP)
+}
+
+func validateTargets(field string, targets []string) error {
+	if len(targets) > maxCampaignTargets {
+		return newValidationError(field, "must contain at most 100 values")
+	}
+	for _, target := range targets {
+		if target == "" {
+			return newValidationError(field, "must not contain empty values")
+		}
+		if len(target) > 100 {
+			return newValidationError(field, "values must be at most 100 characters")
+		}
+	}
+	return nil
 }
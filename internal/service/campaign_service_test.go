@@ -9,6 +9,7 @@ import (
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,7 +17,8 @@ func setupCampaignService(t *testing.T) (*CampaignService, *MockCampaignReposito
 	mockRepo := new(MockCampaignRepository)
 	c, err := cache.New(cache.Config{MaxSizeMB: 10})
 	require.NoError(t, err)
-	svc := NewCampaignService(mockRepo, c)
+	audit, _ := newMockAuditService()
+	svc := NewCampaignService(mockRepo, c, audit)
 	return svc, mockRepo, c
 }
 
@@ -159,3 +161,140 @@ func TestCampaignService_GetIndicatorsTimeline_WithDateFilters(t *testing.T) {
 	assert.Len(t, result.Timeline, 1)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCampaignService_List_DefaultsPagination(t *testing.T) {
+	svc, mockRepo, _ := setupCampaignService(t)
+	ctx := context.Background()
+
+	expectedParams := model.CampaignListParams{Status: "active", Page: 1, Limit: 20}
+	mockRepo.On("List", ctx, expectedParams).Return(&model.CampaignListResult{Page: 1, Limit: 20}, nil)
+
+	_, err := svc.List(ctx, model.CampaignListParams{Status: "active"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCampaignService_GetByID_UsesCache(t *testing.T) {
+	svc, mockRepo, _ := setupCampaignService(t)
+	ctx := context.Background()
+
+	expected := &model.Campaign{ID: "camp-1", Name: "Operation Test", TargetSectors: []string{"finance"}}
+	mockRepo.On("GetByID", ctx, "camp-1").Return(expected, nil).Once()
+
+	result, err := svc.GetByID(ctx, "camp-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"finance"}, result.TargetSectors)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = svc.GetByID(ctx, "camp-1")
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCampaignService_Create_AppliesDefaults(t *testing.T) {
+	svc, mockRepo, _ := setupCampaignService(t)
+	ctx := context.Background()
+
+	name := "  Operation Test "
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(c *model.Campaign) bool {
+		return c.Name == "Operation Test" && c.Status == "active" && c.Severity == "medium" &&
+			assert.ObjectsAreEqual([]string{"finance", "energy"}, c.TargetSectors)
+	})).Return(nil)
+
+	result, err := svc.Create(ctx, model.CampaignInput{
+		Name:          &name,
+		TargetSectors: []string{" finance", "energy", "finance"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "active", result.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCampaignService_Create_ValidationErrors(t *testing.T) {
+	svc, mockRepo, _ := setupCampaignService(t)
+	ctx := context.Background()
+
+	name := "Operation Test"
+	status := "paused"
+	actorID := "not-a-uuid"
+	start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		input model.CampaignInput
+		field string
+	}{
+		"missing name":     {model.CampaignInput{}, "name"},
+		"invalid status":   {model.CampaignInput{Name: &name, Status: &status}, "status"},
+		"end before start": {model.CampaignInput{Name: &name, StartDate: &start, EndDate: &end}, "end_date"},
+		"invalid actor":    {model.CampaignInput{Name: &name, ThreatActorID: &actorID}, "threat_actor_id"},
+		"empty region":     {model.CampaignInput{Name: &name, TargetRegions: []string{" "}}, "target_regions"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Create(ctx, tt.input)
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestCampaignService_Patch_InvalidatesDetail(t *testing.T) {
+	svc, mockRepo, c := setupCampaignService(t)
+	ctx := context.Background()
+
+	key := cache.GenerateKey(ctx, "campaign", map[string]string{"id": "camp-1"})
+	c.Set(key, &model.Campaign{ID: "camp-1", Status: "active"}, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	current := &model.Campaign{ID: "camp-1", Name: "Operation Test", Status: "active", Severity: "high", TLP: "green", PAP: "clear"}
+	mockRepo.On("GetByID", ctx, "camp-1").Return(current, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(c *model.Campaign) bool {
+		return c.Status == "historical" && c.Severity == "high"
+	})).Return(nil)
+
+	status := "historical"
+	result, err := svc.Patch(ctx, "camp-1", model.CampaignInput{Status: &status})
+
+	require.NoError(t, err)
+	assert.Equal(t, "historical", result.Status)
+	_, found := c.Get(key)
+	assert.False(t, found)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCampaignService_LinkIndicator(t *testing.T) {
+	svc, mockRepo, _ := setupCampaignService(t)
+	ctx := context.Background()
+
+	mockRepo.On("LinkIndicator", ctx, &model.IndicatorCampaignLink{
+		IndicatorID: "ind-1",
+		CampaignID:  "camp-1",
+		Notes:       "seen in phishing wave",
+	}).Return(true, nil)
+
+	link, created, err := svc.LinkIndicator(ctx, "camp-1", "ind-1", model.CampaignIndicatorInput{Notes: " seen in phishing wave "})
+
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "seen in phishing wave", link.Notes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCampaignService_UnlinkIndicator_NotFound(t *testing.T) {
+	svc, mockRepo, _ := setupCampaignService(t)
+	ctx := context.Background()
+
+	mockRepo.On("UnlinkIndicator", ctx, "camp-1", "ind-1").Return(repository.ErrNotFound)
+
+	err := svc.UnlinkIndicator(ctx, "camp-1", "ind-1")
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
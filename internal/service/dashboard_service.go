@@ -29,7 +29,7 @@ func (s *DashboardService) GetSummary(ctx context.Context, timeRange string) (*m
 		timeRange = "7d"
 	}
 
-	cacheKey := cache.GenerateKey("dashboard_summary", map[string]string{"range": timeRange})
+	cacheKey := cache.GenerateKey(ctx, "dashboard_summary", map[string]string{"range": timeRange})
 	if cached, found := s.cache.Get(cacheKey); found {
 		return cached.(*model.DashboardSummary), nil
 	}
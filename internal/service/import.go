@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+)
+
+func importIntel(ctx context.Context, repo repository.BundleRepositoryInterface, c *cache.Cache, audit *AuditService, format string, data *model.IntelImport, rejected []model.ImportError) (*model.ImportResult, error) {
+	rejected = append(rejected, validateImport(ctx, data)...)
+
+	result, err := repo.Import(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Errors = append(rejected, result.Errors...)
+	if result.Errors == nil {
+		result.Errors = []model.ImportError{}
+	}
+	result.Rejected = len(result.Errors)
+
+	invalidateIndicators(c)
+	if err := audit.RecordImport(ctx, model.EntityBundle, map[string]interface{}{
+		"format":        format,
+		"indicators":    result.Indicators,
+		"campaigns":     result.Campaigns,
+		"threat_actors": result.ThreatActors,
+		"relationships": result.Relationships,
+		"rejected":      result.Rejected,
+		"affected": map[string]model.AffectedIDs{
+			"indicators":    result.Indicators.IDs,
+			"campaigns":     result.Campaigns.IDs,
+			"threat_actors": result.ThreatActors.IDs,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func validateImport(ctx context.Context, data *model.IntelImport) []model.ImportError {
+	var rejected []model.ImportError
+	reject := func(ref, objectType string, err error) {
+		rejected = append(rejected, model.ImportError{Ref: ref, Type: objectType, Reason: err.Error()})
+	}
+
+	indicators := data.Indicators[:0]
+	for _, item := range data.Indicators {
+		item.Indicator.Tags = normalizeTags(item.Indicator.Tags)
+		item.Indicator.TLP, item.Indicator.PAP = defaultMarkings(item.Indicator.TLP, item.Indicator.PAP)
+		if err := validateIndicatorFor(ctx, &item.Indicator); err != nil {
+			reject(item.Ref, model.EntityIndicator, err)
+			continue
+		}
+		indicators = append(indicators, item)
+	}
+	data.Indicators = indicators
+
+	campaigns := data.Campaigns[:0]
+	for _, item := range data.Campaigns {
+		item.Campaign.TLP, item.Campaign.PAP = defaultMarkings(item.Campaign.TLP, item.Campaign.PAP)
+		err := validateName(item.Campaign.Name)
+		if err == nil && item.Campaign.Severity != "" && !model.IsValidSeverity(item.Campaign.Severity) {
+			err = newValidationError("severity", "must be one of: low, medium, high, critical")
+		}
+		if err == nil {
+			err = validateMarkings(item.Campaign.TLP, item.Campaign.PAP)
+		}
+		if err == nil {
+			err = checkClearance(ctx, item.Campaign.TLP)
+		}
+		if err != nil {
+			reject(item.Ref, model.EntityCampaign, err)
+			continue
+		}
+		campaigns = append(campaigns, item)
+	}
+	data.Campaigns = campaigns
+
+	actors := data.ThreatActors[:0]
+	for _, item := range data.ThreatActors {
+		err := validateName(item.ThreatActor.Name)
+		if err == nil {
+			err = validateConfidence(item.ThreatActor.ConfidenceLevel)
+		}
+		if err == nil && len(item.ThreatActor.Country) > 100 {
+			err = newValidationError("country", "must be at most 100 characters")
+		}
+		if err == nil {
+			item.ThreatActor.Aliases, err = normalizeAliases(item.ThreatActor.Aliases)
+		}
+		if err != nil {
+			reject(item.Ref, model.EntityThreatActor, err)
+			continue
+		}
+		actors = append(actors, item)
+	}
+	data.ThreatActors = actors
+
+	relationships := data.Relationships[:0]
+	for _, rel := range data.Relationships {
+		if rel.Confidence != nil {
+			if err := validateConfidence(*rel.Confidence); err != nil {
+				reject(rel.Ref, "relationship", err)
+				continue
+			}
+		}
+		relationships = append(relationships, rel)
+	}
+	data.Relationships = relationships
+
+	return rejected
+}
+
+func defaultMarkings(tlp, pap string) (string, string) {
+	if tlp == "" {
+		tlp = string(marking.Clear)
+	}
+	if pap == "" {
+		pap = string(marking.Clear)
+	}
+	return tlp, pap
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return newValidationError("name", "is required")
+	}
+	if len(name) > 255 {
+		return newValidationError("name", "must be at most 255 characters")
+	}
+	return nil
+}
+
+func validateConfidence(confidence int) error {
+	if confidence < 0 || confidence > 100 {
+		return newValidationError("confidence", "must be between 0 and 100")
+	}
+	return nil
+}
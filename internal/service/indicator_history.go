@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+func (s *IndicatorService) GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.IndicatorWithRelations, error) {
+	indicator, err := s.repo.GetAsOf(ctx, id, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.audit.RecordRead(ctx, model.EntityIndicator, id, indicator.TLP); err != nil {
+		return nil, err
+	}
+	return indicator, nil
+}
+
+func (s *IndicatorService) History(ctx context.Context, id string, params model.IndicatorHistoryParams) (*model.IndicatorHistoryResult, error) {
+	params.Page, params.Limit = normalizePage(params.Page, params.Limit)
+
+	result, err := s.repo.History(ctx, id, params)
+	if err != nil {
+		return nil, err
+	}
+
+	tlp := marking.Clear
+	for _, version := range result.Data {
+		tlp = marking.Max(tlp, marking.Level(version.TLP))
+	}
+	if err := s.audit.RecordRead(ctx, model.EntityIndicator, id, string(tlp)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
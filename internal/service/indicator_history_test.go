@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndicatorService_History_DefaultsPaginationAndAuditsSensitiveVersions(t *testing.T) {
+	mockRepo := new(MockIndicatorRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	audit, auditRepo := newMockAuditService()
+	svc := NewIndicatorService(mockRepo, c, audit)
+	ctx := context.Background()
+
+	mockRepo.On("History", ctx, "ind-1", model.IndicatorHistoryParams{Page: 1, Limit: 20}).Return(&model.IndicatorHistoryResult{
+		Data: []model.IndicatorVersion{
+			{Indicator: model.Indicator{ID: "ind-1", TLP: "green"}, Version: 2},
+			{Indicator: model.Indicator{ID: "ind-1", TLP: "amber"}, Version: 1},
+		},
+		Total: 2, Page: 1, Limit: 20, TotalPages: 1,
+	}, nil)
+
+	result, err := svc.History(ctx, "ind-1", model.IndicatorHistoryParams{})
+
+	require.NoError(t, err)
+	assert.Len(t, result.Data, 2)
+	auditRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditActionRead && e.EntityID == "ind-1"
+	}))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_GetAsOf_NotFound(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+	asOf := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetAsOf", ctx, "ind-1", asOf).Return(nil, repository.ErrNotFound)
+
+	result, err := svc.GetAsOf(ctx, "ind-1", asOf)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestIndicatorService_GetAsOf_ReturnsPastVersion(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+	asOf := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetAsOf", ctx, "ind-1", asOf).Return(&model.IndicatorWithRelations{
+		Indicator:    model.Indicator{ID: "ind-1", Confidence: 90, Severity: "critical", TLP: "clear"},
+		ThreatActors: []model.ThreatActorSummary{{ID: "actor-1", Name: "APT28"}},
+	}, nil)
+
+	result, err := svc.GetAsOf(ctx, "ind-1", asOf)
+
+	require.NoError(t, err)
+	assert.Equal(t, 90, result.Confidence)
+	assert.Len(t, result.ThreatActors, 1)
+}
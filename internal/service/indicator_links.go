@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+const maxLinkNotes = 2000
+
+func (s *IndicatorService) LinkActor(ctx context.Context, indicatorID, actorID string, input model.IndicatorActorInput) (*model.IndicatorActorLink, bool, error) {
+	link := &model.IndicatorActorLink{IndicatorID: indicatorID, ActorID: actorID, AttributionConfidence: 50}
+	if input.Confidence != nil {
+		if err := validateConfidence(*input.Confidence); err != nil {
+			return nil, false, err
+		}
+		link.AttributionConfidence = *input.Confidence
+	}
+
+	created, err := s.repo.LinkActor(ctx, link)
+	if err != nil {
+		return nil, false, err
+	}
+
+	invalidateIndicatorLinks(s.cache)
+	s.cache.DeleteKey(ctx, "actor", map[string]string{"id": actorID})
+	s.cache.DeletePrefix("dashboard_summary")
+	if err := s.audit.RecordWrite(ctx, model.AuditActionLink, model.EntityIndicator, indicatorID, nil, link); err != nil {
+		return nil, false, err
+	}
+	return link, created, nil
+}
+
+func (s *IndicatorService) UnlinkActor(ctx context.Context, indicatorID, actorID string) error {
+	if err := s.repo.UnlinkActor(ctx, indicatorID, actorID); err != nil {
+		return err
+	}
+
+	invalidateIndicatorLinks(s.cache)
+	s.cache.DeleteKey(ctx, "actor", map[string]string{"id": actorID})
+	s.cache.DeletePrefix("dashboard_summary")
+	if err := s.audit.RecordWrite(ctx, model.AuditActionUnlink, model.EntityIndicator, indicatorID,
+		map[string]string{"indicator_id": indicatorID, "actor_id": actorID}, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *IndicatorService) LinkCampaign(ctx context.Context, indicatorID, campaignID string, input model.CampaignIndicatorInput) (*model.IndicatorCampaignLink, bool, error) {
+	link, err := newCampaignLink(indicatorID, campaignID, input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	created, err := s.repo.LinkCampaign(ctx, link)
+	if err != nil {
+		return nil, false, err
+	}
+
+	invalidateIndicatorLinks(s.cache)
+	if err := s.audit.RecordWrite(ctx, model.AuditActionLink, model.EntityIndicator, indicatorID, nil, link); err != nil {
+		return nil, false, err
+	}
+	return link, created, nil
+}
+
+func (s *IndicatorService) UnlinkCampaign(ctx context.Context, indicatorID, campaignID string) error {
+	if err := s.repo.UnlinkCampaign(ctx, indicatorID, campaignID); err != nil {
+		return err
+	}
+
+	invalidateIndicatorLinks(s.cache)
+	if err := s.audit.RecordWrite(ctx, model.AuditActionUnlink, model.EntityIndicator, indicatorID,
+		map[string]string{"indicator_id": indicatorID, "campaign_id": campaignID}, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *IndicatorService) LinkSample(ctx context.Context, indicatorID, siblingID string) (*model.IndicatorSample, error) {
+	if indicatorID == siblingID {
+		return nil, newValidationError("sibling_id", "must be a different indicator")
+	}
+
+	sample, err := s.repo.LinkSample(ctx, indicatorID, siblingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merging samples changes the detail of every hash in them, not only these two.
+	s.cache.DeletePrefix("indicator")
+	if err := s.audit.RecordWrite(ctx, model.AuditActionLink, model.EntityIndicator, indicatorID, nil, sample); err != nil {
+		return nil, err
+	}
+	return sample, nil
+}
+
+func (s *IndicatorService) UnlinkSample(ctx context.Context, indicatorID string) error {
+	if err := s.repo.UnlinkSample(ctx, indicatorID); err != nil {
+		return err
+	}
+
+	s.cache.DeletePrefix("indicator")
+	if err := s.audit.RecordWrite(ctx, model.AuditActionUnlink, model.EntityIndicator, indicatorID,
+		map[string]string{"indicator_id": indicatorID}, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func newCampaignLink(indicatorID, campaignID string, input model.CampaignIndicatorInput) (*model.IndicatorCampaignLink, error) {
+	link := &model.IndicatorCampaignLink{
+		IndicatorID: indicatorID,
+		CampaignID:  campaignID,
+		Notes:       strings.TrimSpace(input.Notes),
+	}
+	if len(link.Notes) > maxLinkNotes {
+		return nil, newValidationError("notes", "must be at most 2000 characters")
+	}
+	return link, nil
+}
+
+// A link changes the related indicators of every other indicator in the same
+// campaign, not only the detail of the one being linked.
+func invalidateIndicatorLinks(c *cache.Cache) {
+	c.DeletePrefix("indicator")
+	c.DeletePrefix("search")
+	c.DeletePrefix("campaign_timeline")
+}
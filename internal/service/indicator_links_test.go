@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndicatorService_LinkActor_InvalidatesRelations(t *testing.T) {
+	svc, mockRepo, c := setupIndicatorService(t)
+	ctx := context.Background()
+
+	indicatorKey := cache.GenerateKey(ctx, "indicator", map[string]string{"id": "ind-1"})
+	timelineKey := cache.GenerateKey(ctx, "campaign_timeline", map[string]interface{}{"id": "camp-1"})
+	actorKey := cache.GenerateKey(ctx, "actor", map[string]string{"id": "actor-1"})
+	dashboardKey := cache.GenerateKey(ctx, "dashboard_summary", map[string]string{"range": "7d"})
+	c.Set(indicatorKey, &model.IndicatorWithRelations{}, time.Minute)
+	c.Set(timelineKey, &model.CampaignWithTimeline{}, time.Minute)
+	c.Set(actorKey, &model.ThreatActorDetail{}, time.Minute)
+	c.Set(dashboardKey, &model.DashboardSummary{}, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	confidence := 90
+	mockRepo.On("LinkActor", ctx, &model.IndicatorActorLink{
+		IndicatorID: "ind-1", ActorID: "actor-1", AttributionConfidence: 90,
+	}).Return(false, nil)
+
+	link, created, err := svc.LinkActor(ctx, "ind-1", "actor-1", model.IndicatorActorInput{Confidence: &confidence})
+
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, 90, link.AttributionConfidence)
+	for _, key := range []string{indicatorKey, timelineKey, actorKey, dashboardKey} {
+		_, found := c.Get(key)
+		assert.False(t, found, key)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_LinkActor_DefaultsConfidence(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+
+	mockRepo.On("LinkActor", ctx, &model.IndicatorActorLink{
+		IndicatorID: "ind-1", ActorID: "actor-1", AttributionConfidence: 50,
+	}).Return(true, nil)
+
+	_, created, err := svc.LinkActor(ctx, "ind-1", "actor-1", model.IndicatorActorInput{})
+
+	require.NoError(t, err)
+	assert.True(t, created)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_LinkActor_InvalidConfidence(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+
+	confidence := 101
+	_, _, err := svc.LinkActor(context.Background(), "ind-1", "actor-1", model.IndicatorActorInput{Confidence: &confidence})
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "confidence", validationErr.Field)
+	mockRepo.AssertNotCalled(t, "LinkActor")
+}
+
+func TestIndicatorService_LinkCampaign_InvalidatesOtherIndicatorsInCampaign(t *testing.T) {
+	svc, mockRepo, c := setupIndicatorService(t)
+	ctx := context.Background()
+
+	siblingKey := cache.GenerateKey(ctx, "indicator", map[string]string{"id": "ind-2"})
+	c.Set(siblingKey, &model.IndicatorWithRelations{}, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	mockRepo.On("LinkCampaign", ctx, &model.IndicatorCampaignLink{IndicatorID: "ind-1", CampaignID: "camp-1"}).Return(true, nil)
+
+	_, _, err := svc.LinkCampaign(ctx, "ind-1", "camp-1", model.CampaignIndicatorInput{})
+
+	require.NoError(t, err)
+	_, found := c.Get(siblingKey)
+	assert.False(t, found)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_LinkCampaign_TrimsNotes(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+
+	mockRepo.On("LinkCampaign", ctx, &model.IndicatorCampaignLink{
+		IndicatorID: "ind-1", CampaignID: "camp-1", Notes: "staging server",
+	}).Return(true, nil)
+
+	link, _, err := svc.LinkCampaign(ctx, "ind-1", "camp-1", model.CampaignIndicatorInput{Notes: "  staging server\n"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "staging server", link.Notes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_UnlinkCampaign_NotFound(t *testing.T) {
+	svc, mockRepo, c := setupIndicatorService(t)
+	ctx := context.Background()
+
+	key := cache.GenerateKey(ctx, "indicator", map[string]string{"id": "ind-1"})
+	c.Set(key, &model.IndicatorWithRelations{}, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	mockRepo.On("UnlinkCampaign", ctx, "ind-1", "camp-1").Return(repository.ErrNotFound)
+
+	err := svc.UnlinkCampaign(ctx, "ind-1", "camp-1")
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, found := c.Get(key)
+	assert.True(t, found)
+}
+
+func TestIndicatorService_LinkSample_InvalidatesDetails(t *testing.T) {
+	svc, mockRepo, c := setupIndicatorService(t)
+	ctx := context.Background()
+
+	key := cache.GenerateKey(ctx, "indicator", map[string]string{"id": "ind-3"})
+	c.Set(key, &model.IndicatorWithRelations{}, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	sample := &model.IndicatorSample{ID: "sample-1", Hashes: []model.SiblingHash{
+		{ID: "ind-1", HashAlgo: model.HashAlgoMD5}, {ID: "ind-2", HashAlgo: model.HashAlgoSHA256},
+	}}
+	mockRepo.On("LinkSample", ctx, "ind-1", "ind-2").Return(sample, nil)
+
+	result, err := svc.LinkSample(ctx, "ind-1", "ind-2")
+
+	require.NoError(t, err)
+	assert.Equal(t, sample, result)
+	_, found := c.Get(key)
+	assert.False(t, found)
+}
+
+func TestIndicatorService_LinkSample_RejectsSelf(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+
+	_, err := svc.LinkSample(context.Background(), "ind-1", "ind-1")
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "sibling_id", validationErr.Field)
+	mockRepo.AssertNotCalled(t, "LinkSample")
+}
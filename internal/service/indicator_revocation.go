@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+func (s *IndicatorService) Revoke(ctx context.Context, id string, input model.IndicatorRevokeInput) (*model.Indicator, error) {
+	if !model.IsValidRevocationReason(input.Reason) {
+		return nil, newValidationError("reason", "must be one of: false_positive, expired, superseded")
+	}
+
+	return s.revoke(ctx, id, input.Reason, model.AuditActionRevoke)
+}
+
+func (s *IndicatorService) Restore(ctx context.Context, id string) (*model.Indicator, error) {
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	indicator := current.Indicator
+	indicator.RevokedAt = nil
+	indicator.RevocationReason = ""
+
+	invalidateIndicators(s.cache)
+	if err := s.audit.RecordWrite(ctx, model.AuditActionRestore, model.EntityIndicator, id, &current.Indicator, &indicator); err != nil {
+		return nil, err
+	}
+	return &indicator, nil
+}
+
+func (s *IndicatorService) revoke(ctx context.Context, id, reason, action string) (*model.Indicator, error) {
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	revokedAt, err := s.repo.Revoke(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	indicator := current.Indicator
+	indicator.RevokedAt = revokedAt
+	if reason != "" {
+		indicator.RevocationReason = reason
+	}
+
+	invalidateIndicators(s.cache)
+	if err := s.audit.RecordWrite(ctx, action, model.EntityIndicator, id, &current.Indicator, &indicator); err != nil {
+		return nil, err
+	}
+	return &indicator, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndicatorService_Revoke_RecordsReason(t *testing.T) {
+	mockRepo := new(MockIndicatorRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	audit, auditRepo := newMockAuditService()
+	svc := NewIndicatorService(mockRepo, c, audit)
+	ctx := context.Background()
+
+	revokedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	mockRepo.On("GetByID", ctx, "ind-1").Return(&model.IndicatorWithRelations{
+		Indicator: model.Indicator{ID: "ind-1", Value: "10.0.0.1"},
+	}, nil)
+	mockRepo.On("Revoke", ctx, "ind-1", model.RevocationFalsePositive).Return(&revokedAt, nil)
+
+	result, err := svc.Revoke(ctx, "ind-1", model.IndicatorRevokeInput{Reason: model.RevocationFalsePositive})
+
+	require.NoError(t, err)
+	assert.Equal(t, &revokedAt, result.RevokedAt)
+	assert.Equal(t, model.RevocationFalsePositive, result.RevocationReason)
+	auditRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(e *model.AuditEvent) bool {
+		return e.Action == model.AuditActionRevoke && e.EntityID == "ind-1"
+	}))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_Revoke_InvalidReason(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+
+	for _, reason := range []string{"", "duplicate"} {
+		result, err := svc.Revoke(context.Background(), "ind-1", model.IndicatorRevokeInput{Reason: reason})
+
+		assert.Nil(t, result)
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "reason", validationErr.Field)
+	}
+	mockRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIndicatorService_Restore_ClearsRevocation(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+
+	revokedAt := time.Now()
+	mockRepo.On("GetByID", ctx, "ind-1").Return(&model.IndicatorWithRelations{
+		Indicator: model.Indicator{ID: "ind-1", RevokedAt: &revokedAt, RevocationReason: model.RevocationExpired},
+	}, nil)
+	mockRepo.On("Restore", ctx, "ind-1").Return(nil)
+
+	result, err := svc.Restore(ctx, "ind-1")
+
+	require.NoError(t, err)
+	assert.Nil(t, result.RevokedAt)
+	assert.Empty(t, result.RevocationReason)
+	mockRepo.AssertExpectations(t)
+}
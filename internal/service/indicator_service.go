@@ -2,60 +2,439 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strings"
 
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/google/uuid"
 )
 
 type IndicatorService struct {
 	repo  repository.IndicatorRepositoryInterface
 	cache *cache.Cache
+	audit *AuditService
 }
 
-func NewIndicatorService(repo repository.IndicatorRepositoryInterface, c *cache.Cache) *IndicatorService {
+func NewIndicatorService(repo repository.IndicatorRepositoryInterface, c *cache.Cache, audit *AuditService) *IndicatorService {
 	return &IndicatorService{
 		repo:  repo,
 		cache: c,
+		audit: audit,
 	}
 }
 
 func (s *IndicatorService) GetByID(ctx context.Context, id string) (*model.IndicatorWithRelations, error) {
-	cacheKey := cache.GenerateKey("indicator", map[string]string{"id": id})
-	if cached, found := s.cache.Get(cacheKey); found {
-		return cached.(*model.IndicatorWithRelations), nil
+	cacheKey := cache.GenerateKey(ctx, "indicator", map[string]string{"id": id})
+	indicator, found := s.cache.Get(cacheKey)
+	if !found {
+		fetched, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(cacheKey, fetched, cache.TTLIndicatorDetail)
+		indicator = fetched
 	}
 
-	indicator, err := s.repo.GetByID(ctx, id)
+	result := indicator.(*model.IndicatorWithRelations)
+	if err := s.audit.RecordRead(ctx, model.EntityIndicator, id, result.TLP); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *IndicatorService) Search(ctx context.Context, params model.SearchParams) (*model.SearchResult, error) {
+	params.Page, params.Limit = normalizePage(params.Page, params.Limit)
+
+	cacheKey := cache.GenerateKey(ctx, "search", params)
+	cached, found := s.cache.Get(cacheKey)
+	if !found {
+		fetched, err := s.repo.Search(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(cacheKey, fetched, cache.TTLIndicatorSearch)
+		cached = fetched
+	}
+
+	result := cached.(*model.SearchResult)
+	tlps := make([]string, len(result.Data))
+	for i, indicator := range result.Data {
+		tlps[i] = indicator.TLP
+	}
+	if err := s.audit.RecordQuery(ctx, model.EntityIndicator, "", params, tlps); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *IndicatorService) Export(ctx context.Context, params model.SearchParams, fn func(*model.Indicator) error) error {
+	if err := s.audit.RecordExport(ctx, model.EntityIndicator, "", params); err != nil {
+		return err
+	}
+	return s.repo.StreamSearch(ctx, params, fn)
+}
+
+func (s *IndicatorService) ExportAttributed(ctx context.Context, params model.SearchParams, fn func(*model.AttributedIndicator) error) error {
+	if err := s.audit.RecordExport(ctx, model.EntityIndicator, "", params); err != nil {
+		return err
+	}
+	return s.repo.StreamAttributed(ctx, params, fn)
+}
+
+func (s *IndicatorService) Create(ctx context.Context, input model.IndicatorInput) (*model.Indicator, error) {
+	indicator := newIndicatorWithDefaults()
+	applyIndicatorInput(indicator, input)
+
+	if err := validateIndicatorFor(ctx, indicator); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, indicator); err != nil {
+		return nil, err
+	}
+
+	invalidateIndicators(s.cache)
+	if err := s.audit.RecordWrite(ctx, model.AuditActionCreate, model.EntityIndicator, indicator.ID, nil, indicator); err != nil {
+		return nil, err
+	}
+	return indicator, nil
+}
+
+func (s *IndicatorService) Update(ctx context.Context, id string, input model.IndicatorInput) (*model.Indicator, error) {
+	indicator := newIndicatorWithDefaults()
+	indicator.ID = id
+	applyIndicatorInput(indicator, input)
+
+	if err := validateIndicatorFor(ctx, indicator); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	s.cache.Set(cacheKey, indicator, cache.TTLIndicatorDetail)
+	if err := s.repo.Update(ctx, indicator); err != nil {
+		return nil, err
+	}
+
+	invalidateIndicators(s.cache)
+	if err := s.audit.RecordWrite(ctx, model.AuditActionUpdate, model.EntityIndicator, id, &current.Indicator, indicator); err != nil {
+		return nil, err
+	}
 	return indicator, nil
 }
 
-func (s *IndicatorService) Search(ctx context.Context, params model.SearchParams) (*model.SearchResult, error) {
-	if params.Page < 1 {
-		params.Page = 1
+func (s *IndicatorService) Patch(ctx context.Context, id string, input model.IndicatorInput) (*model.Indicator, error) {
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	indicator := current.Indicator
+	applyIndicatorInput(&indicator, input)
+
+	if err := validateIndicatorFor(ctx, &indicator); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, &indicator); err != nil {
+		return nil, err
 	}
-	if params.Limit < 1 {
-		params.Limit = 20
+
+	invalidateIndicators(s.cache)
+	if err := s.audit.RecordWrite(ctx, model.AuditActionUpdate, model.EntityIndicator, id, &current.Indicator, &indicator); err != nil {
+		return nil, err
 	}
-	if params.Limit > 100 {
-		params.Limit = 100
+	return &indicator, nil
+}
+
+func (s *IndicatorService) Upsert(ctx context.Context, input model.IndicatorInput, force bool) (*model.Indicator, bool, error) {
+	indicator := newIndicatorWithDefaults()
+	applyIndicatorInput(indicator, input)
+
+	if err := validateIndicatorFor(ctx, indicator); err != nil {
+		return nil, false, err
+	}
+
+	previous, created, err := s.repo.Upsert(ctx, indicator, force)
+	if err != nil {
+		return nil, false, err
 	}
 
-	cacheKey := cache.GenerateKey("search", params)
-	if cached, found := s.cache.Get(cacheKey); found {
-		return cached.(*model.SearchResult), nil
+	invalidateIndicators(s.cache)
+	action := model.AuditActionUpdate
+	if created {
+		action = model.AuditActionCreate
+	}
+	if err := s.audit.RecordWrite(ctx, action, model.EntityIndicator, indicator.ID, previous, indicator); err != nil {
+		return nil, false, err
+	}
+	return indicator, created, nil
+}
+
+func (s *IndicatorService) BulkIngest(ctx context.Context, items []model.BulkIndicatorItem, force bool) (*model.BulkResult, error) {
+	var rejected []model.BulkError
+	valid := make([]model.BulkIndicator, 0, len(items))
+
+	for _, item := range items {
+		if item.ParseError != "" {
+			rejected = append(rejected, model.BulkError{Line: item.Line, Reason: item.ParseError})
+			continue
+		}
+
+		indicator := newIndicatorWithDefaults()
+		applyIndicatorInput(indicator, item.Input.IndicatorInput)
+
+		err := validateIndicatorFor(ctx, indicator)
+		if err == nil {
+			err = validateIDs("campaign_ids", item.Input.CampaignIDs)
+		}
+		if err == nil {
+			err = validateIDs("threat_actor_ids", item.Input.ThreatActorIDs)
+		}
+		if err != nil {
+			rejected = append(rejected, model.BulkError{Line: item.Line, Value: indicator.Value, Reason: err.Error()})
+			continue
+		}
+
+		valid = append(valid, model.BulkIndicator{
+			Line:           item.Line,
+			Indicator:      *indicator,
+			CampaignIDs:    item.Input.CampaignIDs,
+			ThreatActorIDs: item.Input.ThreatActorIDs,
+		})
 	}
 
-	result, err := s.repo.Search(ctx, params)
+	result, err := s.repo.BulkUpsert(ctx, valid, force)
 	if err != nil {
 		return nil, err
 	}
 
-	s.cache.Set(cacheKey, result, cache.TTLIndicatorSearch)
+	result.Received = len(items)
+	result.Errors = append(result.Errors, rejected...)
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Line < result.Errors[j].Line
+	})
+	result.Rejected = len(result.Errors)
+
+	if result.Inserted > 0 || result.Updated > 0 {
+		invalidateIndicators(s.cache)
+	}
+
+	if err := s.audit.RecordImport(ctx, model.EntityIndicator, map[string]interface{}{
+		"format":   "bulk",
+		"received": result.Received,
+		"inserted": result.Inserted,
+		"updated":  result.Updated,
+		"rejected": result.Rejected,
+		"affected": result.Affected,
+	}); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
+
+// Delete revokes the indicator without a reason instead of removing the row,
+// so its campaign and actor links survive.
+func (s *IndicatorService) Delete(ctx context.Context, id string) error {
+	_, err := s.revoke(ctx, id, "", model.AuditActionDelete)
+	return err
+}
+
+// A write to one indicator can change its marking, value or status as seen
+// from other indicators' related lists, campaign timelines, actor details and
+// the dashboard, so every indicator write evicts all of them.
+func invalidateIndicators(c *cache.Cache) {
+	for _, prefix := range []string{"indicator", "search", "campaign_timeline", "dashboard_summary", "actor"} {
+		c.DeletePrefix(prefix)
+	}
+}
+
+func newIndicatorWithDefaults() *model.Indicator {
+	return &model.Indicator{
+		Severity:   "medium",
+		Confidence: 50,
+		IsActive:   true,
+		TLP:        string(marking.Clear),
+		PAP:        string(marking.Clear),
+	}
+}
+
+func applyIndicatorInput(indicator *model.Indicator, input model.IndicatorInput) {
+	if input.Type != nil || input.Value != nil {
+		indicator.HashAlgo = ""
+	}
+	if input.Type != nil {
+		indicator.Type = *input.Type
+	}
+	if input.Value != nil {
+		indicator.Value = strings.TrimSpace(*input.Value)
+	}
+	if input.HashAlgo != nil {
+		indicator.HashAlgo = strings.ToLower(strings.TrimSpace(*input.HashAlgo))
+	}
+	if input.Description != nil {
+		indicator.Description = *input.Description
+	}
+	if input.Severity != nil {
+		indicator.Severity = *input.Severity
+	}
+	if input.Confidence != nil {
+		indicator.Confidence = *input.Confidence
+	}
+	if input.FirstSeen != nil {
+		indicator.FirstSeen = input.FirstSeen
+	}
+	if input.LastSeen != nil {
+		indicator.LastSeen = input.LastSeen
+	}
+	if input.IsActive != nil {
+		indicator.IsActive = *input.IsActive
+	}
+	if input.TLP != nil {
+		indicator.TLP = normalizeMarking(*input.TLP)
+	}
+	if input.PAP != nil {
+		indicator.PAP = normalizeMarking(*input.PAP)
+	}
+	if input.Tags != nil {
+		indicator.Tags = normalizeTags(input.Tags)
+	}
+	if input.Metadata != nil {
+		indicator.Metadata = input.Metadata
+	}
+	if input.Source != nil {
+		indicator.Source = *input.Source
+	}
+}
+
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func normalizeMarking(value string) string {
+	if level, ok := marking.Parse(value); ok {
+		return string(level)
+	}
+	return value
+}
+
+// resolveHashAlgo detects the algorithm of hashes submitted without one and
+// checks the value against the algorithm otherwise.
+func resolveHashAlgo(indicator *model.Indicator) error {
+	if indicator.Type != model.IndicatorTypeHash {
+		if indicator.HashAlgo != "" {
+			return newValidationError("hash_algo", "only applies to hash indicators")
+		}
+		return nil
+	}
+	if indicator.HashAlgo == "" {
+		indicator.HashAlgo = model.DetectHashAlgo(indicator.Value)
+		if indicator.HashAlgo == "" {
+			return newValidationError("value", "must be an MD5, SHA-1, SHA-256, SHA-512, SSDEEP or TLSH hash")
+		}
+		return nil
+	}
+	if !model.IsValidHashAlgo(indicator.HashAlgo) {
+		return newValidationError("hash_algo", "must be one of: md5, sha1, sha256, sha512, ssdeep, tlsh, imphash")
+	}
+	if !model.IsValidHash(indicator.HashAlgo, indicator.Value) {
+		return newValidationError("value", "is not a valid "+indicator.HashAlgo+" hash")
+	}
+	return nil
+}
+
+func validateMarkings(tlp, pap string) error {
+	if !marking.Level(tlp).IsValid() {
+		return newValidationError("tlp", "must be one of: clear, green, amber, red")
+	}
+	if !marking.Level(pap).IsValid() {
+		return newValidationError("pap", "must be one of: clear, green, amber, red")
+	}
+	return nil
+}
+
+func checkClearance(ctx context.Context, tlp string) error {
+	if !marking.ClearanceFromContext(ctx).Allows(marking.Level(tlp)) {
+		return newValidationError("tlp", "must not exceed your clearance")
+	}
+	return nil
+}
+
+func validateIndicatorFor(ctx context.Context, indicator *model.Indicator) error {
+	if err := validateIndicator(indicator); err != nil {
+		return err
+	}
+	return checkClearance(ctx, indicator.TLP)
+}
+
+func validateIDs(field string, ids []string) error {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return newValidationError(field, "contains an invalid UUID: "+id)
+		}
+	}
+	return nil
+}
+
+func validateIndicator(indicator *model.Indicator) error {
+	spec, ok := model.LookupIndicatorType(indicator.Type)
+	if !ok {
+		return newValidationError("type", "must be one of: "+model.IndicatorTypeNames())
+	}
+	indicator.Value = indicator.Type.Normalize(indicator.Value)
+	if indicator.Value == "" {
+		return newValidationError("value", "is required")
+	}
+	if len(indicator.Value) > 2048 {
+		return newValidationError("value", "must be at most 2048 characters")
+	}
+	if spec.Valid != nil && !spec.Valid(indicator.Value) {
+		return newValidationError("value", spec.Invalid)
+	}
+	if err := resolveHashAlgo(indicator); err != nil {
+		return err
+	}
+	if !model.IsValidSeverity(indicator.Severity) {
+		return newValidationError("severity", "must be one of: low, medium, high, critical")
+	}
+	if indicator.Confidence < 0 || indicator.Confidence > 100 {
+		return newValidationError("confidence", "must be between 0 and 100")
+	}
+	if indicator.FirstSeen != nil && indicator.LastSeen != nil && indicator.LastSeen.Before(*indicator.FirstSeen) {
+		return newValidationError("last_seen", "must not be before first_seen")
+	}
+	for _, tag := range indicator.Tags {
+		if tag == "" {
+			return newValidationError("tags", "must not contain empty values")
+		}
+		if len(tag) > 100 {
+			return newValidationError("tags", "values must be at most 100 characters")
+		}
+	}
+	if len(indicator.Metadata) > 0 {
+		var metadata map[string]interface{}
+		if err := json.Unmarshal(indicator.Metadata, &metadata); err != nil {
+			return newValidationError("metadata", "must be a JSON object")
+		}
+	}
+	if len(indicator.Source) > 255 {
+		return newValidationError("source", "must be at most 255 characters")
+	}
+	return validateMarkings(indicator.TLP, indicator.PAP)
+}
@@ -2,14 +2,17 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,7 +20,8 @@ func setupIndicatorService(t *testing.T) (*IndicatorService, *MockIndicatorRepos
 	mockRepo := new(MockIndicatorRepository)
 	c, err := cache.New(cache.Config{MaxSizeMB: 10})
 	require.NoError(t, err)
-	svc := NewIndicatorService(mockRepo, c)
+	audit, _ := newMockAuditService()
+	svc := NewIndicatorService(mockRepo, c, audit)
 	return svc, mockRepo, c
 }
 
@@ -181,3 +185,439 @@ func TestIndicatorService_Search_RepoError(t *testing.T) {
 	assert.Equal(t, "database error", err.Error())
 	mockRepo.AssertExpectations(t)
 }
+
+func TestIndicatorService_Create_Success(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+
+	indicatorType := model.IndicatorTypeDomain
+	value := " evil.example.com "
+	input := model.IndicatorInput{
+		Type:  &indicatorType,
+		Value: &value,
+		Tags:  []string{"phishing", " phishing", "c2"},
+	}
+
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*model.Indicator")).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.Indicator).ID = "new-uuid"
+	}).Return(nil)
+
+	result, err := svc.Create(ctx, input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-uuid", result.ID)
+	assert.Equal(t, "evil.example.com", result.Value)
+	assert.Equal(t, "medium", result.Severity)
+	assert.Equal(t, 50, result.Confidence)
+	assert.True(t, result.IsActive)
+	assert.Equal(t, []string{"phishing", "c2"}, result.Tags)
+	assert.Equal(t, "clear", result.TLP)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_Create_ValidationErrors(t *testing.T) {
+	ipType := model.IndicatorTypeIP
+	badType := model.IndicatorType("btc_wallet")
+	value := "10.0.0.1"
+	empty := ""
+	badSeverity := "urgent"
+	badTLP := "purple"
+	tooHigh := 101
+	earlier := time.Now().Add(-time.Hour)
+	later := time.Now()
+	hashType := model.IndicatorTypeHash
+	md5Value := "d41d8cd98f00b204e9800998ecf8427e"
+	md5Algo := model.HashAlgoMD5
+	sha256Algo := model.HashAlgoSHA256
+
+	tests := []struct {
+		name  string
+		input model.IndicatorInput
+		field string
+	}{
+		{"invalid type", model.IndicatorInput{Type: &badType, Value: &value}, "type"},
+		{"missing value", model.IndicatorInput{Type: &ipType, Value: &empty}, "value"},
+		{"ip value not an address", model.IndicatorInput{Type: &ipType, Value: &badSeverity}, "value"},
+		{"invalid severity", model.IndicatorInput{Type: &ipType, Value: &value, Severity: &badSeverity}, "severity"},
+		{"confidence out of range", model.IndicatorInput{Type: &ipType, Value: &value, Confidence: &tooHigh}, "confidence"},
+		{"empty tag", model.IndicatorInput{Type: &ipType, Value: &value, Tags: []string{"  "}}, "tags"},
+		{"last seen before first seen", model.IndicatorInput{Type: &ipType, Value: &value, FirstSeen: &later, LastSeen: &earlier}, "last_seen"},
+		{"metadata not an object", model.IndicatorInput{Type: &ipType, Value: &value, Metadata: []byte(`[1,2]`)}, "metadata"},
+		{"invalid tlp", model.IndicatorInput{Type: &ipType, Value: &value, TLP: &badTLP}, "tlp"},
+		{"invalid pap", model.IndicatorInput{Type: &ipType, Value: &value, PAP: &badTLP}, "pap"},
+		{"hash algo on ip", model.IndicatorInput{Type: &ipType, Value: &value, HashAlgo: &md5Algo}, "hash_algo"},
+		{"undetectable hash", model.IndicatorInput{Type: &hashType, Value: &badSeverity}, "value"},
+		{"unknown hash algo", model.IndicatorInput{Type: &hashType, Value: &md5Value, HashAlgo: &badTLP}, "hash_algo"},
+		{"hash not matching algo", model.IndicatorInput{Type: &hashType, Value: &md5Value, HashAlgo: &sha256Algo}, "value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mockRepo, _ := setupIndicatorService(t)
+
+			result, err := svc.Create(context.Background(), tt.input)
+
+			assert.Nil(t, result)
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestResolveHashAlgo(t *testing.T) {
+	tests := []struct {
+		value    string
+		hashAlgo string
+		expected string
+	}{
+		{"D41D8CD98F00B204E9800998ECF8427E", "", model.HashAlgoMD5},
+		{"da39a3ee5e6b4b0d3255bfef95601890afd80709", "", model.HashAlgoSHA1},
+		{"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "", model.HashAlgoSHA256},
+		{"3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C", "", model.HashAlgoSSDEEP},
+		{"T14a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a4a", "", model.HashAlgoTLSH},
+		{"f34d5f2d4577ed6d9ceec516c1f5a744", model.HashAlgoImphash, model.HashAlgoImphash},
+	}
+
+	for _, tt := range tests {
+		indicator := &model.Indicator{Type: model.IndicatorTypeHash, Value: tt.value, HashAlgo: tt.hashAlgo}
+		require.NoError(t, resolveHashAlgo(indicator), tt.value)
+		assert.Equal(t, tt.expected, indicator.HashAlgo, tt.value)
+	}
+}
+
+func TestIndicatorService_Create_NormalizesByType(t *testing.T) {
+	tests := []struct {
+		indicatorType model.IndicatorType
+		value         string
+		expected      string
+	}{
+		{model.IndicatorTypeIP, " 10.0.0.1 ", "10.0.0.1"},
+		{model.IndicatorTypeEmail, "Phish@Example.COM", "phish@example.com"},
+		{model.IndicatorTypeASN, "as013335", "AS13335"},
+		{model.IndicatorTypeASN, "13335", "AS13335"},
+		{model.IndicatorTypeCVE, "cve-2021-44228", "CVE-2021-44228"},
+		{model.IndicatorTypeJA3, "E7D705A3286E19EA42F587B344EE6865", "e7d705a3286e19ea42f587b344ee6865"},
+		{model.IndicatorTypeJA4, "T13D1516H2_8DAAF6152771_B186095E22B6", "t13d1516h2_8daaf6152771_b186095e22b6"},
+		{model.IndicatorTypeFilePath, `C:\Users\Public\evil.exe`, `C:\Users\Public\evil.exe`},
+		{model.IndicatorTypeFilePath, "/tmp/.X11-unix/kworker", "/tmp/.X11-unix/kworker"},
+		{model.IndicatorTypeMutex, `Global\DCPERSFWBP`, `Global\DCPERSFWBP`},
+		{model.IndicatorTypeRegistryKey, `hklm\Software\Microsoft\Windows\CurrentVersion\Run\`, `HKEY_LOCAL_MACHINE\Software\Microsoft\Windows\CurrentVersion\Run`},
+		{model.IndicatorTypeUserAgent, "Mozilla/5.0 (Windows NT 6.1; WOW64) Evil", "Mozilla/5.0 (Windows NT 6.1; WOW64) Evil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.indicatorType)+" "+tt.value, func(t *testing.T) {
+			svc, mockRepo, _ := setupIndicatorService(t)
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(ind *model.Indicator) bool {
+				return ind.Value == tt.expected
+			})).Return(nil)
+
+			_, err := svc.Create(context.Background(), model.IndicatorInput{Type: &tt.indicatorType, Value: &tt.value})
+
+			require.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIndicatorService_Create_InvalidValueForType(t *testing.T) {
+	tests := []struct {
+		indicatorType model.IndicatorType
+		value         string
+	}{
+		{model.IndicatorTypeIP, "10.0.0"},
+		{model.IndicatorTypeIP, "10.0.0.1/33"},
+		{model.IndicatorTypeIP, "fe80::1%eth0"},
+		{model.IndicatorTypeIP, "example.com"},
+		{model.IndicatorTypeEmail, "Phisher <phish@example.com>"},
+		{model.IndicatorTypeEmail, "phish@localhost"},
+		{model.IndicatorTypeASN, "AS0"},
+		{model.IndicatorTypeASN, "AS4294967296"},
+		{model.IndicatorTypeCVE, "CVE-21-44228"},
+		{model.IndicatorTypeJA3, "e7d705a3286e19ea42f587b344ee68"},
+		{model.IndicatorTypeJA4, "t13d1516h2_8daaf6152771"},
+		{model.IndicatorTypeFilePath, "evil.exe"},
+		{model.IndicatorTypeMutex, "bad\nname"},
+		{model.IndicatorTypeRegistryKey, `HKEY_NOPE\Software`},
+		{model.IndicatorTypeUserAgent, "Mozilla\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.indicatorType)+" "+tt.value, func(t *testing.T) {
+			svc, mockRepo, _ := setupIndicatorService(t)
+
+			_, err := svc.Create(context.Background(), model.IndicatorInput{Type: &tt.indicatorType, Value: &tt.value})
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "value", validationErr.Field)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestIndicatorService_Create_AboveClearance(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := marking.WithClearance(context.Background(), marking.Green)
+
+	ipType := model.IndicatorTypeIP
+	value := "10.0.0.1"
+	amber := "TLP:AMBER"
+	result, err := svc.Create(ctx, model.IndicatorInput{Type: &ipType, Value: &value, TLP: &amber})
+
+	assert.Nil(t, result)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "tlp", validationErr.Field)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestIndicatorService_Update_NotFound(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+
+	ipType := model.IndicatorTypeIP
+	value := "10.0.0.1"
+
+	mockRepo.On("GetByID", ctx, "missing-uuid").Return(nil, repository.ErrNotFound)
+
+	result, err := svc.Update(ctx, "missing-uuid", model.IndicatorInput{Type: &ipType, Value: &value})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_Patch_TLPChangeEvictsCampaignTimeline(t *testing.T) {
+	svc, mockRepo, c := setupIndicatorService(t)
+	audit, _ := newMockAuditService()
+	campaignRepo := new(MockCampaignRepository)
+	campaigns := NewCampaignService(campaignRepo, c, audit)
+	ctx := context.Background()
+
+	params := model.TimelineParams{GroupBy: "day"}
+	campaignRepo.On("GetIndicatorsTimeline", ctx, "camp-1", params).Return(&model.CampaignWithTimeline{
+		Campaign: model.CampaignDetail{ID: "camp-1", TLP: "green"},
+	}, nil).Twice()
+
+	_, err := campaigns.GetIndicatorsTimeline(ctx, "camp-1", params)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	tlp := "red"
+	mockRepo.On("GetByID", ctx, "ind-1").Return(&model.IndicatorWithRelations{
+		Indicator: model.Indicator{ID: "ind-1", Type: model.IndicatorTypeIP, Value: "10.0.0.1", Severity: "low", TLP: "green", PAP: "clear"},
+	}, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*model.Indicator")).Return(nil)
+	_, err = svc.Patch(ctx, "ind-1", model.IndicatorInput{TLP: &tlp})
+	require.NoError(t, err)
+
+	_, err = campaigns.GetIndicatorsTimeline(ctx, "camp-1", params)
+	require.NoError(t, err)
+	campaignRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_Patch_AppliesOnlyProvidedFields(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+
+	existing := &model.IndicatorWithRelations{
+		Indicator: model.Indicator{
+			ID:         "patch-uuid",
+			Type:       model.IndicatorTypeIP,
+			Value:      "10.0.0.1",
+			Severity:   "low",
+			Confidence: 40,
+			TLP:        "amber",
+			PAP:        "clear",
+			Tags:       []string{"scanner"},
+		},
+	}
+	confidence := 90
+
+	mockRepo.On("GetByID", ctx, "patch-uuid").Return(existing, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*model.Indicator")).Return(nil)
+
+	result, err := svc.Patch(ctx, "patch-uuid", model.IndicatorInput{Confidence: &confidence})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 90, result.Confidence)
+	assert.Equal(t, "low", result.Severity)
+	assert.Equal(t, "10.0.0.1", result.Value)
+	assert.Equal(t, []string{"scanner"}, result.Tags)
+	assert.Equal(t, "amber", result.TLP)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_Delete_EvictsCache(t *testing.T) {
+	svc, mockRepo, c := setupIndicatorService(t)
+	ctx := context.Background()
+
+	detailKey := cache.GenerateKey(ctx, "indicator", map[string]string{"id": "delete-uuid"})
+	searchKey := cache.GenerateKey(ctx, "search", model.SearchParams{Page: 1, Limit: 20})
+	c.Set(detailKey, &model.IndicatorWithRelations{}, time.Minute)
+	c.Set(searchKey, &model.SearchResult{}, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	mockRepo.On("GetByID", ctx, "delete-uuid").Return(&model.IndicatorWithRelations{
+		Indicator: model.Indicator{ID: "delete-uuid"},
+	}, nil)
+	revokedAt := time.Now()
+	mockRepo.On("Revoke", ctx, "delete-uuid", "").Return(&revokedAt, nil)
+
+	err := svc.Delete(ctx, "delete-uuid")
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	_, detailFound := c.Get(detailKey)
+	_, searchFound := c.Get(searchKey)
+	assert.False(t, detailFound)
+	assert.False(t, searchFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_Upsert_ReturnsMergedIndicator(t *testing.T) {
+	mockRepo := new(MockIndicatorRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	auditRepo := new(MockAuditRepository)
+	svc := NewIndicatorService(mockRepo, c, NewAuditService(auditRepo))
+	ctx := context.Background()
+
+	hashType := model.IndicatorTypeHash
+	value := "D41D8CD98F00B204E9800998ECF8427E"
+	confidence := 30
+
+	mockRepo.On("Upsert", ctx, mock.AnythingOfType("*model.Indicator"), false).Run(func(args mock.Arguments) {
+		indicator := args.Get(1).(*model.Indicator)
+		indicator.ID = "existing-uuid"
+		indicator.Confidence = 85
+	}).Return(&model.Indicator{ID: "existing-uuid", Type: hashType, Value: "d41d8cd98f00b204e9800998ecf8427e", Confidence: 60}, false, nil)
+
+	var recorded *model.AuditEvent
+	auditRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = args.Get(1).(*model.AuditEvent)
+	}).Return(nil)
+
+	result, created, err := svc.Upsert(ctx, model.IndicatorInput{Type: &hashType, Value: &value, Confidence: &confidence}, false)
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "existing-uuid", result.ID)
+	assert.Equal(t, 85, result.Confidence)
+	mockRepo.AssertExpectations(t)
+
+	require.NotNil(t, recorded)
+	assert.Equal(t, model.AuditActionUpdate, recorded.Action)
+	var changes map[string]model.AuditChange
+	require.NoError(t, json.Unmarshal(recorded.Changes, &changes))
+	assert.Equal(t, model.AuditChange{Before: float64(60), After: float64(85)}, changes["confidence"])
+}
+
+func TestIndicatorService_Upsert_ValidationError(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+
+	hashType := model.IndicatorTypeHash
+	value := "abc"
+	badConfidence := -1
+
+	result, created, err := svc.Upsert(context.Background(), model.IndicatorInput{Type: &hashType, Value: &value, Confidence: &badConfidence}, false)
+
+	assert.Nil(t, result)
+	assert.False(t, created)
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIndicatorService_BulkIngest_RejectsInvalidLines(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+
+	ipType := model.IndicatorTypeIP
+	goodValue := "10.0.0.1"
+	otherValue := "10.0.0.2"
+	badConfidence := 101
+
+	items := []model.BulkIndicatorItem{
+		{Line: 1, Input: model.BulkIndicatorInput{IndicatorInput: model.IndicatorInput{Type: &ipType, Value: &goodValue}}},
+		{Line: 2, ParseError: "invalid JSON: unexpected end of JSON input"},
+		{Line: 3, Input: model.BulkIndicatorInput{IndicatorInput: model.IndicatorInput{Type: &ipType, Value: &otherValue, Confidence: &badConfidence}}},
+		{Line: 4, Input: model.BulkIndicatorInput{
+			IndicatorInput: model.IndicatorInput{Type: &ipType, Value: &otherValue},
+			CampaignIDs:    []string{"not-a-uuid"},
+		}},
+	}
+
+	mockRepo.On("BulkUpsert", ctx, mock.MatchedBy(func(indicators []model.BulkIndicator) bool {
+		return len(indicators) == 1 && indicators[0].Line == 1 && indicators[0].Indicator.Value == "10.0.0.1"
+	}), false).Return(&model.BulkResult{Inserted: 1, Errors: []model.BulkError{}}, nil)
+
+	result, err := svc.BulkIngest(ctx, items, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Received)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 3, result.Rejected)
+	require.Len(t, result.Errors, 3)
+	assert.Equal(t, 2, result.Errors[0].Line)
+	assert.Equal(t, 3, result.Errors[1].Line)
+	assert.Contains(t, result.Errors[1].Reason, "confidence")
+	assert.Equal(t, 4, result.Errors[2].Line)
+	assert.Contains(t, result.Errors[2].Reason, "campaign_ids")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestIndicatorService_BulkIngest_AuditsAffectedIDs(t *testing.T) {
+	mockRepo := new(MockIndicatorRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	auditRepo := new(MockAuditRepository)
+	svc := NewIndicatorService(mockRepo, c, NewAuditService(auditRepo))
+	ctx := context.Background()
+
+	ipType := model.IndicatorTypeIP
+	value := "10.0.0.1"
+	mockRepo.On("BulkUpsert", ctx, mock.Anything, false).Return(&model.BulkResult{
+		Updated:  1,
+		Errors:   []model.BulkError{},
+		Affected: model.AffectedIDs{Updated: []string{"existing-uuid"}},
+	}, nil)
+
+	var recorded *model.AuditEvent
+	auditRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = args.Get(1).(*model.AuditEvent)
+	}).Return(nil)
+
+	_, err = svc.BulkIngest(ctx, []model.BulkIndicatorItem{
+		{Line: 1, Input: model.BulkIndicatorInput{IndicatorInput: model.IndicatorInput{Type: &ipType, Value: &value}}},
+	}, false)
+
+	require.NoError(t, err)
+	require.NotNil(t, recorded)
+	assert.JSONEq(t, `{"format":"bulk","received":1,"inserted":0,"updated":1,"rejected":0,"affected":{"updated":["existing-uuid"]}}`, string(recorded.Params))
+}
+
+func TestIndicatorService_Export_StreamsFromRepository(t *testing.T) {
+	svc, mockRepo, _ := setupIndicatorService(t)
+	ctx := context.Background()
+
+	params := model.SearchParams{Type: "domain"}
+	mockRepo.On("StreamSearch", ctx, params).Return([]model.Indicator{
+		{ID: "1", Type: model.IndicatorTypeDomain, Value: "a.example"},
+		{ID: "2", Type: model.IndicatorTypeDomain, Value: "b.example"},
+	}, nil)
+
+	var values []string
+	err := svc.Export(ctx, params, func(ind *model.Indicator) error {
+		values = append(values, ind.Value)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.example", "b.example"}, values)
+	mockRepo.AssertExpectations(t)
+}
@@ -2,19 +2,83 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/misp"
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/stix"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/taxii"
 )
 
 type IndicatorServiceInterface interface {
 	GetByID(ctx context.Context, id string) (*model.IndicatorWithRelations, error)
 	Search(ctx context.Context, params model.SearchParams) (*model.SearchResult, error)
+	Export(ctx context.Context, params model.SearchParams, fn func(*model.Indicator) error) error
+	ExportAttributed(ctx context.Context, params model.SearchParams, fn func(*model.AttributedIndicator) error) error
+	Create(ctx context.Context, input model.IndicatorInput) (*model.Indicator, error)
+	Update(ctx context.Context, id string, input model.IndicatorInput) (*model.Indicator, error)
+	Patch(ctx context.Context, id string, input model.IndicatorInput) (*model.Indicator, error)
+	Upsert(ctx context.Context, input model.IndicatorInput, force bool) (*model.Indicator, bool, error)
+	BulkIngest(ctx context.Context, items []model.BulkIndicatorItem, force bool) (*model.BulkResult, error)
+	Delete(ctx context.Context, id string) error
+	Revoke(ctx context.Context, id string, input model.IndicatorRevokeInput) (*model.Indicator, error)
+	Restore(ctx context.Context, id string) (*model.Indicator, error)
+	LinkActor(ctx context.Context, indicatorID, actorID string, input model.IndicatorActorInput) (*model.IndicatorActorLink, bool, error)
+	UnlinkActor(ctx context.Context, indicatorID, actorID string) error
+	LinkCampaign(ctx context.Context, indicatorID, campaignID string, input model.CampaignIndicatorInput) (*model.IndicatorCampaignLink, bool, error)
+	UnlinkCampaign(ctx context.Context, indicatorID, campaignID string) error
+	LinkSample(ctx context.Context, indicatorID, siblingID string) (*model.IndicatorSample, error)
+	UnlinkSample(ctx context.Context, indicatorID string) error
+	GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.IndicatorWithRelations, error)
+	History(ctx context.Context, id string, params model.IndicatorHistoryParams) (*model.IndicatorHistoryResult, error)
 }
 
 type CampaignServiceInterface interface {
+	List(ctx context.Context, params model.CampaignListParams) (*model.CampaignListResult, error)
+	GetByID(ctx context.Context, id string) (*model.Campaign, error)
+	Create(ctx context.Context, input model.CampaignInput) (*model.Campaign, error)
+	Update(ctx context.Context, id string, input model.CampaignInput) (*model.Campaign, error)
+	Patch(ctx context.Context, id string, input model.CampaignInput) (*model.Campaign, error)
+	Delete(ctx context.Context, id string) error
+	LinkIndicator(ctx context.Context, campaignID, indicatorID string, input model.CampaignIndicatorInput) (*model.IndicatorCampaignLink, bool, error)
+	UnlinkIndicator(ctx context.Context, campaignID, indicatorID string) error
 	GetIndicatorsTimeline(ctx context.Context, campaignID string, params model.TimelineParams) (*model.CampaignWithTimeline, error)
 }
 
+type ActorServiceInterface interface {
+	List(ctx context.Context, params model.ActorListParams) (*model.ActorListResult, error)
+	GetByID(ctx context.Context, id string) (*model.ThreatActorDetail, error)
+	GetIndicators(ctx context.Context, actorID string, params model.ActorIndicatorParams) (*model.ActorIndicatorResult, error)
+	SetAliases(ctx context.Context, actorID string, input model.ActorAliasesInput) (*model.ThreatActorDetail, error)
+}
+
 type DashboardServiceInterface interface {
 	GetSummary(ctx context.Context, timeRange string) (*model.DashboardSummary, error)
 }
+
+type StixServiceInterface interface {
+	ExportIndicator(ctx context.Context, indicatorID string) (*stix.Bundle, error)
+	ExportCampaign(ctx context.Context, campaignID string) (*stix.Bundle, error)
+	Import(ctx context.Context, payload []byte, force bool) (*model.ImportResult, error)
+}
+
+type TaxiiServiceInterface interface {
+	Collections(ctx context.Context) ([]taxii.Collection, error)
+	Collection(ctx context.Context, id string) (*taxii.Collection, error)
+	Objects(ctx context.Context, collectionID string, params model.TaxiiObjectParams) (*taxii.Envelope, error)
+}
+
+type MispServiceInterface interface {
+	Import(ctx context.Context, payload []byte, force bool) (*model.ImportResult, error)
+	ExportEvent(ctx context.Context, campaignID string) (*misp.EventWrapper, error)
+}
+
+type APIKeyServiceInterface interface {
+	Create(ctx context.Context, input model.APIKeyInput) (*model.IssuedAPIKey, error)
+	List(ctx context.Context) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id string) (*model.APIKey, error)
+}
+
+type AuditServiceInterface interface {
+	List(ctx context.Context, params model.AuditListParams) (*model.AuditListResult, error)
+}
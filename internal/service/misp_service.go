@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/misp"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+)
+
+type MispService struct {
+	bundleRepo   repository.BundleRepositoryInterface
+	campaignRepo repository.CampaignRepositoryInterface
+	cache        *cache.Cache
+	audit        *AuditService
+}
+
+func NewMispService(bundleRepo repository.BundleRepositoryInterface, campaignRepo repository.CampaignRepositoryInterface, c *cache.Cache, audit *AuditService) *MispService {
+	return &MispService{
+		bundleRepo:   bundleRepo,
+		campaignRepo: campaignRepo,
+		cache:        c,
+		audit:        audit,
+	}
+}
+
+func (s *MispService) Import(ctx context.Context, payload []byte, force bool) (*model.ImportResult, error) {
+	data, rejected, err := misp.ParseEvents(payload)
+	if err != nil {
+		return nil, newValidationError("event", err.Error())
+	}
+	data.Force = force
+
+	return importIntel(ctx, s.bundleRepo, s.cache, s.audit, "misp", data, rejected)
+}
+
+func (s *MispService) ExportEvent(ctx context.Context, campaignID string) (*misp.EventWrapper, error) {
+	timeline, err := s.campaignRepo.GetIndicatorsTimeline(ctx, campaignID, model.TimelineParams{GroupBy: "day"})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.RecordExport(ctx, model.EntityCampaign, campaignID, map[string]string{"format": "misp"}); err != nil {
+		return nil, err
+	}
+	return misp.BuildEvent(timeline), nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMispService(t *testing.T) (*MispService, *MockBundleRepository, *MockCampaignRepository) {
+	bundleRepo := new(MockBundleRepository)
+	campaignRepo := new(MockCampaignRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	audit, _ := newMockAuditService()
+	return NewMispService(bundleRepo, campaignRepo, c, audit), bundleRepo, campaignRepo
+}
+
+func TestMispService_Import(t *testing.T) {
+	svc, bundleRepo, _ := setupMispService(t)
+	ctx := context.Background()
+
+	payload := []byte(`{"Event": {
+		"uuid": "5e5d0b55-9d3c-4c5a-8d2f-4a1c2b3d4e5f",
+		"info": "Phishing wave",
+		"Attribute": [
+			{"uuid": "a1a1a1a1-0000-4000-8000-000000000001", "type": "domain", "value": "evil.example.com", "to_ids": true},
+			{"uuid": "a1a1a1a1-0000-4000-8000-000000000002", "type": "text", "value": "notes"}
+		]
+	}}`)
+
+	bundleRepo.On("Import", ctx, mock.MatchedBy(func(data *model.IntelImport) bool {
+		return data.RefKey == "misp_uuid" && len(data.Campaigns) == 1 && len(data.Indicators) == 1
+	})).Return(&model.ImportResult{
+		Indicators: model.ImportCounts{Created: 1},
+		Campaigns:  model.ImportCounts{Created: 1},
+		Errors:     []model.ImportError{},
+	}, nil)
+
+	result, err := svc.Import(ctx, payload, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Indicators.Created)
+	assert.Equal(t, 1, result.Rejected)
+	assert.Equal(t, "a1a1a1a1-0000-4000-8000-000000000002", result.Errors[0].Ref)
+	bundleRepo.AssertExpectations(t)
+}
+
+func TestMispService_ExportEvent(t *testing.T) {
+	svc, _, campaignRepo := setupMispService(t)
+	ctx := context.Background()
+
+	timeline := &model.CampaignWithTimeline{
+		Campaign: model.CampaignDetail{ID: "camp-1", Name: "Operation Test"},
+		Timeline: []model.TimelinePeriod{
+			{Period: "2024-01-15", Indicators: []model.TimelineIndicator{{ID: "ind-1", Type: "ip", Value: "10.0.0.1"}}},
+		},
+	}
+	campaignRepo.On("GetIndicatorsTimeline", ctx, "camp-1", model.TimelineParams{GroupBy: "day"}).Return(timeline, nil)
+
+	event, err := svc.ExportEvent(ctx, "camp-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Operation Test", event.Event.Info)
+	assert.Len(t, event.Event.Attribute, 1)
+	campaignRepo.AssertExpectations(t)
+}
+
+func TestMispService_ExportEvent_NotFound(t *testing.T) {
+	svc, _, campaignRepo := setupMispService(t)
+	ctx := context.Background()
+
+	campaignRepo.On("GetIndicatorsTimeline", ctx, "missing", model.TimelineParams{GroupBy: "day"}).Return(nil, repository.ErrNotFound)
+
+	event, err := svc.ExportEvent(ctx, "missing")
+
+	assert.Nil(t, event)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
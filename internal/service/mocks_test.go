@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +28,30 @@ func (m *MockIndicatorRepository) Search(ctx context.Context, params model.Searc
 	return args.Get(0).(*model.SearchResult), args.Error(1)
 }
 
+func (m *MockIndicatorRepository) StreamSearch(ctx context.Context, params model.SearchParams, fn func(*model.Indicator) error) error {
+	args := m.Called(ctx, params)
+	if indicators, ok := args.Get(0).([]model.Indicator); ok {
+		for i := range indicators {
+			if err := fn(&indicators[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockIndicatorRepository) StreamAttributed(ctx context.Context, params model.SearchParams, fn func(*model.AttributedIndicator) error) error {
+	args := m.Called(ctx, params)
+	if indicators, ok := args.Get(0).([]model.AttributedIndicator); ok {
+		for i := range indicators {
+			if err := fn(&indicators[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *MockIndicatorRepository) GetIndicatorsByIDs(ctx context.Context, ids []string) ([]model.Indicator, error) {
 	args := m.Called(ctx, ids)
 	if args.Get(0) == nil {
@@ -35,6 +60,92 @@ func (m *MockIndicatorRepository) GetIndicatorsByIDs(ctx context.Context, ids []
 	return args.Get(0).([]model.Indicator), args.Error(1)
 }
 
+func (m *MockIndicatorRepository) Create(ctx context.Context, indicator *model.Indicator) error {
+	args := m.Called(ctx, indicator)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorRepository) Update(ctx context.Context, indicator *model.Indicator) error {
+	args := m.Called(ctx, indicator)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorRepository) Upsert(ctx context.Context, indicator *model.Indicator, force bool) (*model.Indicator, bool, error) {
+	args := m.Called(ctx, indicator, force)
+	previous, _ := args.Get(0).(*model.Indicator)
+	return previous, args.Bool(1), args.Error(2)
+}
+
+func (m *MockIndicatorRepository) BulkUpsert(ctx context.Context, indicators []model.BulkIndicator, force bool) (*model.BulkResult, error) {
+	args := m.Called(ctx, indicators, force)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BulkResult), args.Error(1)
+}
+
+func (m *MockIndicatorRepository) Revoke(ctx context.Context, id, reason string) (*time.Time, error) {
+	args := m.Called(ctx, id, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockIndicatorRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorRepository) LinkActor(ctx context.Context, link *model.IndicatorActorLink) (bool, error) {
+	args := m.Called(ctx, link)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIndicatorRepository) UnlinkActor(ctx context.Context, indicatorID, actorID string) error {
+	args := m.Called(ctx, indicatorID, actorID)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorRepository) LinkCampaign(ctx context.Context, link *model.IndicatorCampaignLink) (bool, error) {
+	args := m.Called(ctx, link)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIndicatorRepository) UnlinkCampaign(ctx context.Context, indicatorID, campaignID string) error {
+	args := m.Called(ctx, indicatorID, campaignID)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorRepository) LinkSample(ctx context.Context, indicatorID, siblingID string) (*model.IndicatorSample, error) {
+	args := m.Called(ctx, indicatorID, siblingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IndicatorSample), args.Error(1)
+}
+
+func (m *MockIndicatorRepository) UnlinkSample(ctx context.Context, indicatorID string) error {
+	args := m.Called(ctx, indicatorID)
+	return args.Error(0)
+}
+
+func (m *MockIndicatorRepository) History(ctx context.Context, id string, params model.IndicatorHistoryParams) (*model.IndicatorHistoryResult, error) {
+	args := m.Called(ctx, id, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IndicatorHistoryResult), args.Error(1)
+}
+
+func (m *MockIndicatorRepository) GetAsOf(ctx context.Context, id string, asOf time.Time) (*model.IndicatorWithRelations, error) {
+	args := m.Called(ctx, id, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IndicatorWithRelations), args.Error(1)
+}
+
 type MockCampaignRepository struct {
 	mock.Mock
 }
@@ -47,6 +158,39 @@ func (m *MockCampaignRepository) GetByID(ctx context.Context, id string) (*model
 	return args.Get(0).(*model.Campaign), args.Error(1)
 }
 
+func (m *MockCampaignRepository) List(ctx context.Context, params model.CampaignListParams) (*model.CampaignListResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CampaignListResult), args.Error(1)
+}
+
+func (m *MockCampaignRepository) Create(ctx context.Context, campaign *model.Campaign) error {
+	args := m.Called(ctx, campaign)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) Update(ctx context.Context, campaign *model.Campaign) error {
+	args := m.Called(ctx, campaign)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCampaignRepository) LinkIndicator(ctx context.Context, link *model.IndicatorCampaignLink) (bool, error) {
+	args := m.Called(ctx, link)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCampaignRepository) UnlinkIndicator(ctx context.Context, campaignID, indicatorID string) error {
+	args := m.Called(ctx, campaignID, indicatorID)
+	return args.Error(0)
+}
+
 func (m *MockCampaignRepository) GetIndicatorsTimeline(ctx context.Context, campaignID string, params model.TimelineParams) (*model.CampaignWithTimeline, error) {
 	args := m.Called(ctx, campaignID, params)
 	if args.Get(0) == nil {
@@ -55,6 +199,39 @@ func (m *MockCampaignRepository) GetIndicatorsTimeline(ctx context.Context, camp
 	return args.Get(0).(*model.CampaignWithTimeline), args.Error(1)
 }
 
+type MockActorRepository struct {
+	mock.Mock
+}
+
+func (m *MockActorRepository) List(ctx context.Context, params model.ActorListParams) (*model.ActorListResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ActorListResult), args.Error(1)
+}
+
+func (m *MockActorRepository) GetByID(ctx context.Context, id string) (*model.ThreatActorDetail, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ThreatActorDetail), args.Error(1)
+}
+
+func (m *MockActorRepository) GetIndicators(ctx context.Context, actorID string, params model.ActorIndicatorParams) (*model.ActorIndicatorResult, error) {
+	args := m.Called(ctx, actorID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ActorIndicatorResult), args.Error(1)
+}
+
+func (m *MockActorRepository) SetAliases(ctx context.Context, actorID string, aliases []string) error {
+	args := m.Called(ctx, actorID, aliases)
+	return args.Error(0)
+}
+
 type MockDashboardRepository struct {
 	mock.Mock
 }
@@ -66,3 +243,115 @@ func (m *MockDashboardRepository) GetSummary(ctx context.Context, timeRange stri
 	}
 	return args.Get(0).(*model.DashboardSummary), args.Error(1)
 }
+
+type MockBundleRepository struct {
+	mock.Mock
+}
+
+func (m *MockBundleRepository) GetByIndicator(ctx context.Context, indicatorID string) (*model.IntelBundle, error) {
+	args := m.Called(ctx, indicatorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IntelBundle), args.Error(1)
+}
+
+func (m *MockBundleRepository) GetByCampaign(ctx context.Context, campaignID string) (*model.IntelBundle, error) {
+	args := m.Called(ctx, campaignID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IntelBundle), args.Error(1)
+}
+
+func (m *MockBundleRepository) Import(ctx context.Context, data *model.IntelImport) (*model.ImportResult, error) {
+	args := m.Called(ctx, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportResult), args.Error(1)
+}
+
+type MockTaxiiRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaxiiRepository) ListCampaigns(ctx context.Context) ([]model.Campaign, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Campaign), args.Error(1)
+}
+
+func (m *MockTaxiiRepository) GetCampaign(ctx context.Context, id string) (*model.Campaign, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Campaign), args.Error(1)
+}
+
+func (m *MockTaxiiRepository) GetObjects(ctx context.Context, params model.TaxiiObjectParams) ([]model.TaxiiIndicator, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TaxiiIndicator), args.Error(1)
+}
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *model.APIKey, hash string) error {
+	args := m.Called(ctx, key, hash)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]model.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetActiveByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id string) (*model.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.APIKey), args.Error(1)
+}
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, event *model.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, params model.AuditListParams) (*model.AuditListResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AuditListResult), args.Error(1)
+}
+
+func newMockAuditService() (*AuditService, *MockAuditRepository) {
+	repo := new(MockAuditRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return NewAuditService(repo), repo
+}
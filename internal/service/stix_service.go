@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/stix"
+)
+
+var stixExportParams = map[string]string{"format": "stix"}
+
+type StixService struct {
+	repo  repository.BundleRepositoryInterface
+	cache *cache.Cache
+	audit *AuditService
+}
+
+func NewStixService(repo repository.BundleRepositoryInterface, c *cache.Cache, audit *AuditService) *StixService {
+	return &StixService{
+		repo:  repo,
+		cache: c,
+		audit: audit,
+	}
+}
+
+func (s *StixService) ExportIndicator(ctx context.Context, indicatorID string) (*stix.Bundle, error) {
+	data, err := s.repo.GetByIndicator(ctx, indicatorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.RecordExport(ctx, model.EntityIndicator, indicatorID, stixExportParams); err != nil {
+		return nil, err
+	}
+	return stix.BuildBundle(data), nil
+}
+
+func (s *StixService) ExportCampaign(ctx context.Context, campaignID string) (*stix.Bundle, error) {
+	data, err := s.repo.GetByCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.audit.RecordExport(ctx, model.EntityCampaign, campaignID, stixExportParams); err != nil {
+		return nil, err
+	}
+	return stix.BuildBundle(data), nil
+}
+
+func (s *StixService) Import(ctx context.Context, payload []byte, force bool) (*model.ImportResult, error) {
+	data, rejected, err := stix.ParseBundle(payload)
+	if err != nil {
+		return nil, newValidationError("bundle", err.Error())
+	}
+	data.Force = force
+	return importIntel(ctx, s.repo, s.cache, s.audit, "stix", data, rejected)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/cache"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/stix"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStixService(t *testing.T) (*StixService, *MockBundleRepository, *cache.Cache) {
+	mockRepo := new(MockBundleRepository)
+	c, err := cache.New(cache.Config{MaxSizeMB: 10})
+	require.NoError(t, err)
+	audit, _ := newMockAuditService()
+	svc := NewStixService(mockRepo, c, audit)
+	return svc, mockRepo, c
+}
+
+func TestStixService_ExportCampaign_Success(t *testing.T) {
+	svc, mockRepo, _ := setupStixService(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	data := &model.IntelBundle{
+		Indicators: []model.Indicator{{ID: "ind-1", Type: "domain", Value: "evil.example.com", CreatedAt: now, UpdatedAt: now}},
+		Campaigns:  []model.Campaign{{ID: "camp-1", Name: "Operation Test", CreatedAt: now, UpdatedAt: now}},
+		IndicatorCampaigns: []model.IndicatorCampaignLink{
+			{IndicatorID: "ind-1", CampaignID: "camp-1", AddedAt: now},
+		},
+	}
+	mockRepo.On("GetByCampaign", ctx, "camp-1").Return(data, nil)
+
+	bundle, err := svc.ExportCampaign(ctx, "camp-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, stix.TypeBundle, bundle.Type)
+	assert.Len(t, bundle.Objects, 3)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStixService_ExportIndicator_NotFound(t *testing.T) {
+	svc, mockRepo, _ := setupStixService(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByIndicator", ctx, "missing").Return(nil, repository.ErrNotFound)
+
+	bundle, err := svc.ExportIndicator(ctx, "missing")
+
+	assert.Nil(t, bundle)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStixService_Import_RejectsInvalidObjects(t *testing.T) {
+	svc, mockRepo, c := setupStixService(t)
+	ctx := context.Background()
+
+	searchKey := cache.GenerateKey(ctx, "search", map[string]string{"type": "ip"})
+	c.Set(searchKey, &model.SearchResult{}, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	payload := []byte(`{
+		"type": "bundle",
+		"id": "bundle--0b6f0e3c-4f44-4d8e-a4b5-32f4f1f0f9a1",
+		"objects": [
+			{"type": "indicator", "spec_version": "2.1", "id": "indicator--a1", "pattern": "[domain-name:value = 'evil.example.com']", "pattern_type": "stix", "valid_from": "2024-01-01T00:00:00Z"},
+			{"type": "indicator", "spec_version": "2.1", "id": "indicator--a2", "pattern": "[domain-name:value = 'bad.example.com']", "pattern_type": "stix", "valid_from": "2024-01-01T00:00:00Z", "confidence": 150},
+			{"type": "campaign", "spec_version": "2.1", "id": "campaign--c1", "name": ""},
+			{"type": "malware", "spec_version": "2.1", "id": "malware--m1", "name": "Emotet"}
+		]
+	}`)
+
+	mockRepo.On("Import", ctx, mock.MatchedBy(func(data *model.IntelImport) bool {
+		return len(data.Indicators) == 1 && data.Indicators[0].Ref == "indicator--a1" && len(data.Campaigns) == 0
+	})).Return(&model.ImportResult{
+		Indicators: model.ImportCounts{Created: 1},
+		Errors:     []model.ImportError{},
+	}, nil)
+
+	result, err := svc.Import(ctx, payload, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Indicators.Created)
+	assert.Equal(t, 3, result.Rejected)
+	assert.Equal(t, "malware--m1", result.Errors[0].Ref)
+	assert.Equal(t, "indicator--a2", result.Errors[1].Ref)
+	assert.Equal(t, "campaign--c1", result.Errors[2].Ref)
+
+	time.Sleep(20 * time.Millisecond)
+	_, found := c.Get(searchKey)
+	assert.False(t, found)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStixService_Import_InvalidBundle(t *testing.T) {
+	svc, mockRepo, _ := setupStixService(t)
+
+	result, err := svc.Import(context.Background(), []byte(`{"type": "indicator"}`), false)
+
+	assert.Nil(t, result)
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	mockRepo.AssertNotCalled(t, "Import")
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/stix"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/taxii"
+)
+
+type TaxiiService struct {
+	repo  repository.TaxiiRepositoryInterface
+	audit *AuditService
+}
+
+func NewTaxiiService(repo repository.TaxiiRepositoryInterface, audit *AuditService) *TaxiiService {
+	return &TaxiiService{repo: repo, audit: audit}
+}
+
+func (s *TaxiiService) Collections(ctx context.Context) ([]taxii.Collection, error) {
+	campaigns, err := s.repo.ListCampaigns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]taxii.Collection, 0, len(campaigns)+1)
+	collections = append(collections, indicatorsCollection())
+	for _, campaign := range campaigns {
+		collections = append(collections, campaignCollection(campaign))
+	}
+
+	return collections, nil
+}
+
+func (s *TaxiiService) Collection(ctx context.Context, id string) (*taxii.Collection, error) {
+	if id == taxii.IndicatorsCollectionID {
+		collection := indicatorsCollection()
+		return &collection, nil
+	}
+
+	campaign, err := s.repo.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := campaignCollection(*campaign)
+	return &collection, nil
+}
+
+func (s *TaxiiService) Objects(ctx context.Context, collectionID string, params model.TaxiiObjectParams) (*taxii.Envelope, error) {
+	if collectionID != taxii.IndicatorsCollectionID {
+		if _, err := s.repo.GetCampaign(ctx, collectionID); err != nil {
+			return nil, err
+		}
+		params.CampaignID = collectionID
+	}
+
+	if err := s.audit.RecordExport(ctx, model.EntityCollection, collectionID, taxiiAuditParams(params)); err != nil {
+		return nil, err
+	}
+
+	limit := params.Limit
+	params.Limit = limit + 1
+
+	rows, err := s.repo.GetObjects(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := &taxii.Envelope{}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		envelope.More = true
+	}
+	if len(rows) == 0 {
+		return envelope, nil
+	}
+
+	for _, row := range rows {
+		obj, err := stix.FromIndicator(row.Indicator)
+		if err != nil {
+			continue
+		}
+		envelope.Objects = append(envelope.Objects, obj)
+	}
+
+	last := rows[len(rows)-1]
+	envelope.DateAddedFirst = rows[0].DateAdded
+	envelope.DateAddedLast = last.DateAdded
+	if envelope.More {
+		envelope.Next = taxii.EncodeCursor(last.DateAdded, last.Indicator.ID)
+	}
+
+	return envelope, nil
+}
+
+func taxiiAuditParams(params model.TaxiiObjectParams) map[string]interface{} {
+	audit := map[string]interface{}{"format": "taxii", "limit": params.Limit}
+	if params.AddedAfter != nil {
+		audit["added_after"] = params.AddedAfter
+	}
+	if params.AfterDate != nil {
+		audit["next"] = taxii.EncodeCursor(*params.AfterDate, params.AfterID)
+	}
+	return audit
+}
+
+func indicatorsCollection() taxii.Collection {
+	return taxii.Collection{
+		ID:          taxii.IndicatorsCollectionID,
+		Title:       "All indicators",
+		Description: "Every indicator known to the platform",
+		CanRead:     true,
+		MediaTypes:  []string{stix.MediaType},
+	}
+}
+
+func campaignCollection(campaign model.Campaign) taxii.Collection {
+	return taxii.Collection{
+		ID:          campaign.ID,
+		Title:       campaign.Name,
+		Description: campaign.Description,
+		CanRead:     true,
+		MediaTypes:  []string{stix.MediaType},
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/repository"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/stix"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/taxii"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxiiService_Collections(t *testing.T) {
+	mockRepo := new(MockTaxiiRepository)
+	audit, _ := newMockAuditService()
+	svc := NewTaxiiService(mockRepo, audit)
+	ctx := context.Background()
+
+	mockRepo.On("ListCampaigns", ctx).Return([]model.Campaign{
+		{ID: "550e8400-e29b-41d4-a716-446655440000", Name: "Operation Test"},
+	}, nil)
+
+	collections, err := svc.Collections(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, collections, 2)
+	assert.Equal(t, taxii.IndicatorsCollectionID, collections[0].ID)
+	assert.Equal(t, "Operation Test", collections[1].Title)
+	assert.True(t, collections[1].CanRead)
+	assert.False(t, collections[1].CanWrite)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaxiiService_Objects_Paginates(t *testing.T) {
+	mockRepo := new(MockTaxiiRepository)
+	audit, _ := newMockAuditService()
+	svc := NewTaxiiService(mockRepo, audit)
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []model.TaxiiIndicator{
+		{Indicator: model.Indicator{ID: "11111111-1111-1111-1111-111111111111", Type: model.IndicatorTypeIP, Value: "10.0.0.1"}, DateAdded: base},
+		{Indicator: model.Indicator{ID: "22222222-2222-2222-2222-222222222222", Type: model.IndicatorTypeDomain, Value: "evil.example.com"}, DateAdded: base.Add(time.Minute)},
+		{Indicator: model.Indicator{ID: "33333333-3333-3333-3333-333333333333", Type: model.IndicatorTypeURL, Value: "http://evil.example.com"}, DateAdded: base.Add(2 * time.Minute)},
+	}
+	mockRepo.On("GetObjects", ctx, model.TaxiiObjectParams{Limit: 3}).Return(rows, nil)
+
+	envelope, err := svc.Objects(ctx, taxii.IndicatorsCollectionID, model.TaxiiObjectParams{Limit: 2})
+
+	require.NoError(t, err)
+	assert.True(t, envelope.More)
+	require.Len(t, envelope.Objects, 2)
+	assert.Equal(t, stix.IndicatorID(rows[0].Indicator.ID), envelope.Objects[0].(*stix.Indicator).ID)
+	assert.Equal(t, base, envelope.DateAddedFirst)
+	assert.Equal(t, base.Add(time.Minute), envelope.DateAddedLast)
+
+	dateAdded, id, err := taxii.DecodeCursor(envelope.Next)
+	require.NoError(t, err)
+	assert.True(t, dateAdded.Equal(base.Add(time.Minute)))
+	assert.Equal(t, rows[1].Indicator.ID, id)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaxiiService_Objects_CampaignCollection(t *testing.T) {
+	mockRepo := new(MockTaxiiRepository)
+	audit, _ := newMockAuditService()
+	svc := NewTaxiiService(mockRepo, audit)
+	ctx := context.Background()
+
+	campaignID := "550e8400-e29b-41d4-a716-446655440000"
+	mockRepo.On("GetCampaign", ctx, campaignID).Return(&model.Campaign{ID: campaignID}, nil)
+	mockRepo.On("GetObjects", ctx, model.TaxiiObjectParams{CampaignID: campaignID, Limit: 101}).Return([]model.TaxiiIndicator{}, nil)
+
+	envelope, err := svc.Objects(ctx, campaignID, model.TaxiiObjectParams{Limit: 100})
+
+	require.NoError(t, err)
+	assert.False(t, envelope.More)
+	assert.Empty(t, envelope.Objects)
+	assert.Empty(t, envelope.Next)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaxiiService_Collection_NotFound(t *testing.T) {
+	mockRepo := new(MockTaxiiRepository)
+	audit, _ := newMockAuditService()
+	svc := NewTaxiiService(mockRepo, audit)
+	ctx := context.Background()
+
+	mockRepo.On("GetCampaign", ctx, "missing").Return(nil, repository.ErrNotFound)
+
+	collection, err := svc.Collection(ctx, "missing")
+
+	assert.Nil(t, collection)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
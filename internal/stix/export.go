@@ -0,0 +1,250 @@
+package stix
+
+import (
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/google/uuid"
+)
+
+var relationshipNamespace = uuid.MustParse("8f2b1c1e-3c55-4f0e-9a8e-6d1b7c0f4a21")
+
+var motivations = map[string]string{
+	"financial":   "personal-gain",
+	"espionage":   "organizational-gain",
+	"hacktivism":  "ideology",
+	"destruction": "dominance",
+}
+
+func NewBundle(objects []interface{}) *Bundle {
+	if objects == nil {
+		objects = []interface{}{}
+	}
+	return &Bundle{
+		Type:    TypeBundle,
+		ID:      TypeBundle + "--" + uuid.New().String(),
+		Objects: objects,
+	}
+}
+
+func BuildBundle(data *model.IntelBundle) *Bundle {
+	var objects []interface{}
+
+	markings := make(map[string]bool)
+	for _, ind := range data.Indicators {
+		markings[TLPMarkingRef(ind.TLP)] = true
+	}
+	for _, campaign := range data.Campaigns {
+		markings[TLPMarkingRef(campaign.TLP)] = true
+	}
+	for _, level := range marking.Levels() {
+		if markings[TLPMarkingRef(string(level))] {
+			objects = append(objects, TLPMarkingDefinition(string(level)))
+		}
+	}
+
+	indicatorRefs := make(map[string]string)
+	for _, ind := range data.Indicators {
+		obj, err := FromIndicator(ind)
+		if err != nil {
+			continue
+		}
+		indicatorRefs[ind.ID] = obj.ID
+		objects = append(objects, obj)
+	}
+
+	campaignRefs := make(map[string]string)
+	for _, campaign := range data.Campaigns {
+		obj := FromCampaign(campaign)
+		campaignRefs[campaign.ID] = obj.ID
+		objects = append(objects, obj)
+	}
+
+	actorRefs := make(map[string]string)
+	for _, actor := range data.ThreatActors {
+		obj := FromThreatActor(actor)
+		actorRefs[actor.ID] = obj.ID
+		objects = append(objects, obj)
+	}
+
+	for _, campaign := range data.Campaigns {
+		actorRef, ok := actorRefs[campaign.ThreatActorID]
+		if !ok {
+			continue
+		}
+		objects = append(objects, NewRelationship(
+			campaignRefs[campaign.ID], RelationshipAttributedTo, actorRef,
+			campaign.CreatedAt, nil,
+		))
+	}
+
+	for _, link := range data.IndicatorCampaigns {
+		indicatorRef, ok := indicatorRefs[link.IndicatorID]
+		if !ok {
+			continue
+		}
+		campaignRef, ok := campaignRefs[link.CampaignID]
+		if !ok {
+			campaignRef = CampaignID(link.CampaignID)
+		}
+		objects = append(objects, NewRelationship(
+			indicatorRef, RelationshipIndicates, campaignRef,
+			link.AddedAt, nil,
+		))
+	}
+
+	for _, link := range data.IndicatorActors {
+		indicatorRef, ok := indicatorRefs[link.IndicatorID]
+		if !ok {
+			continue
+		}
+		actorRef, ok := actorRefs[link.ActorID]
+		if !ok {
+			continue
+		}
+		confidence := link.AttributionConfidence
+		objects = append(objects, NewRelationship(
+			indicatorRef, RelationshipIndicates, actorRef,
+			link.AddedAt, &confidence,
+		))
+	}
+
+	return NewBundle(objects)
+}
+
+func IndicatorID(id string) string {
+	return TypeIndicator + "--" + id
+}
+
+func CampaignID(id string) string {
+	return TypeCampaign + "--" + id
+}
+
+func ThreatActorID(id string) string {
+	return TypeThreatActor + "--" + id
+}
+
+func RelationshipID(sourceRef, relationshipType, targetRef string) string {
+	name := sourceRef + "|" + relationshipType + "|" + targetRef
+	return TypeRelationship + "--" + uuid.NewSHA1(relationshipNamespace, []byte(name)).String()
+}
+
+func NewRelationship(sourceRef, relationshipType, targetRef string, created time.Time, confidence *int) *Relationship {
+	return &Relationship{
+		Common: Common{
+			Type:        TypeRelationship,
+			SpecVersion: SpecVersion,
+			ID:          RelationshipID(sourceRef, relationshipType, targetRef),
+			Created:     NewTimestamp(created),
+			Modified:    NewTimestamp(created),
+			Confidence:  confidence,
+		},
+		RelationshipType: relationshipType,
+		SourceRef:        sourceRef,
+		TargetRef:        targetRef,
+	}
+}
+
+func FromIndicator(ind model.Indicator) (*Indicator, error) {
+	pattern, err := Pattern(ind.Type, ind.Value, ind.HashAlgo)
+	if err != nil {
+		return nil, err
+	}
+
+	confidence := ind.Confidence
+	obj := &Indicator{
+		Common: Common{
+			Type:        TypeIndicator,
+			SpecVersion: SpecVersion,
+			ID:          refOrDefault(storedID(ind.Metadata, TypeIndicator), IndicatorID(ind.ID)),
+			Created:     NewTimestamp(ind.CreatedAt),
+			Modified:    NewTimestamp(ind.UpdatedAt),
+			Confidence:  &confidence,
+			Labels:      ind.Tags,
+			Revoked:     ind.RevokedAt != nil,
+
+			ObjectMarkingRefs: markingRefs(ind.TLP),
+		},
+		Name:           ind.Value,
+		Description:    ind.Description,
+		IndicatorTypes: []string{"malicious-activity"},
+		Pattern:        pattern,
+		PatternType:    "stix",
+		ValidFrom:      NewTimestamp(ind.CreatedAt),
+	}
+
+	if ind.FirstSeen != nil {
+		obj.ValidFrom = NewTimestamp(*ind.FirstSeen)
+	}
+	if !ind.IsActive && ind.LastSeen != nil && ind.LastSeen.After(obj.ValidFrom.Time) {
+		validUntil := NewTimestamp(*ind.LastSeen)
+		obj.ValidUntil = &validUntil
+	}
+
+	return obj, nil
+}
+
+func FromCampaign(campaign model.Campaign) *Campaign {
+	obj := &Campaign{
+		Common: Common{
+			Type:        TypeCampaign,
+			SpecVersion: SpecVersion,
+			ID:          refOrDefault(storedID(campaign.Metadata, TypeCampaign), CampaignID(campaign.ID)),
+			Created:     NewTimestamp(campaign.CreatedAt),
+			Modified:    NewTimestamp(campaign.UpdatedAt),
+
+			ObjectMarkingRefs: markingRefs(campaign.TLP),
+		},
+		Name:        campaign.Name,
+		Description: campaign.Description,
+	}
+
+	if campaign.StartDate != nil {
+		firstSeen := NewTimestamp(*campaign.StartDate)
+		obj.FirstSeen = &firstSeen
+	}
+	if campaign.EndDate != nil {
+		lastSeen := NewTimestamp(*campaign.EndDate)
+		obj.LastSeen = &lastSeen
+	}
+
+	return obj
+}
+
+func FromThreatActor(actor model.ThreatActor) *ThreatActor {
+	id := refOrDefault(storedID(actor.Metadata, TypeThreatActor, TypeIntrusionSet), ThreatActorID(actor.ID))
+	confidence := actor.ConfidenceLevel
+	obj := &ThreatActor{
+		Common: Common{
+			Type:        refType(id),
+			SpecVersion: SpecVersion,
+			ID:          id,
+			Created:     NewTimestamp(actor.CreatedAt),
+			Modified:    NewTimestamp(actor.UpdatedAt),
+			Confidence:  &confidence,
+		},
+		Name:              actor.Name,
+		Description:       actor.Description,
+		Aliases:           actor.Aliases,
+		PrimaryMotivation: motivations[actor.Motivation],
+	}
+
+	if actor.FirstSeen != nil {
+		firstSeen := NewTimestamp(*actor.FirstSeen)
+		obj.FirstSeen = &firstSeen
+	}
+	if actor.LastSeen != nil {
+		lastSeen := NewTimestamp(*actor.LastSeen)
+		obj.LastSeen = &lastSeen
+	}
+
+	return obj
+}
+
+func refOrDefault(ref, fallback string) string {
+	if ref == "" {
+		return fallback
+	}
+	return ref
+}
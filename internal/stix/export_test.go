@@ -0,0 +1,179 @@
+package stix
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPattern(t *testing.T) {
+	tests := []struct {
+		name          string
+		indicatorType model.IndicatorType
+		value         string
+		expected      string
+	}{
+		{"ipv4", model.IndicatorTypeIP, "10.0.0.1", "[ipv4-addr:value = '10.0.0.1']"},
+		{"ipv4 cidr", model.IndicatorTypeIP, "10.0.0.0/24", "[ipv4-addr:value = '10.0.0.0/24']"},
+		{"ipv6", model.IndicatorTypeIP, "2001:db8::1", "[ipv6-addr:value = '2001:db8::1']"},
+		{"domain", model.IndicatorTypeDomain, "evil.example.com", "[domain-name:value = 'evil.example.com']"},
+		{"url with quote", model.IndicatorTypeURL, "http://evil.example.com/a'b", `[url:value = 'http://evil.example.com/a\'b']`},
+		{"md5", model.IndicatorTypeHash, "D41D8CD98F00B204E9800998ECF8427E", "[file:hashes.'MD5' = 'd41d8cd98f00b204e9800998ecf8427e']"},
+		{"sha256", model.IndicatorTypeHash, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "[file:hashes.'SHA-256' = 'e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855']"},
+		{"email", model.IndicatorTypeEmail, "phish@example.com", "[email-addr:value = 'phish@example.com']"},
+		{"registry key", model.IndicatorTypeRegistryKey, `HKEY_CURRENT_USER\Software\Evil`, `[windows-registry-key:key = 'HKEY_CURRENT_USER\\Software\\Evil']`},
+		{"ssdeep", model.IndicatorTypeHash, "3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C", "[file:hashes.'SSDEEP' = '3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C']"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := Pattern(tt.indicatorType, tt.value, "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, pattern)
+		})
+	}
+}
+
+func TestPattern_Imphash(t *testing.T) {
+	pattern, err := Pattern(model.IndicatorTypeHash, "F34D5F2D4577ED6D9CEEC516C1F5A744", model.HashAlgoImphash)
+
+	require.NoError(t, err)
+	assert.Equal(t, "[file:extensions.'windows-pebinary-ext'.imphash = 'f34d5f2d4577ed6d9ceec516c1f5a744']", pattern)
+}
+
+func TestPattern_Unsupported(t *testing.T) {
+	_, err := Pattern(model.IndicatorTypeHash, "abc", "")
+	assert.Error(t, err)
+
+	_, err = Pattern(model.IndicatorTypeIP, "not-an-ip", "")
+	assert.Error(t, err)
+}
+
+func TestBuildBundle(t *testing.T) {
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	data := &model.IntelBundle{
+		Indicators: []model.Indicator{
+			{ID: "11111111-1111-1111-1111-111111111111", Type: "ip", Value: "10.0.0.1", Confidence: 80, IsActive: true, CreatedAt: created, UpdatedAt: created},
+			{ID: "22222222-2222-2222-2222-222222222222", Type: "hash", Value: "bad", CreatedAt: created, UpdatedAt: created},
+		},
+		Campaigns: []model.Campaign{
+			{ID: "33333333-3333-3333-3333-333333333333", Name: "Operation Test", ThreatActorID: "44444444-4444-4444-4444-444444444444", CreatedAt: created, UpdatedAt: created},
+		},
+		ThreatActors: []model.ThreatActor{
+			{ID: "44444444-4444-4444-4444-444444444444", Name: "APT-Test", Motivation: "espionage", CreatedAt: created, UpdatedAt: created},
+		},
+		IndicatorCampaigns: []model.IndicatorCampaignLink{
+			{IndicatorID: "11111111-1111-1111-1111-111111111111", CampaignID: "33333333-3333-3333-3333-333333333333", AddedAt: created},
+			{IndicatorID: "22222222-2222-2222-2222-222222222222", CampaignID: "33333333-3333-3333-3333-333333333333", AddedAt: created},
+		},
+		IndicatorActors: []model.IndicatorActorLink{
+			{IndicatorID: "11111111-1111-1111-1111-111111111111", ActorID: "44444444-4444-4444-4444-444444444444", AttributionConfidence: 70, AddedAt: created},
+		},
+	}
+
+	bundle := BuildBundle(data)
+
+	assert.Equal(t, TypeBundle, bundle.Type)
+	assert.Contains(t, bundle.ID, "bundle--")
+
+	counts := make(map[string]int)
+	relationships := make(map[string]string)
+	for _, obj := range bundle.Objects {
+		switch o := obj.(type) {
+		case *Indicator:
+			counts[o.Type]++
+			assert.Equal(t, "indicator--11111111-1111-1111-1111-111111111111", o.ID)
+			assert.Equal(t, "[ipv4-addr:value = '10.0.0.1']", o.Pattern)
+		case *Campaign:
+			counts[o.Type]++
+		case *ThreatActor:
+			counts[o.Type]++
+			assert.Equal(t, "organizational-gain", o.PrimaryMotivation)
+		case *Relationship:
+			counts[o.Type]++
+			relationships[o.SourceRef+"->"+o.TargetRef] = o.RelationshipType
+		}
+	}
+
+	assert.Equal(t, 1, counts[TypeIndicator])
+	assert.Equal(t, 1, counts[TypeCampaign])
+	assert.Equal(t, 1, counts[TypeThreatActor])
+	assert.Equal(t, 3, counts[TypeRelationship])
+	assert.Equal(t, RelationshipAttributedTo, relationships["campaign--33333333-3333-3333-3333-333333333333->threat-actor--44444444-4444-4444-4444-444444444444"])
+	assert.Equal(t, RelationshipIndicates, relationships["indicator--11111111-1111-1111-1111-111111111111->campaign--33333333-3333-3333-3333-333333333333"])
+	assert.Equal(t, RelationshipIndicates, relationships["indicator--11111111-1111-1111-1111-111111111111->threat-actor--44444444-4444-4444-4444-444444444444"])
+}
+
+func TestBuildBundle_EmbedsTLPMarkings(t *testing.T) {
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	data := &model.IntelBundle{
+		Indicators: []model.Indicator{
+			{ID: "11111111-1111-1111-1111-111111111111", Type: "ip", Value: "10.0.0.1", TLP: "amber", CreatedAt: created, UpdatedAt: created},
+			{ID: "22222222-2222-2222-2222-222222222222", Type: "ip", Value: "10.0.0.2", TLP: "clear", CreatedAt: created, UpdatedAt: created},
+		},
+		Campaigns: []model.Campaign{
+			{ID: "33333333-3333-3333-3333-333333333333", Name: "Operation Test", TLP: "amber", CreatedAt: created, UpdatedAt: created},
+		},
+	}
+
+	bundle := BuildBundle(data)
+
+	var definitions []*MarkingDefinition
+	refs := make(map[string][]string)
+	for _, obj := range bundle.Objects {
+		switch o := obj.(type) {
+		case *MarkingDefinition:
+			definitions = append(definitions, o)
+		case *Indicator:
+			refs[o.ID] = o.ObjectMarkingRefs
+		case *Campaign:
+			refs[o.ID] = o.ObjectMarkingRefs
+		}
+	}
+
+	require.Len(t, definitions, 2)
+	assert.Equal(t, "TLP:WHITE", definitions[0].Name)
+	assert.Equal(t, map[string]string{"tlp": "white"}, definitions[0].Definition)
+	assert.Equal(t, "marking-definition--f88d31f6-486f-44da-b317-01333bde0b82", definitions[1].ID)
+	assert.Equal(t, []string{definitions[1].ID}, refs["indicator--11111111-1111-1111-1111-111111111111"])
+	assert.Equal(t, []string{definitions[0].ID}, refs["indicator--22222222-2222-2222-2222-222222222222"])
+	assert.Equal(t, []string{definitions[1].ID}, refs["campaign--33333333-3333-3333-3333-333333333333"])
+}
+
+func TestFromIndicator_MarksRevoked(t *testing.T) {
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	revoked := created.Add(24 * time.Hour)
+	ind := model.Indicator{ID: "11111111-1111-1111-1111-111111111111", Type: "ip", Value: "10.0.0.1", TLP: "clear",
+		CreatedAt: created, UpdatedAt: revoked, RevokedAt: &revoked, RevocationReason: model.RevocationFalsePositive}
+
+	obj, err := FromIndicator(ind)
+	require.NoError(t, err)
+	assert.True(t, obj.Revoked)
+
+	ind.RevokedAt = nil
+	obj, err = FromIndicator(ind)
+	require.NoError(t, err)
+	assert.False(t, obj.Revoked)
+}
+
+func TestRelationshipID_IsDeterministic(t *testing.T) {
+	first := RelationshipID("indicator--a", RelationshipIndicates, "campaign--b")
+	second := RelationshipID("indicator--a", RelationshipIndicates, "campaign--b")
+	other := RelationshipID("indicator--a", RelationshipIndicates, "campaign--c")
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+}
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	ts := NewTimestamp(time.Date(2024, 5, 12, 8, 17, 27, 0, time.FixedZone("ART", -3*3600)))
+
+	data, err := json.Marshal(ts)
+
+	require.NoError(t, err)
+	assert.Equal(t, `"2024-05-12T11:17:27.000Z"`, string(data))
+}
@@ -0,0 +1,256 @@
+package stix
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+const (
+	MetadataKey  = "stix_id"
+	ImportSource = "stix"
+
+	defaultConfidence = 50
+)
+
+var motivationsFromStix = map[string]string{
+	"personal-gain":       "financial",
+	"organizational-gain": "espionage",
+	"ideology":            "hacktivism",
+	"dominance":           "destruction",
+}
+
+var refEntityTypes = map[string]string{
+	TypeIndicator:    model.EntityIndicator,
+	TypeCampaign:     model.EntityCampaign,
+	TypeThreatActor:  model.EntityThreatActor,
+	TypeIntrusionSet: model.EntityThreatActor,
+}
+
+type rawBundle struct {
+	Type    string            `json:"type"`
+	ID      string            `json:"id"`
+	Objects []json.RawMessage `json:"objects"`
+}
+
+type objectHeader struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+func ParseBundle(data []byte) (*model.IntelImport, []model.ImportError, error) {
+	var bundle rawBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if bundle.Type != TypeBundle {
+		return nil, nil, fmt.Errorf("expected object of type %q, got %q", TypeBundle, bundle.Type)
+	}
+
+	result := &model.IntelImport{RefKey: MetadataKey}
+	var rejected []model.ImportError
+
+	for i, raw := range bundle.Objects {
+		var header objectHeader
+		if err := json.Unmarshal(raw, &header); err != nil {
+			rejected = append(rejected, model.ImportError{
+				Ref:    fmt.Sprintf("objects[%d]", i),
+				Reason: "invalid object: " + err.Error(),
+			})
+			continue
+		}
+
+		if err := parseObject(result, header, raw); err != nil {
+			rejected = append(rejected, model.ImportError{Ref: header.ID, Type: header.Type, Reason: err.Error()})
+		}
+	}
+
+	return result, rejected, nil
+}
+
+func parseObject(result *model.IntelImport, header objectHeader, raw json.RawMessage) error {
+	if !strings.HasPrefix(header.ID, header.Type+"--") {
+		return fmt.Errorf("id must start with %q", header.Type+"--")
+	}
+
+	switch header.Type {
+	case TypeIndicator:
+		var obj Indicator
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return fmt.Errorf("invalid indicator: %w", err)
+		}
+		ind, err := ToIndicator(&obj)
+		if err != nil {
+			return err
+		}
+		result.Indicators = append(result.Indicators, model.ImportIndicator{Ref: obj.ID, Indicator: *ind})
+	case TypeCampaign:
+		var obj Campaign
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return fmt.Errorf("invalid campaign: %w", err)
+		}
+		result.Campaigns = append(result.Campaigns, model.ImportCampaign{Ref: obj.ID, Campaign: *ToCampaign(&obj)})
+	case TypeThreatActor, TypeIntrusionSet:
+		var obj ThreatActor
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return fmt.Errorf("invalid %s: %w", header.Type, err)
+		}
+		result.ThreatActors = append(result.ThreatActors, model.ImportThreatActor{Ref: obj.ID, ThreatActor: *ToThreatActor(&obj)})
+	case TypeRelationship:
+		var obj Relationship
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return fmt.Errorf("invalid relationship: %w", err)
+		}
+		rel, err := ToRelationship(&obj)
+		if err != nil {
+			return err
+		}
+		result.Relationships = append(result.Relationships, *rel)
+	case TypeMarkingDefinition:
+		// Markings are resolved from object_marking_refs; the definitions carry nothing to import.
+	default:
+		return fmt.Errorf("unsupported object type %q", header.Type)
+	}
+
+	return nil
+}
+
+func ToIndicator(obj *Indicator) (*model.Indicator, error) {
+	if obj.PatternType != "" && obj.PatternType != "stix" {
+		return nil, fmt.Errorf("unsupported pattern_type %q", obj.PatternType)
+	}
+
+	indicatorType, value, hashAlgo, err := ParsePattern(obj.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	ind := &model.Indicator{
+		Type:        indicatorType,
+		Value:       value,
+		HashAlgo:    hashAlgo,
+		Description: obj.Description,
+		Severity:    "medium",
+		Confidence:  defaultConfidence,
+		IsActive:    !obj.Revoked,
+		Tags:        obj.Labels,
+		Metadata:    referenceMetadata(obj.ID),
+		Source:      ImportSource,
+		TLP:         TLPFromMarkingRefs(obj.ObjectMarkingRefs),
+	}
+	if ind.Description == "" && obj.Name != value {
+		ind.Description = obj.Name
+	}
+	if obj.Confidence != nil {
+		ind.Confidence = *obj.Confidence
+	}
+	if !obj.ValidFrom.IsZero() {
+		ind.FirstSeen = &obj.ValidFrom.Time
+	}
+	if obj.ValidUntil != nil {
+		ind.LastSeen = &obj.ValidUntil.Time
+		if !obj.ValidUntil.After(time.Now()) {
+			ind.IsActive = false
+		}
+	}
+
+	return ind, nil
+}
+
+func ToCampaign(obj *Campaign) *model.Campaign {
+	campaign := &model.Campaign{
+		Name:        obj.Name,
+		Description: obj.Description,
+		Metadata:    referenceMetadata(obj.ID),
+		TLP:         TLPFromMarkingRefs(obj.ObjectMarkingRefs),
+	}
+	if obj.FirstSeen != nil {
+		campaign.StartDate = &obj.FirstSeen.Time
+	}
+	if obj.LastSeen != nil {
+		campaign.EndDate = &obj.LastSeen.Time
+	}
+
+	return campaign
+}
+
+func ToThreatActor(obj *ThreatActor) *model.ThreatActor {
+	actor := &model.ThreatActor{
+		Name:            obj.Name,
+		Description:     obj.Description,
+		Motivation:      motivationsFromStix[obj.PrimaryMotivation],
+		ConfidenceLevel: defaultConfidence,
+		Aliases:         obj.Aliases,
+		Metadata:        referenceMetadata(obj.ID),
+	}
+	if obj.Confidence != nil {
+		actor.ConfidenceLevel = *obj.Confidence
+	}
+	if obj.FirstSeen != nil {
+		actor.FirstSeen = &obj.FirstSeen.Time
+	}
+	if obj.LastSeen != nil {
+		actor.LastSeen = &obj.LastSeen.Time
+	}
+
+	return actor
+}
+
+func ToRelationship(obj *Relationship) (*model.ImportRelationship, error) {
+	sourceType := refEntityTypes[refType(obj.SourceRef)]
+	targetType := refEntityTypes[refType(obj.TargetRef)]
+
+	supported := false
+	switch obj.RelationshipType {
+	case RelationshipIndicates:
+		supported = sourceType == model.EntityIndicator &&
+			(targetType == model.EntityCampaign || targetType == model.EntityThreatActor)
+	case RelationshipAttributedTo:
+		supported = sourceType == model.EntityCampaign && targetType == model.EntityThreatActor
+	}
+	if !supported {
+		return nil, fmt.Errorf("unsupported relationship %s %s %s",
+			refType(obj.SourceRef), obj.RelationshipType, refType(obj.TargetRef))
+	}
+
+	return &model.ImportRelationship{
+		Ref:        obj.ID,
+		SourceType: sourceType,
+		SourceRef:  obj.SourceRef,
+		TargetType: targetType,
+		TargetRef:  obj.TargetRef,
+		Confidence: obj.Confidence,
+	}, nil
+}
+
+func refType(ref string) string {
+	objectType, _, _ := strings.Cut(ref, "--")
+	return objectType
+}
+
+func referenceMetadata(id string) json.RawMessage {
+	metadata, _ := json.Marshal(map[string]string{MetadataKey: id})
+	return metadata
+}
+
+func storedID(metadata json.RawMessage, objectTypes ...string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(metadata, &fields); err != nil {
+		return ""
+	}
+	id, _ := fields[MetadataKey].(string)
+	for _, objectType := range objectTypes {
+		if strings.HasPrefix(id, objectType+"--") {
+			return id
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,157 @@
+package stix
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		indicatorType model.IndicatorType
+		value         string
+		hashAlgo      string
+	}{
+		{"ipv4", "[ipv4-addr:value = '10.0.0.1']", model.IndicatorTypeIP, "10.0.0.1", ""},
+		{"ipv6", "[ipv6-addr:value='2001:db8::1']", model.IndicatorTypeIP, "2001:db8::1", ""},
+		{"domain", "[domain-name:value = 'evil.example.com']", model.IndicatorTypeDomain, "evil.example.com", ""},
+		{"url with quote", `[url:value = 'http://evil.example.com/a\'b']`, model.IndicatorTypeURL, "http://evil.example.com/a'b", ""},
+		{"quoted hash", "[file:hashes.'SHA-256' = 'E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855']", model.IndicatorTypeHash, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", model.HashAlgoSHA256},
+		{"unquoted hash", "[file:hashes.MD5 = 'd41d8cd98f00b204e9800998ecf8427e']", model.IndicatorTypeHash, "d41d8cd98f00b204e9800998ecf8427e", model.HashAlgoMD5},
+		{"ssdeep keeps case", "[file:hashes.'SSDEEP' = '3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C']", model.IndicatorTypeHash, "3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C", model.HashAlgoSSDEEP},
+		{"mutex", "[mutex:name = 'Global\\\\DCPERSFWBP']", model.IndicatorTypeMutex, `Global\DCPERSFWBP`, ""},
+		{"user agent", "[network-traffic:extensions.'http-request-ext'.request_header.'User-Agent' = 'EvilBot/1.0']", model.IndicatorTypeUserAgent, "EvilBot/1.0", ""},
+		{"imphash", "[file:extensions.'windows-pebinary-ext'.imphash = 'F34D5F2D4577ED6D9CEEC516C1F5A744']", model.IndicatorTypeHash, "f34d5f2d4577ed6d9ceec516c1f5a744", model.HashAlgoImphash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indicatorType, value, hashAlgo, err := ParsePattern(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.indicatorType, indicatorType)
+			assert.Equal(t, tt.value, value)
+			assert.Equal(t, tt.hashAlgo, hashAlgo)
+		})
+	}
+}
+
+func TestParsePattern_Unsupported(t *testing.T) {
+	patterns := []string{
+		"[ipv4-addr:value = '10.0.0.1'] OR [ipv4-addr:value = '10.0.0.2']",
+		"[x509-certificate:serial_number = '01']",
+		"[file:name = 'evil.exe']",
+		"[file:hashes.'SHA3-256' = 'abc']",
+		"[domain-name:value LIKE '%.example.com']",
+	}
+
+	for _, pattern := range patterns {
+		_, _, _, err := ParsePattern(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
+
+func TestParsePattern_RoundTrip(t *testing.T) {
+	pattern, err := Pattern(model.IndicatorTypeURL, `http://evil.example.com/a\b'c`, "")
+	require.NoError(t, err)
+
+	indicatorType, value, _, err := ParsePattern(pattern)
+
+	require.NoError(t, err)
+	assert.Equal(t, model.IndicatorTypeURL, indicatorType)
+	assert.Equal(t, `http://evil.example.com/a\b'c`, value)
+}
+
+func TestParseBundle(t *testing.T) {
+	payload := []byte(`{
+		"type": "bundle",
+		"id": "bundle--5d0092c5-5f74-4287-9642-33f4c354e56d",
+		"objects": [
+			{"type": "indicator", "spec_version": "2.1", "id": "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f", "created": "2024-01-01T00:00:00.000Z", "modified": "2024-01-01T00:00:00.000Z", "name": "C2 domain", "pattern": "[domain-name:value = 'evil.example.com']", "pattern_type": "stix", "valid_from": "2024-01-01T00:00:00Z", "valid_until": "2024-02-01T00:00:00Z", "confidence": 80, "labels": ["c2"], "object_marking_refs": ["marking-definition--34098fce-860f-48ae-8e50-ebd3cc5e41da", "marking-definition--f88d31f6-486f-44da-b317-01333bde0b82"]},
+			{"type": "indicator", "spec_version": "2.1", "id": "indicator--f3c9d7f4-5b1a-4b8e-9a37-4b0a9f6a7d11", "pattern": "[x-custom:value = 'a']", "pattern_type": "stix", "valid_from": "2024-01-01T00:00:00Z"},
+			{"type": "indicator", "spec_version": "2.1", "id": "indicator--0f6d1f9e-3e4e-4f6b-9a0c-1b5d1c8d9e22", "pattern": "alert tcp any any", "pattern_type": "snort", "valid_from": "2024-01-01T00:00:00Z"},
+			{"type": "campaign", "spec_version": "2.1", "id": "campaign--83422c77-904c-4dc1-aff5-5c38f3a2c55c", "name": "Operation Test", "first_seen": "2024-01-01T00:00:00Z", "object_marking_refs": ["marking-definition--e828b379-4e03-4974-9ac4-e53a884c97c1"]},
+			{"type": "marking-definition", "spec_version": "2.1", "id": "marking-definition--34098fce-860f-48ae-8e50-ebd3cc5e41da", "definition_type": "tlp", "definition": {"tlp": "green"}},
+			{"type": "intrusion-set", "spec_version": "2.1", "id": "intrusion-set--4e78f46f-a023-4e5f-bc24-71b3ca22ec29", "name": "APT Test", "aliases": ["Test Bear"], "primary_motivation": "organizational-gain"},
+			{"type": "relationship", "spec_version": "2.1", "id": "relationship--1", "relationship_type": "indicates", "source_ref": "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f", "target_ref": "campaign--83422c77-904c-4dc1-aff5-5c38f3a2c55c"},
+			{"type": "relationship", "spec_version": "2.1", "id": "relationship--2", "relationship_type": "attributed-to", "source_ref": "campaign--83422c77-904c-4dc1-aff5-5c38f3a2c55c", "target_ref": "intrusion-set--4e78f46f-a023-4e5f-bc24-71b3ca22ec29"},
+			{"type": "relationship", "spec_version": "2.1", "id": "relationship--3", "relationship_type": "uses", "source_ref": "intrusion-set--4e78f46f-a023-4e5f-bc24-71b3ca22ec29", "target_ref": "malware--c7a4f5d2-1c3e-4a5f-8f77-0e8a7c9d4b33"},
+			{"type": "malware", "spec_version": "2.1", "id": "malware--c7a4f5d2-1c3e-4a5f-8f77-0e8a7c9d4b33", "name": "Emotet"}
+		]
+	}`)
+
+	data, rejected, err := ParseBundle(payload)
+
+	require.NoError(t, err)
+	assert.Equal(t, MetadataKey, data.RefKey)
+
+	require.Len(t, data.Indicators, 1)
+	ind := data.Indicators[0].Indicator
+	assert.Equal(t, "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f", data.Indicators[0].Ref)
+	assert.Equal(t, model.IndicatorTypeDomain, ind.Type)
+	assert.Equal(t, "evil.example.com", ind.Value)
+	assert.Equal(t, "C2 domain", ind.Description)
+	assert.Equal(t, 80, ind.Confidence)
+	assert.False(t, ind.IsActive)
+	assert.Equal(t, []string{"c2"}, ind.Tags)
+	assert.Equal(t, "amber", ind.TLP)
+	assert.JSONEq(t, `{"stix_id": "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"}`, string(ind.Metadata))
+
+	require.Len(t, data.Campaigns, 1)
+	assert.Equal(t, "Operation Test", data.Campaigns[0].Campaign.Name)
+	assert.Equal(t, "red", data.Campaigns[0].Campaign.TLP)
+
+	require.Len(t, data.ThreatActors, 1)
+	assert.Equal(t, "espionage", data.ThreatActors[0].ThreatActor.Motivation)
+	assert.Equal(t, []string{"Test Bear"}, data.ThreatActors[0].ThreatActor.Aliases)
+
+	require.Len(t, data.Relationships, 2)
+	assert.Equal(t, model.EntityCampaign, data.Relationships[0].TargetType)
+	assert.Equal(t, model.EntityThreatActor, data.Relationships[1].TargetType)
+
+	require.Len(t, rejected, 4)
+	assert.Equal(t, "indicator--f3c9d7f4-5b1a-4b8e-9a37-4b0a9f6a7d11", rejected[0].Ref)
+	assert.Equal(t, "indicator--0f6d1f9e-3e4e-4f6b-9a0c-1b5d1c8d9e22", rejected[1].Ref)
+	assert.Equal(t, "relationship--3", rejected[2].Ref)
+	assert.Equal(t, "malware--c7a4f5d2-1c3e-4a5f-8f77-0e8a7c9d4b33", rejected[3].Ref)
+}
+
+func TestParseBundle_NotABundle(t *testing.T) {
+	_, _, err := ParseBundle([]byte(`{"type": "indicator"}`))
+	assert.Error(t, err)
+}
+
+func TestBuildBundle_PreservesImportedIDs(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := &model.IntelBundle{
+		Indicators: []model.Indicator{{
+			ID: "ind-1", Type: model.IndicatorTypeDomain, Value: "evil.example.com",
+			Metadata:  json.RawMessage(`{"stix_id": "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"}`),
+			CreatedAt: now, UpdatedAt: now,
+		}},
+		ThreatActors: []model.ThreatActor{{
+			ID: "actor-1", Name: "APT Test",
+			Metadata:  json.RawMessage(`{"stix_id": "intrusion-set--4e78f46f-a023-4e5f-bc24-71b3ca22ec29"}`),
+			CreatedAt: now, UpdatedAt: now,
+		}},
+		IndicatorActors: []model.IndicatorActorLink{
+			{IndicatorID: "ind-1", ActorID: "actor-1", AttributionConfidence: 70, AddedAt: now},
+		},
+	}
+
+	bundle := BuildBundle(data)
+
+	require.Len(t, bundle.Objects, 3)
+	assert.Equal(t, "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f", bundle.Objects[0].(*Indicator).ID)
+	actor := bundle.Objects[1].(*ThreatActor)
+	assert.Equal(t, TypeIntrusionSet, actor.Type)
+	assert.Equal(t, "intrusion-set--4e78f46f-a023-4e5f-bc24-71b3ca22ec29", actor.ID)
+	rel := bundle.Objects[2].(*Relationship)
+	assert.Equal(t, "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f", rel.SourceRef)
+	assert.Equal(t, actor.ID, rel.TargetRef)
+}
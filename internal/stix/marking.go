@@ -0,0 +1,76 @@
+package stix
+
+import (
+	"strings"
+	"time"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/marking"
+)
+
+var tlpDefinitionCreated = time.Date(2017, time.January, 20, 0, 0, 0, 0, time.UTC)
+
+var tlpMarkingRefs = map[marking.Level]string{
+	marking.Clear: "marking-definition--613f2e26-407d-48c7-9eca-b8e91df99dc9",
+	marking.Green: "marking-definition--34098fce-860f-48ae-8e50-ebd3cc5e41da",
+	marking.Amber: "marking-definition--f88d31f6-486f-44da-b317-01333bde0b82",
+	marking.Red:   "marking-definition--5e57c739-391a-4eb3-b6be-7d15ca92d5ed",
+}
+
+var tlpMarkingLevels = map[string]marking.Level{
+	"marking-definition--613f2e26-407d-48c7-9eca-b8e91df99dc9": marking.Clear,
+	"marking-definition--34098fce-860f-48ae-8e50-ebd3cc5e41da": marking.Green,
+	"marking-definition--f88d31f6-486f-44da-b317-01333bde0b82": marking.Amber,
+	"marking-definition--5e57c739-391a-4eb3-b6be-7d15ca92d5ed": marking.Red,
+	// TLP 2.0 definitions published as a STIX extension.
+	"marking-definition--94868c89-83c2-464b-929b-a1a8aa3c8487": marking.Clear,
+	"marking-definition--bab4a63c-aed9-4cf5-a766-dfca5abac2bb": marking.Green,
+	"marking-definition--55d920b0-5e8b-4f79-9ee9-91f868d9b421": marking.Amber,
+	"marking-definition--939a9414-2ddd-4d32-a0cd-375ea402b003": marking.Amber,
+	"marking-definition--e828b379-4e03-4974-9ac4-e53a884c97c1": marking.Red,
+}
+
+func TLPMarkingRef(tlp string) string {
+	level, ok := marking.Parse(tlp)
+	if !ok {
+		return ""
+	}
+	return tlpMarkingRefs[level]
+}
+
+func TLPMarkingDefinition(tlp string) *MarkingDefinition {
+	level, ok := marking.Parse(tlp)
+	if !ok {
+		return nil
+	}
+
+	name := "white"
+	if level != marking.Clear {
+		name = string(level)
+	}
+	return &MarkingDefinition{
+		Type:           TypeMarkingDefinition,
+		SpecVersion:    SpecVersion,
+		ID:             tlpMarkingRefs[level],
+		Created:        NewTimestamp(tlpDefinitionCreated),
+		DefinitionType: "tlp",
+		Name:           "TLP:" + strings.ToUpper(name),
+		Definition:     map[string]string{"tlp": name},
+	}
+}
+
+func TLPFromMarkingRefs(refs []string) string {
+	var tlp marking.Level
+	for _, ref := range refs {
+		if level, ok := tlpMarkingLevels[ref]; ok {
+			tlp = marking.Max(tlp, level)
+		}
+	}
+	return string(tlp)
+}
+
+func markingRefs(tlp string) []string {
+	if ref := TLPMarkingRef(tlp); ref != "" {
+		return []string{ref}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package stix
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/LorenzattiGabriel/threat-intel-api/internal/model"
+)
+
+// hashAlgorithmNames maps hash_algo to the STIX hash-algorithm-ov name. An
+// imphash has no entry there and lives on the PE binary extension instead.
+var hashAlgorithmNames = map[string]string{
+	model.HashAlgoMD5:    "MD5",
+	model.HashAlgoSHA1:   "SHA-1",
+	model.HashAlgoSHA256: "SHA-256",
+	model.HashAlgoSHA512: "SHA-512",
+	model.HashAlgoSSDEEP: "SSDEEP",
+	model.HashAlgoTLSH:   "TLSH",
+}
+
+const imphashPath = "extensions.'windows-pebinary-ext'.imphash"
+
+var (
+	comparisonPattern = regexp.MustCompile(`^\[\s*([a-z0-9-]+):([A-Za-z0-9_.'-]+)\s*=\s*'((?:[^'\\]|\\.)*)'\s*\]$`)
+	hashPathPattern   = regexp.MustCompile(`^hashes\.'?([A-Za-z0-9-]+)'?$`)
+)
+
+var patternObjectTypes = map[string]model.IndicatorType{
+	"ipv4-addr":   model.IndicatorTypeIP,
+	"ipv6-addr":   model.IndicatorTypeIP,
+	"domain-name": model.IndicatorTypeDomain,
+	"url":         model.IndicatorTypeURL,
+}
+
+// observablePaths holds the other types that map onto a single observable
+// property. ASNs, CVEs, JA3/JA4 fingerprints and file paths have no such
+// property and are left out of STIX exports.
+var observablePaths = map[model.IndicatorType]string{
+	model.IndicatorTypeEmail:       "email-addr:value",
+	model.IndicatorTypeMutex:       "mutex:name",
+	model.IndicatorTypeRegistryKey: "windows-registry-key:key",
+	model.IndicatorTypeUserAgent:   "network-traffic:extensions.'http-request-ext'.request_header.'User-Agent'",
+}
+
+var hashAlgorithms = map[string]string{
+	"MD5":     model.HashAlgoMD5,
+	"SHA-1":   model.HashAlgoSHA1,
+	"SHA1":    model.HashAlgoSHA1,
+	"SHA-256": model.HashAlgoSHA256,
+	"SHA256":  model.HashAlgoSHA256,
+	"SHA-512": model.HashAlgoSHA512,
+	"SHA512":  model.HashAlgoSHA512,
+	"SSDEEP":  model.HashAlgoSSDEEP,
+	"TLSH":    model.HashAlgoTLSH,
+}
+
+// Pattern builds the STIX pattern for an indicator. hashAlgo is only used for
+// hashes and is detected from the value when empty.
+func Pattern(indicatorType model.IndicatorType, value, hashAlgo string) (string, error) {
+	switch indicatorType {
+	case model.IndicatorTypeIP:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			cidrIP, _, err := net.ParseCIDR(value)
+			if err != nil {
+				return "", fmt.Errorf("invalid ip value %q", value)
+			}
+			ip = cidrIP
+		}
+		if ip.To4() != nil {
+			return comparison("ipv4-addr:value", value), nil
+		}
+		return comparison("ipv6-addr:value", value), nil
+	case model.IndicatorTypeDomain:
+		return comparison("domain-name:value", value), nil
+	case model.IndicatorTypeURL:
+		return comparison("url:value", value), nil
+	case model.IndicatorTypeHash:
+		if hashAlgo == "" {
+			hashAlgo = model.DetectHashAlgo(value)
+		}
+		if hashAlgo == model.HashAlgoImphash {
+			return comparison("file:"+imphashPath, strings.ToLower(value)), nil
+		}
+		algorithm, ok := hashAlgorithmNames[hashAlgo]
+		if !ok {
+			return "", fmt.Errorf("unsupported hash value %q", value)
+		}
+		return comparison("file:hashes.'"+algorithm+"'", hashValue(hashAlgo, value)), nil
+	}
+
+	if path, ok := observablePaths[indicatorType]; ok {
+		return comparison(path, value), nil
+	}
+
+	return "", fmt.Errorf("unsupported indicator type %q", indicatorType)
+}
+
+// ssdeep digests are base64 and case-sensitive; every other algorithm is hex.
+func hashValue(hashAlgo, value string) string {
+	if hashAlgo == model.HashAlgoSSDEEP {
+		return value
+	}
+	return strings.ToLower(value)
+}
+
+func comparison(path, value string) string {
+	return "[" + path + " = '" + escapeString(value) + "']"
+}
+
+func escapeString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, `'`, `\'`)
+}
+
+// ParsePattern returns the indicator type and value of a single comparison
+// pattern, plus the hash algorithm when the pattern is a file hash.
+func ParsePattern(pattern string) (indicatorType model.IndicatorType, value, hashAlgo string, err error) {
+	match := comparisonPattern.FindStringSubmatch(strings.TrimSpace(pattern))
+	if match == nil {
+		return "", "", "", fmt.Errorf("unsupported pattern %q: only single equality comparisons are supported", pattern)
+	}
+
+	objectType, path, value := match[1], match[2], unescapeString(match[3])
+
+	if objectType == "file" {
+		if path == imphashPath {
+			return model.IndicatorTypeHash, strings.ToLower(value), model.HashAlgoImphash, nil
+		}
+		hash := hashPathPattern.FindStringSubmatch(path)
+		if hash == nil {
+			return "", "", "", fmt.Errorf("unsupported file property %q", path)
+		}
+		hashAlgo, ok := hashAlgorithms[strings.ToUpper(hash[1])]
+		if !ok {
+			return "", "", "", fmt.Errorf("unsupported file property %q", path)
+		}
+		return model.IndicatorTypeHash, hashValue(hashAlgo, value), hashAlgo, nil
+	}
+
+	for observableType, observablePath := range observablePaths {
+		if objectType+":"+path == observablePath {
+			return observableType, value, "", nil
+		}
+	}
+
+	indicatorType, ok := patternObjectTypes[objectType]
+	if !ok {
+		return "", "", "", fmt.Errorf("unsupported object type %q", objectType)
+	}
+	if path != "value" {
+		return "", "", "", fmt.Errorf("unsupported %s property %q", objectType, path)
+	}
+
+	return indicatorType, value, "", nil
+}
+
+func unescapeString(value string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range value {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
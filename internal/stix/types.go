@@ -0,0 +1,112 @@
+package stix
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	SpecVersion = "2.1"
+	MediaType   = "application/stix+json;version=2.1"
+
+	TypeBundle       = "bundle"
+	TypeIndicator    = "indicator"
+	TypeCampaign     = "campaign"
+	TypeThreatActor  = "threat-actor"
+	TypeIntrusionSet = "intrusion-set"
+	TypeRelationship = "relationship"
+
+	TypeMarkingDefinition = "marking-definition"
+
+	RelationshipIndicates    = "indicates"
+	RelationshipAttributedTo = "attributed-to"
+)
+
+const timestampLayout = "2006-01-02T15:04:05.000Z"
+
+type Timestamp struct {
+	time.Time
+}
+
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t.UTC()}
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + t.UTC().Format(timestampLayout) + `"`), nil
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	parsed, err := time.Parse(time.RFC3339Nano, strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	t.Time = parsed.UTC()
+	return nil
+}
+
+type Bundle struct {
+	Type    string        `json:"type"`
+	ID      string        `json:"id"`
+	Objects []interface{} `json:"objects"`
+}
+
+type Common struct {
+	Type        string    `json:"type"`
+	SpecVersion string    `json:"spec_version"`
+	ID          string    `json:"id"`
+	Created     Timestamp `json:"created"`
+	Modified    Timestamp `json:"modified"`
+	Confidence  *int      `json:"confidence,omitempty"`
+	Labels      []string  `json:"labels,omitempty"`
+	Revoked     bool      `json:"revoked,omitempty"`
+
+	ObjectMarkingRefs []string `json:"object_marking_refs,omitempty"`
+}
+
+type Indicator struct {
+	Common
+	Name           string     `json:"name,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	IndicatorTypes []string   `json:"indicator_types,omitempty"`
+	Pattern        string     `json:"pattern"`
+	PatternType    string     `json:"pattern_type"`
+	ValidFrom      Timestamp  `json:"valid_from"`
+	ValidUntil     *Timestamp `json:"valid_until,omitempty"`
+}
+
+type Campaign struct {
+	Common
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	FirstSeen   *Timestamp `json:"first_seen,omitempty"`
+	LastSeen    *Timestamp `json:"last_seen,omitempty"`
+}
+
+type ThreatActor struct {
+	Common
+	Name              string     `json:"name"`
+	Description       string     `json:"description,omitempty"`
+	ThreatActorTypes  []string   `json:"threat_actor_types,omitempty"`
+	Aliases           []string   `json:"aliases,omitempty"`
+	FirstSeen         *Timestamp `json:"first_seen,omitempty"`
+	LastSeen          *Timestamp `json:"last_seen,omitempty"`
+	PrimaryMotivation string     `json:"primary_motivation,omitempty"`
+}
+
+type MarkingDefinition struct {
+	Type           string            `json:"type"`
+	SpecVersion    string            `json:"spec_version"`
+	ID             string            `json:"id"`
+	Created        Timestamp         `json:"created"`
+	DefinitionType string            `json:"definition_type"`
+	Name           string            `json:"name"`
+	Definition     map[string]string `json:"definition"`
+}
+
+type Relationship struct {
+	Common
+	RelationshipType string `json:"relationship_type"`
+	SourceRef        string `json:"source_ref"`
+	TargetRef        string `json:"target_ref"`
+}
@@ -0,0 +1,27 @@
+package taxii
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	dateAdded := time.Date(2024, 3, 1, 12, 30, 45, 123456000, time.UTC)
+	id := "550e8400-e29b-41d4-a716-446655440000"
+
+	decodedDate, decodedID, err := DecodeCursor(EncodeCursor(dateAdded, id))
+
+	require.NoError(t, err)
+	assert.True(t, dateAdded.Equal(decodedDate))
+	assert.Equal(t, id, decodedID)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", EncodeCursor(time.Now(), "not-a-uuid")} {
+		_, _, err := DecodeCursor(cursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
@@ -0,0 +1,23 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, Default, FromContext(WithID(context.Background(), "")))
+	assert.Equal(t, "soc-emea", FromContext(WithID(context.Background(), "soc-emea")))
+}
+
+func TestIsValidID(t *testing.T) {
+	for _, id := range []string{"default", "soc-emea", "bu_42"} {
+		assert.True(t, IsValidID(id), id)
+	}
+	for _, id := range []string{"", "SOC", "-soc", "soc emea", "soc:emea"} {
+		assert.False(t, IsValidID(id), id)
+	}
+}
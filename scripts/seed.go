@@ -106,7 +106,7 @@ func seedThreatActors(db *sql.DB, count int) ([]string, error) {
 		err := db.QueryRow(`
 			INSERT INTO threat_actors (id, name, description, country, motivation, first_seen, last_seen, confidence_level)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			ON CONFLICT (tenant_id, name) DO UPDATE SET name = EXCLUDED.name
 			RETURNING id
 		`,
 			id,
@@ -179,9 +179,12 @@ func seedIndicators(db *sql.DB, count int) ([]string, error) {
 		firstSeen := randomTime(365)
 		lastSeen := randomTimeBetween(firstSeen, time.Now())
 
-		_, err := db.Exec(`
+		var insertedID string
+		err := db.QueryRow(`
 			INSERT INTO indicators (id, type, value, description, severity, confidence, first_seen, last_seen, is_active, source)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (tenant_id, type, normalized_value) DO NOTHING
+			RETURNING id
 		`,
 			id,
 			indType,
@@ -193,11 +196,14 @@ func seedIndicators(db *sql.DB, count int) ([]string, error) {
 			lastSeen,
 			rand.Float32() > 0.2,
 			randomChoice(sources),
-		)
+		).Scan(&insertedID)
+		if err == sql.ErrNoRows {
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
-		ids = append(ids, id)
+		ids = append(ids, insertedID)
 
 		if (i+1)%1000 == 0 {
 			log.Printf("Created %d indicators...", i+1)